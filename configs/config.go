@@ -33,6 +33,19 @@ type Config struct {
 	MaxConcurrentCrawls int
 	CrawlTimeout        time.Duration
 	UserAgent           string
+	SiteCrawlMaxDepth   int      // Cap on link depth followed in site-crawl mode
+	SiteCrawlMaxPages   int      // Cap on pages analyzed per site crawl
+	HostConcurrency     int      // Requests in flight to one host across all crawlers
+	HostRPS             float64  // Requests per second to one host across all crawlers
+	MaxBodyBytes        int64    // Page bytes parsed per fetch; 0 means no cap
+	BlockPrivateIPs     bool     // Refuse to crawl internal addresses
+	AllowedHosts        []string // Hosts, IPs and CIDR ranges exempt from BlockPrivateIPs
+	QueueLease          time.Duration
+	QueuePollInterval   time.Duration
+	QueueMaxAttempts    int
+	SchedulerTick       time.Duration // How often due schedules are checked
+	WebhookMaxAttempts  int           // Delivery attempts before a webhook delivery fails
+	WebhookTimeout      time.Duration // Per delivery attempt
 }
 
 // Load reads configuration exclusively from environment variables (optionally .env file).
@@ -107,6 +120,103 @@ func Load() (*Config, error) {
 	}
 	cfg.CrawlTimeout = time.Duration(ts) * time.Second
 
+	maxDepth := getEnv("SITE_CRAWL_MAX_DEPTH", "3")
+	md, err := strconv.Atoi(maxDepth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SITE_CRAWL_MAX_DEPTH: %w", err)
+	}
+	cfg.SiteCrawlMaxDepth = md
+
+	maxPages := getEnv("SITE_CRAWL_MAX_PAGES", "100")
+	mp, err := strconv.Atoi(maxPages)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SITE_CRAWL_MAX_PAGES: %w", err)
+	}
+	cfg.SiteCrawlMaxPages = mp
+
+	// Politeness towards crawled hosts
+	hostConc := getEnv("CRAWL_HOST_CONCURRENCY", "2")
+	hc, err := strconv.Atoi(hostConc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CRAWL_HOST_CONCURRENCY: %w", err)
+	}
+	cfg.HostConcurrency = hc
+
+	hostRPS := getEnv("CRAWL_HOST_RPS", "2")
+	hr, err := strconv.ParseFloat(hostRPS, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CRAWL_HOST_RPS: %w", err)
+	}
+	cfg.HostRPS = hr
+
+	maxBody := getEnv("CRAWL_MAX_BODY_BYTES", "5242880")
+	mb, err := strconv.ParseInt(maxBody, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CRAWL_MAX_BODY_BYTES: %w", err)
+	}
+	cfg.MaxBodyBytes = mb
+
+	// Network guard
+	blockPrivate := getEnv("CRAWL_BLOCK_PRIVATE_NETWORKS", "true")
+	bp, err := strconv.ParseBool(blockPrivate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CRAWL_BLOCK_PRIVATE_NETWORKS: %w", err)
+	}
+	cfg.BlockPrivateIPs = bp
+
+	if allowed := getEnv("CRAWL_ALLOWED_HOSTS", ""); allowed != "" {
+		for _, h := range strings.Split(allowed, ",") {
+			if h = strings.TrimSpace(h); h != "" {
+				cfg.AllowedHosts = append(cfg.AllowedHosts, h)
+			}
+		}
+	}
+
+	// Durable crawl queue
+	leaseSec := getEnv("QUEUE_LEASE_SECONDS", "120")
+	ls, err := strconv.Atoi(leaseSec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUEUE_LEASE_SECONDS: %w", err)
+	}
+	cfg.QueueLease = time.Duration(ls) * time.Second
+
+	pollMs := getEnv("QUEUE_POLL_INTERVAL_MS", "1000")
+	pm, err := strconv.Atoi(pollMs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUEUE_POLL_INTERVAL_MS: %w", err)
+	}
+	cfg.QueuePollInterval = time.Duration(pm) * time.Millisecond
+
+	maxAttempts := getEnv("QUEUE_MAX_ATTEMPTS", "3")
+	ma, err := strconv.Atoi(maxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid QUEUE_MAX_ATTEMPTS: %w", err)
+	}
+	cfg.QueueMaxAttempts = ma
+
+	// Scheduler
+	tickSec := getEnv("SCHEDULER_TICK_SECONDS", "30")
+	tk, err := strconv.Atoi(tickSec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SCHEDULER_TICK_SECONDS: %w", err)
+	}
+	cfg.SchedulerTick = time.Duration(tk) * time.Second
+
+	// Webhooks
+	hookAttempts := getEnv("WEBHOOK_MAX_ATTEMPTS", "5")
+	ha, err := strconv.Atoi(hookAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WEBHOOK_MAX_ATTEMPTS: %w", err)
+	}
+	cfg.WebhookMaxAttempts = ha
+
+	hookTimeout := getEnv("WEBHOOK_TIMEOUT_SECONDS", "10")
+	ht, err := strconv.Atoi(hookTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WEBHOOK_TIMEOUT_SECONDS: %w", err)
+	}
+	cfg.WebhookTimeout = time.Duration(ht) * time.Second
+
 	// User agent
 	cfg.UserAgent = getEnv("USER_AGENT", "URLInsight-Bot/1.0")
 
@@ -15,6 +15,55 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/certificates/expiring": {
+            "get": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Returns the current user's URLs whose latest analysis saw a TLS certificate expiring within the given number of days, including already expired ones.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "certificates"
+                ],
+                "summary": "List certificates expiring soon",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 30,
+                        "description": "window in days",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ExpiringCertificateDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/health": {
             "get": {
                 "description": "Get the status of server and database connection",
@@ -222,6 +271,51 @@ const docTemplate = `{
                 }
             }
         },
+        "/schedules": {
+            "get": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "List schedules with next-run times (paginated)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "example": 10,
+                        "description": "page_size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated schedule list",
+                        "schema": {
+                            "$ref": "#/definitions/model.PaginatedResponse-model_ScheduleDTO"
+                        }
+                    }
+                }
+            }
+        },
         "/status": {
             "get": {
                 "description": "Returns a welcome message and service status",
@@ -280,12 +374,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Paginated URL list",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.URLDTO"
-                            }
+                            "$ref": "#/definitions/model.PaginatedResponse-model_URLDTO"
                         }
                     }
                 }
@@ -342,8 +433,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/urls/{id}": {
-            "get": {
+        "/urls/bulk": {
+            "post": {
                 "security": [
                     {
                         "JWTAuth": []
@@ -352,32 +443,105 @@ const docTemplate = `{
                         "BasicAuth": []
                     }
                 ],
+                "description": "Creates many URLs at once, reporting each row's outcome. The body is one of: JSON (model.BulkImportRequestDTO) with a list of URLs and/or a sitemap_url to read; a CSV file (text/csv), using the \"url\" or \"original_url\" column if there is a header and the first column otherwise; a plain-text list (text/plain), one URL per line with # comments; a sitemap.xml (application/xml); or a multipart form with any of these as \"file\". For the non-JSON forms the shared options come from query or form fields. Entries are trimmed, given https:// when they have no scheme and normalized; duplicates within the import are reported. At most 1000 URLs per import.",
+                "consumes": [
+                    "application/json",
+                    "text/csv",
+                    "text/plain",
+                    "application/xml",
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "urls"
                 ],
-                "summary": "Get one URL row",
+                "summary": "Bulk import URLs",
                 "parameters": [
+                    {
+                        "description": "URLs (JSON form)",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkImportRequestDTO"
+                        }
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV, text list or sitemap (multipart form)",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "page",
+                            "site"
+                        ],
+                        "type": "string",
+                        "description": "crawl mode for every URL (non-JSON forms)",
+                        "name": "crawl_mode",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "URL ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "max depth for every URL (non-JSON forms)",
+                        "name": "max_depth",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max pages for every URL (non-JSON forms)",
+                        "name": "max_pages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "analysis modules for every URL (non-JSON forms)",
+                        "name": "modules",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "queue every created URL for analysis (non-JSON forms)",
+                        "name": "start",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.URLDTO"
+                            "$ref": "#/definitions/model.BulkImportResultDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/urls/bulk/delete": {
+            "post": {
                 "security": [
                     {
                         "JWTAuth": []
@@ -386,6 +550,7 @@ const docTemplate = `{
                         "BasicAuth": []
                     }
                 ],
+                "description": "Deletes the URLs selected by ids or by filter.",
                 "consumes": [
                     "application/json"
                 ],
@@ -395,28 +560,27 @@ const docTemplate = `{
                 "tags": [
                     "urls"
                 ],
-                "summary": "Update URL row",
+                "summary": "Bulk delete URLs",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "URL ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "fields",
+                        "description": "selection",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UpdateURLInput"
+                            "$ref": "#/definitions/model.BulkActionRequestDTO"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "updated",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkActionResultDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -425,8 +589,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/urls/bulk/start": {
+            "post": {
                 "security": [
                     {
                         "JWTAuth": []
@@ -435,25 +601,37 @@ const docTemplate = `{
                         "BasicAuth": []
                     }
                 ],
+                "description": "Queues analysis for the URLs selected by ids or by filter (status, crawl_mode, q), at most 1000.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "urls"
                 ],
-                "summary": "Delete URL row",
+                "summary": "Bulk start crawls",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "URL ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "selection",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkActionRequestDTO"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "deleted",
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkActionResultDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -464,8 +642,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/urls/{id}/results": {
-            "get": {
+        "/urls/bulk/stop": {
+            "post": {
                 "security": [
                     {
                         "JWTAuth": []
@@ -474,40 +652,37 @@ const docTemplate = `{
                         "BasicAuth": []
                     }
                 ],
+                "description": "Stops the URLs selected by ids or by filter. Partial results are discarded unless keep_partial is true.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "urls"
                 ],
-                "summary": "Latest analysis snapshot + links",
+                "summary": "Bulk stop crawls",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "URL ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "selection",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkActionRequestDTO"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/model.URLResultsDTO"
+                            "$ref": "#/definitions/model.BulkActionResultDTO"
                         }
                     },
                     "400": {
-                        "description": "bad request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "not found",
+                        "description": "error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -518,8 +693,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/urls/{id}/start": {
-            "patch": {
+        "/urls/export": {
+            "get": {
                 "security": [
                     {
                         "JWTAuth": []
@@ -528,25 +703,39 @@ const docTemplate = `{
                         "BasicAuth": []
                     }
                 ],
+                "description": "Downloads every URL of the user with the metrics of its latest snapshot (status code, health, title, link counts, timing, certificate expiry). The export is streamed.",
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "urls"
+                    "export"
                 ],
-                "summary": "Start crawl",
+                "summary": "Export URLs",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "URL ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "file format",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "queued",
+                    "200": {
+                        "description": "export",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -557,8 +746,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/urls/{id}/stop": {
-            "patch": {
+        "/urls/{id}": {
+            "get": {
                 "security": [
                     {
                         "JWTAuth": []
@@ -573,7 +762,7 @@ const docTemplate = `{
                 "tags": [
                     "urls"
                 ],
-                "summary": "Stop crawl",
+                "summary": "Get one URL row",
                 "parameters": [
                     {
                         "type": "integer",
@@ -584,55 +773,2265 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "stopped",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.URLDTO"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "handler.LoginRequest": {
+            },
+            "put": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "Update URL row",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "fields",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateURLInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "updated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "Delete URL row",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/diff": {
+            "get": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Compares title, HTML version, heading counts and links between two snapshots. Without from/to the root pages of the two latest runs are compared.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "Diff two analysis snapshots",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "older snapshot ID",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "newer snapshot ID",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SnapshotDiffDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events stream for one URL. The first event carries the current status. \"status\" events follow every transition (queued, running, done, error, stopped); \"progress\" events report links checked so far on the page being analyzed and, in site mode, pages crawled. Data is a model.ProgressEvent as JSON. The stream stays open until the client disconnects.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "Stream URL status and progress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "event stream",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProgressEvent"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/export/links": {
+            "get": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Downloads the links recorded by one analysis run of a URL: the latest run, or the run containing snapshot. The export is streamed, so it suits link tables too large for the results endpoint.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export links",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "file format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "snapshot ID",
+                        "name": "snapshot",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "dns",
+                            "timeout",
+                            "tls",
+                            "connection_refused",
+                            "connection_reset",
+                            "unreachable",
+                            "blocked",
+                            "other"
+                        ],
+                        "type": "string",
+                        "description": "only links that failed with this error category, or any",
+                        "name": "link_error",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "export",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/export/snapshots": {
+            "get": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Downloads every analysis snapshot of a URL, oldest first, one row per page analyzed. The export is streamed.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export snapshots",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "file format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "export",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/results": {
+            "get": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Returns one analysis run and the links it recorded. Defaults to the latest run; pass snapshot to get the run containing that snapshot.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "Latest analysis snapshot + links (per-page results and site summary in site mode)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "snapshot ID",
+                        "name": "snapshot",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "dns",
+                            "timeout",
+                            "tls",
+                            "connection_refused",
+                            "connection_reset",
+                            "unreachable",
+                            "blocked",
+                            "other"
+                        ],
+                        "type": "string",
+                        "description": "only links that failed with this error category, or any",
+                        "name": "link_error",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.URLResultsDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/schedule": {
+            "get": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Get schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduleDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Creates or replaces the URL's schedule. Give either a standard 5-field cron expression or a fixed interval (\u003e= 60s).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Set recurring schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "schedule",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduleInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduleDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Delete schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/schedule/pause": {
+            "patch": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Pause schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduleDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/schedule/resume": {
+            "patch": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedules"
+                ],
+                "summary": "Resume schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ScheduleDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/snapshots": {
+            "get": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "List analysis snapshots (paginated, newest first)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "example": 10,
+                        "description": "page_size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated snapshot list",
+                        "schema": {
+                            "$ref": "#/definitions/model.PaginatedResponse-model_AnalysisResultDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/start": {
+            "patch": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "Start crawl",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "queued",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/urls/{id}/stop": {
+            "patch": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Cancels a running analysis immediately. Partial results are discarded unless keep_partial is true.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "urls"
+                ],
+                "summary": "Stop crawl",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Persist results collected before the stop",
+                        "name": "keep_partial",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "stopped",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks (paginated)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "example": 10,
+                        "description": "page_size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated webhook list",
+                        "schema": {
+                            "$ref": "#/definitions/model.PaginatedResponse-model_WebhookDTO"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Registers an endpoint notified of analysis events (analysis.completed, analysis.failed, analysis.stopped, broken_links.detected). Each delivery is signed in the X-URLInsight-Signature header as \"sha256=\" + hex HMAC-SHA256 of \"\u003cX-URLInsight-Timestamp\u003e.\u003cbody\u003e\"; the secret is returned only in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Register webhook",
+                "parameters": [
+                    {
+                        "description": "webhook",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Replaces the webhook's URL and events; active is left unchanged when omitted. The secret is kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "webhook",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries (paginated, newest first)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "example": 10,
+                        "description": "page_size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated delivery log",
+                        "schema": {
+                            "$ref": "#/definitions/model.PaginatedResponse-model_WebhookDelivery"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "JWTAuth": []
+                    },
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Queues the delivery's payload again as a new delivery, sent right away.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Redeliver webhook delivery",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookDelivery"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AccessibilityFinding": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                },
+                "severity": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AccessibilityReport": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "integer"
+                },
+                "findings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AccessibilityFinding"
+                    }
+                },
+                "warnings": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.AnalysisResult": {
+            "type": "object",
+            "properties": {
+                "accessibility": {
+                    "$ref": "#/definitions/model.AccessibilityReport"
+                },
+                "assertions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AssertionResult"
+                    }
+                },
+                "body_truncated": {
+                    "type": "boolean"
+                },
+                "broken_link_count": {
+                    "type": "integer"
+                },
+                "cert_expires_at": {
+                    "type": "string"
+                },
+                "charset": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "depth": {
+                    "type": "integer"
+                },
+                "error_link_count": {
+                    "type": "integer"
+                },
+                "external_link_count": {
+                    "type": "integer"
+                },
+                "h1_count": {
+                    "type": "integer"
+                },
+                "h2_count": {
+                    "type": "integer"
+                },
+                "h3_count": {
+                    "type": "integer"
+                },
+                "h4_count": {
+                    "type": "integer"
+                },
+                "h5_count": {
+                    "type": "integer"
+                },
+                "h6_count": {
+                    "type": "integer"
+                },
+                "has_login_form": {
+                    "type": "boolean"
+                },
+                "health": {
+                    "type": "string"
+                },
+                "html_version": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "internal_link_count": {
+                    "type": "integer"
+                },
+                "links_incomplete": {
+                    "type": "boolean"
+                },
+                "modules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "page_url": {
+                    "type": "string"
+                },
+                "redirects": {
+                    "$ref": "#/definitions/model.RedirectChain"
+                },
+                "robots": {
+                    "$ref": "#/definitions/model.RobotsReport"
+                },
+                "security": {
+                    "$ref": "#/definitions/model.SecurityReport"
+                },
+                "seo": {
+                    "$ref": "#/definitions/model.SEOMetadata"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "structured_data": {
+                    "$ref": "#/definitions/model.StructuredData"
+                },
+                "timing": {
+                    "$ref": "#/definitions/model.PageTiming"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "tls": {
+                    "$ref": "#/definitions/model.TLSReport"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.AnalysisResultDTO": {
+            "type": "object",
+            "properties": {
+                "accessibility": {
+                    "$ref": "#/definitions/model.AccessibilityReport"
+                },
+                "assertions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AssertionResult"
+                    }
+                },
+                "body_truncated": {
+                    "type": "boolean"
+                },
+                "broken_link_count": {
+                    "type": "integer"
+                },
+                "cert_expires_at": {
+                    "type": "string"
+                },
+                "charset": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "depth": {
+                    "type": "integer"
+                },
+                "error_link_count": {
+                    "type": "integer"
+                },
+                "external_link_count": {
+                    "type": "integer"
+                },
+                "h1_count": {
+                    "type": "integer"
+                },
+                "h2_count": {
+                    "type": "integer"
+                },
+                "h3_count": {
+                    "type": "integer"
+                },
+                "h4_count": {
+                    "type": "integer"
+                },
+                "h5_count": {
+                    "type": "integer"
+                },
+                "h6_count": {
+                    "type": "integer"
+                },
+                "has_login_form": {
+                    "type": "boolean"
+                },
+                "health": {
+                    "type": "string"
+                },
+                "html_version": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "internal_link_count": {
+                    "type": "integer"
+                },
+                "links_incomplete": {
+                    "type": "boolean"
+                },
+                "modules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "page_url": {
+                    "type": "string"
+                },
+                "redirects": {
+                    "$ref": "#/definitions/model.RedirectChain"
+                },
+                "robots": {
+                    "$ref": "#/definitions/model.RobotsReport"
+                },
+                "security": {
+                    "$ref": "#/definitions/model.SecurityReport"
+                },
+                "seo": {
+                    "$ref": "#/definitions/model.SEOMetadata"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "structured_data": {
+                    "$ref": "#/definitions/model.StructuredData"
+                },
+                "timing": {
+                    "$ref": "#/definitions/model.PageTiming"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "tls": {
+                    "$ref": "#/definitions/model.TLSReport"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.AssertionResult": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "rule": {
+                    "$ref": "#/definitions/model.AssertionRule"
+                }
+            }
+        },
+        "model.AssertionRule": {
+            "type": "object",
+            "properties": {
+                "max": {
+                    "type": "integer"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "selector": {
+                    "type": "string",
+                    "example": "#checkout"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "selector_exists"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BulkActionRequestDTO": {
+            "type": "object",
+            "properties": {
+                "filter": {
+                    "$ref": "#/definitions/model.URLFilterDTO"
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                },
+                "keep_partial": {
+                    "description": "KeepPartial keeps the results collected before a stop; only used when stopping.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.BulkActionResultDTO": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "matched": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BulkItemResultDTO"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BulkImportRequestDTO": {
+            "type": "object",
+            "properties": {
+                "crawl_mode": {
+                    "type": "string",
+                    "enum": [
+                        "page",
+                        "site"
+                    ],
+                    "example": "page"
+                },
+                "max_depth": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "max_pages": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "modules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sitemap_url": {
+                    "description": "SitemapURL points at a sitemap.xml (or sitemap index) whose pages are imported.",
+                    "type": "string",
+                    "example": "https://example.com/sitemap.xml"
+                },
+                "start": {
+                    "description": "Start queues every created URL for analysis right away.",
+                    "type": "boolean"
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "https://example.com",
+                        "example.org/pricing"
+                    ]
+                }
+            }
+        },
+        "model.BulkImportResultDTO": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BulkRowResultDTO"
+                    }
+                },
+                "started": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BulkItemResultDTO": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BulkRowResultDTO": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "input": {
+                    "type": "string"
+                },
+                "row": {
+                    "description": "1-based position in the input",
+                    "type": "integer"
+                },
+                "url": {
+                    "description": "normalized form",
+                    "type": "string"
+                }
+            }
+        },
+        "model.CertificateInfo": {
+            "type": "object",
+            "properties": {
+                "dns_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "key_type": {
+                    "type": "string"
+                },
+                "not_after": {
+                    "type": "string"
+                },
+                "not_before": {
+                    "type": "string"
+                },
+                "serial_number": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CookieCheck": {
+            "type": "object",
+            "properties": {
+                "http_only": {
+                    "type": "boolean"
+                },
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "same_site": {
+                    "type": "string"
+                },
+                "secure": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.ExpiringCertificateDTO": {
+            "type": "object",
+            "properties": {
+                "days_left": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "original_url": {
+                    "type": "string"
+                },
+                "snapshot_id": {
+                    "type": "integer"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.FieldChangeDTO": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.HeaderCheck": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.HeadingDeltaDTO": {
+            "type": "object",
+            "properties": {
+                "h1": {
+                    "type": "integer"
+                },
+                "h2": {
+                    "type": "integer"
+                },
+                "h3": {
+                    "type": "integer"
+                },
+                "h4": {
+                    "type": "integer"
+                },
+                "h5": {
+                    "type": "integer"
+                },
+                "h6": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.HreflangLink": {
+            "type": "object",
+            "properties": {
+                "href": {
+                    "type": "string"
+                },
+                "lang": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Link": {
+            "type": "object",
+            "properties": {
+                "analysis_result_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error_category": {
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "href": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_external": {
+                    "type": "boolean"
+                },
+                "redirects": {
+                    "$ref": "#/definitions/model.RedirectChain"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "ttfb_ms": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.LinkDTO": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "analysis_result_id": {
+                    "type": "integer"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "password": {
+                "error_category": {
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "href": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_external": {
+                    "type": "boolean"
+                },
+                "redirects": {
+                    "$ref": "#/definitions/model.RedirectChain"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "ttfb_ms": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.LinkStatusChangeDTO": {
+            "type": "object",
+            "properties": {
+                "from_status": {
+                    "type": "integer"
+                },
+                "href": {
+                    "type": "string"
+                },
+                "to_error": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.PageTiming": {
+            "type": "object",
+            "properties": {
+                "connect_ms": {
+                    "type": "integer"
+                },
+                "dns_ms": {
+                    "type": "integer"
+                },
+                "download_ms": {
+                    "type": "integer"
+                },
+                "response_bytes": {
+                    "type": "integer"
+                },
+                "tls_ms": {
+                    "type": "integer"
+                },
+                "total_ms": {
+                    "type": "integer"
+                },
+                "ttfb_ms": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.PaginatedResponse-model_AnalysisResultDTO": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AnalysisResultDTO"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/model.PaginationMetaDTO"
+                }
+            }
+        },
+        "model.PaginatedResponse-model_ScheduleDTO": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ScheduleDTO"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/model.PaginationMetaDTO"
+                }
+            }
+        },
+        "model.PaginatedResponse-model_URLDTO": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.URLDTO"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/model.PaginationMetaDTO"
+                }
+            }
+        },
+        "model.PaginatedResponse-model_WebhookDTO": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WebhookDTO"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/model.PaginationMetaDTO"
+                }
+            }
+        },
+        "model.PaginatedResponse-model_WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WebhookDelivery"
+                    }
+                },
+                "pagination": {
+                    "$ref": "#/definitions/model.PaginationMetaDTO"
+                }
+            }
+        },
+        "model.PaginationMetaDTO": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "totalItems": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ProgressEvent": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "links_checked": {
+                    "description": "links of PageURL checked so far",
+                    "type": "integer"
+                },
+                "links_total": {
+                    "description": "links found on PageURL",
+                    "type": "integer"
+                },
+                "page_url": {
+                    "description": "page whose links are being checked",
+                    "type": "string"
+                },
+                "pages_crawled": {
+                    "description": "pages finished so far in a site crawl",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.RedirectChain": {
+            "type": "object",
+            "properties": {
+                "downgrade": {
+                    "type": "boolean"
+                },
+                "excessive": {
+                    "type": "boolean"
+                },
+                "final_url": {
+                    "type": "string"
+                },
+                "hops": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RedirectHop"
+                    }
+                },
+                "loop": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.RedirectHop": {
+            "type": "object",
+            "properties": {
+                "location": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RobotsReport": {
+            "type": "object",
+            "properties": {
+                "crawl_delay": {
+                    "description": "seconds",
+                    "type": "number"
+                },
+                "page_disallowed": {
+                    "type": "boolean"
+                },
+                "sitemaps": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "skipped_links": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.SEOMetadata": {
+            "type": "object",
+            "properties": {
+                "canonical": {
+                    "type": "string"
+                },
+                "hreflang": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.HreflangLink"
+                    }
+                },
+                "meta_description": {
+                    "type": "string"
+                },
+                "meta_robots": {
+                    "type": "string"
+                },
+                "open_graph": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "twitter_card": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "viewport": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ScheduleDTO": {
+            "type": "object",
+            "properties": {
+                "cron": {
+                    "type": "string"
+                },
+                "interval_seconds": {
+                    "type": "integer"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "original_url": {
                     "type": "string"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "url_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ScheduleInputDTO": {
+            "type": "object",
+            "properties": {
+                "cron": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "0 6 * * *"
+                },
+                "interval_seconds": {
+                    "type": "integer",
+                    "minimum": 60,
+                    "example": 86400
+                },
+                "paused": {
+                    "type": "boolean"
                 }
             }
         },
-        "handler.RegisterRequest": {
+        "model.SecurityReport": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "cookies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CookieCheck"
+                    }
                 },
-                "password": {
+                "grade": {
                     "type": "string"
                 },
-                "username": {
-                    "type": "string"
+                "headers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.HeaderCheck"
+                    }
+                },
+                "score": {
+                    "type": "integer"
                 }
             }
         },
-        "model.AnalysisResult": {
+        "model.SiteSummary": {
             "type": "object",
             "properties": {
                 "broken_link_count": {
@@ -641,73 +3040,150 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
-                "external_link_count": {
+                "error_link_count": {
                     "type": "integer"
                 },
-                "h1_count": {
+                "external_link_count": {
                     "type": "integer"
                 },
-                "h2_count": {
+                "id": {
                     "type": "integer"
                 },
-                "h3_count": {
+                "internal_link_count": {
                     "type": "integer"
                 },
-                "h4_count": {
+                "login_form_pages": {
                     "type": "integer"
                 },
-                "h5_count": {
+                "max_depth_reached": {
                     "type": "integer"
                 },
-                "h6_count": {
+                "pages_crawled": {
                     "type": "integer"
                 },
-                "has_login_form": {
-                    "type": "boolean"
+                "pages_failed": {
+                    "type": "integer"
                 },
-                "html_version": {
+                "updated_at": {
                     "type": "string"
                 },
-                "id": {
+                "url_id": {
                     "type": "integer"
+                }
+            }
+        },
+        "model.SnapshotDiffDTO": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "$ref": "#/definitions/model.AnalysisResultDTO"
                 },
-                "internal_link_count": {
-                    "type": "integer"
+                "headings": {
+                    "$ref": "#/definitions/model.HeadingDeltaDTO"
+                },
+                "html_version": {
+                    "$ref": "#/definitions/model.FieldChangeDTO"
+                },
+                "links_added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LinkDTO"
+                    }
+                },
+                "links_removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LinkDTO"
+                    }
+                },
+                "newly_broken": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.LinkStatusChangeDTO"
+                    }
+                },
+                "security_grade": {
+                    "$ref": "#/definitions/model.FieldChangeDTO"
                 },
                 "title": {
-                    "type": "string"
+                    "$ref": "#/definitions/model.FieldChangeDTO"
                 },
-                "updated_at": {
-                    "type": "string"
+                "to": {
+                    "$ref": "#/definitions/model.AnalysisResultDTO"
                 },
                 "url_id": {
                     "type": "integer"
                 }
             }
         },
-        "model.Link": {
+        "model.StructuredData": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StructuredDataError"
+                    }
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StructuredDataItem"
+                    }
+                }
+            }
+        },
+        "model.StructuredDataError": {
+            "type": "object",
+            "properties": {
+                "block": {
+                    "type": "integer"
+                },
+                "format": {
                     "type": "string"
                 },
-                "href": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.StructuredDataItem": {
+            "type": "object",
+            "properties": {
+                "format": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "properties": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "is_external": {
-                    "type": "boolean"
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TLSReport": {
+            "type": "object",
+            "properties": {
+                "chain": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CertificateInfo"
+                    }
                 },
-                "status_code": {
-                    "type": "integer"
+                "cipher_suite": {
+                    "type": "string"
                 },
-                "updated_at": {
+                "verified": {
+                    "type": "boolean"
+                },
+                "verify_error": {
                     "type": "string"
                 },
-                "url_id": {
-                    "type": "integer"
+                "version": {
+                    "type": "string"
                 }
             }
         },
@@ -717,24 +3193,105 @@ const docTemplate = `{
                 "original_url"
             ],
             "properties": {
+                "assertions": {
+                    "description": "Assertions are checked on every analysis and decide its health.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AssertionRule"
+                    }
+                },
+                "crawl_mode": {
+                    "type": "string",
+                    "enum": [
+                        "page",
+                        "site"
+                    ],
+                    "example": "page"
+                },
+                "max_depth": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2
+                },
+                "max_pages": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 50
+                },
+                "modules": {
+                    "description": "Modules selects the analysis modules to run; empty runs all of them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "headings",
+                        "seo"
+                    ]
+                },
                 "original_url": {
                     "type": "string",
                     "example": "https://example.com"
+                },
+                "request_cookies": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "request_headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 }
             }
         },
         "model.URLDTO": {
             "type": "object",
             "properties": {
+                "assertions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AssertionRule"
+                    }
+                },
+                "crawl_mode": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
+                "max_depth": {
+                    "type": "integer"
+                },
+                "max_pages": {
+                    "type": "integer"
+                },
+                "modules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "original_url": {
                     "type": "string"
                 },
+                "request_cookies": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "request_headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "status": {
                     "type": "string",
                     "enum": [
@@ -752,6 +3309,33 @@ const docTemplate = `{
                 }
             }
         },
+        "model.URLFilterDTO": {
+            "type": "object",
+            "properties": {
+                "crawl_mode": {
+                    "type": "string",
+                    "enum": [
+                        "page",
+                        "site"
+                    ]
+                },
+                "q": {
+                    "type": "string",
+                    "example": "example.com"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "queued",
+                        "running",
+                        "done",
+                        "error",
+                        "stopped"
+                    ],
+                    "example": "error"
+                }
+            }
+        },
         "model.URLResultsDTO": {
             "type": "object",
             "properties": {
@@ -767,6 +3351,9 @@ const docTemplate = `{
                         "$ref": "#/definitions/model.Link"
                     }
                 },
+                "site_summary": {
+                    "$ref": "#/definitions/model.SiteSummary"
+                },
                 "url": {
                     "$ref": "#/definitions/model.URLDTO"
                 }
@@ -775,9 +3362,51 @@ const docTemplate = `{
         "model.UpdateURLInput": {
             "type": "object",
             "properties": {
+                "assertions": {
+                    "description": "Assertions replace the stored rules when present; an empty list removes them.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AssertionRule"
+                    }
+                },
+                "crawl_mode": {
+                    "type": "string",
+                    "enum": [
+                        "page",
+                        "site"
+                    ]
+                },
+                "max_depth": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "max_pages": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "modules": {
+                    "description": "Modules replace the stored selection when present; an empty list selects all modules.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "original_url": {
                     "type": "string"
                 },
+                "request_cookies": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "request_headers": {
+                    "description": "Request headers and cookies replace the stored ones when present; an empty object clears them.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "status": {
                     "type": "string",
                     "enum": [
@@ -788,6 +3417,104 @@ const docTemplate = `{
                     ]
                 }
             }
+        },
+        "model.WebhookDTO": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "response_code": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.WebhookInputDTO": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "analysis.completed",
+                        "broken_links.detected"
+                    ]
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048,
+                    "example": "https://ci.example.com/hooks/urlinsight"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,181 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+func init() {
+	Register(NewModule(ModuleAccessibility, func(_ context.Context, p *Page, res *model.AnalysisResult) {
+		res.Accessibility = auditAccessibility(p.Doc)
+	}))
+}
+
+// Accessibility rule identifiers.
+const (
+	ruleImageAlt    = "image-alt"
+	ruleLabel       = "label"
+	ruleHTMLLang    = "html-lang"
+	ruleHeadingSkip = "heading-order"
+	ruleLinkName    = "link-name"
+	ruleButtonName  = "button-name"
+	ruleDuplicateID = "duplicate-id"
+	ruleLandmarks   = "landmarks"
+)
+
+// auditAccessibility runs the static accessibility checks over the document.
+func auditAccessibility(doc *goquery.Document) *model.AccessibilityReport {
+	r := &model.AccessibilityReport{Findings: []model.AccessibilityFinding{}}
+	add := func(rule, severity string, s *goquery.Selection, msg string) {
+		r.Add(model.AccessibilityFinding{Rule: rule, Severity: severity, Path: cssPath(s), Message: msg})
+	}
+
+	root := doc.Find("html").First()
+	if strings.TrimSpace(root.AttrOr("lang", "")) == "" {
+		add(ruleHTMLLang, model.SeverityError, root, "<html> element has no lang attribute")
+	}
+
+	doc.Find("img, input[type='image']").Each(func(_ int, s *goquery.Selection) {
+		if _, ok := s.Attr("alt"); !ok && !hasAriaName(s) {
+			add(ruleImageAlt, model.SeverityError, s, "image has no alt text")
+		}
+	})
+
+	labelled := map[string]bool{}
+	doc.Find("label[for]").Each(func(_ int, s *goquery.Selection) {
+		labelled[s.AttrOr("for", "")] = true
+	})
+	doc.Find("input, select, textarea").Each(func(_ int, s *goquery.Selection) {
+		switch strings.ToLower(s.AttrOr("type", "")) {
+		case "hidden", "submit", "button", "reset", "image":
+			return
+		}
+		id := s.AttrOr("id", "")
+		if (id != "" && labelled[id]) || s.Closest("label").Length() > 0 || hasAriaName(s) {
+			return
+		}
+		add(ruleLabel, model.SeverityError, s, "form control has no associated label")
+	})
+
+	prev := 0
+	doc.Find("h1,h2,h3,h4,h5,h6").Each(func(_ int, s *goquery.Selection) {
+		level := int(goquery.NodeName(s)[1] - '0')
+		if prev > 0 && level > prev+1 {
+			add(ruleHeadingSkip, model.SeverityWarning, s, fmt.Sprintf("heading level jumps from h%d to h%d", prev, level))
+		}
+		prev = level
+	})
+
+	doc.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+		if !hasAccessibleName(s) {
+			add(ruleLinkName, model.SeverityError, s, "link has no discernible text")
+		}
+	})
+	doc.Find("button, input[type='button'], input[type='submit'], input[type='reset']").Each(func(_ int, s *goquery.Selection) {
+		if goquery.NodeName(s) == "input" {
+			// Submit and reset buttons get a default label from the browser.
+			t := strings.ToLower(s.AttrOr("type", ""))
+			if strings.TrimSpace(s.AttrOr("value", "")) != "" || t == "submit" || t == "reset" || hasAriaName(s) {
+				return
+			}
+		} else if hasAccessibleName(s) {
+			return
+		}
+		add(ruleButtonName, model.SeverityError, s, "button has no discernible text")
+	})
+
+	ids := map[string]bool{}
+	doc.Find("[id]").Each(func(_ int, s *goquery.Selection) {
+		id := s.AttrOr("id", "")
+		if id == "" {
+			return
+		}
+		if ids[id] {
+			add(ruleDuplicateID, model.SeverityError, s, fmt.Sprintf("id %q is used more than once", id))
+		}
+		ids[id] = true
+	})
+
+	if doc.Find("main, [role='main']").Length() == 0 {
+		add(ruleLandmarks, model.SeverityWarning, doc.Find("body").First(), "page has no main landmark")
+	}
+	return r
+}
+
+// hasAriaName reports whether the element is named through ARIA or a title.
+func hasAriaName(s *goquery.Selection) bool {
+	for _, attr := range []string{"aria-label", "aria-labelledby", "title"} {
+		if strings.TrimSpace(s.AttrOr(attr, "")) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAccessibleName reports whether a link or button has text, an ARIA name or a described image.
+func hasAccessibleName(s *goquery.Selection) bool {
+	if strings.TrimSpace(s.Text()) != "" || hasAriaName(s) {
+		return true
+	}
+	named := false
+	s.Find("img[alt]").EachWithBreak(func(_ int, img *goquery.Selection) bool {
+		named = strings.TrimSpace(img.AttrOr("alt", "")) != ""
+		return !named
+	})
+	return named
+}
+
+// cssPath builds a selector from the root to the element, stopping early at an element with an id.
+func cssPath(s *goquery.Selection) string {
+	if s.Length() == 0 {
+		return ""
+	}
+	var parts []string
+	for n := s.Get(0); n != nil && n.Type == html.ElementNode; n = n.Parent {
+		part := n.Data
+		if id := attr(n, "id"); id != "" {
+			parts = append(parts, part+"#"+id)
+			break
+		}
+		if idx, total := typeIndex(n); total > 1 {
+			part += fmt.Sprintf(":nth-of-type(%d)", idx)
+		}
+		parts = append(parts, part)
+	}
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, " > ")
+}
+
+// typeIndex returns the 1-based position of n among its same-tag siblings and their count.
+func typeIndex(n *html.Node) (idx, total int) {
+	if n.Parent == nil {
+		return 1, 1
+	}
+	for c := n.Parent.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.Data != n.Data {
+			continue
+		}
+		total++
+		if c == n {
+			idx = total
+		}
+	}
+	return idx, total
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
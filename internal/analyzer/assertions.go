@@ -0,0 +1,136 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+type assertionsKey struct{}
+
+// WithAssertions attaches assertion rules to ctx; Analyze evaluates them against the page.
+func WithAssertions(ctx context.Context, rules []model.AssertionRule) context.Context {
+	return context.WithValue(ctx, assertionsKey{}, rules)
+}
+
+// ValidateAssertions checks that every rule is complete and its selector or pattern compiles.
+func ValidateAssertions(rules []model.AssertionRule) error {
+	for i, r := range rules {
+		switch r.Type {
+		case model.AssertSelectorExists, model.AssertSelectorAbsent:
+			if _, err := cascadia.ParseGroup(r.Selector); err != nil {
+				return fmt.Errorf("assertion %d: invalid selector %q", i, r.Selector)
+			}
+		case model.AssertTitleMatches:
+			if _, err := regexp.Compile(r.Pattern); err != nil {
+				return fmt.Errorf("assertion %d: invalid pattern: %w", i, err)
+			}
+		case model.AssertMetaRobotsExcludes:
+			if strings.TrimSpace(r.Value) == "" {
+				return fmt.Errorf("assertion %d: value is required", i)
+			}
+		case model.AssertMaxBrokenLinks, model.AssertMaxBrokenInternalLinks:
+			if r.Max < 0 {
+				return fmt.Errorf("assertion %d: max must not be negative", i)
+			}
+		default:
+			return fmt.Errorf("assertion %d: unknown type %q", i, r.Type)
+		}
+	}
+	return nil
+}
+
+// checkAssertions evaluates the rules in ctx, if any, and sets the result's health: failing
+// when any rule fails. It runs after the modules, whose output the link rules read.
+func checkAssertions(ctx context.Context, p *Page, res *model.AnalysisResult) {
+	rules, _ := ctx.Value(assertionsKey{}).([]model.AssertionRule)
+	if len(rules) == 0 {
+		return
+	}
+	res.Health = model.HealthPassing
+	res.Assertions = make([]model.AssertionResult, 0, len(rules))
+	for _, r := range rules {
+		msg := evaluate(r, p, res)
+		res.Assertions = append(res.Assertions, model.AssertionResult{Rule: r, Passed: msg == "", Message: msg})
+		if msg != "" {
+			res.Health = model.HealthFailing
+		}
+	}
+}
+
+// evaluate checks one rule and returns why it failed, or "" when it passed.
+func evaluate(r model.AssertionRule, p *Page, res *model.AnalysisResult) string {
+	switch r.Type {
+	case model.AssertSelectorExists, model.AssertSelectorAbsent:
+		if _, err := cascadia.ParseGroup(r.Selector); err != nil {
+			return fmt.Sprintf("invalid selector %q", r.Selector)
+		}
+		n := p.Doc.Find(r.Selector).Length()
+		if r.Type == model.AssertSelectorExists && n == 0 {
+			return fmt.Sprintf("no element matches %q", r.Selector)
+		}
+		if r.Type == model.AssertSelectorAbsent && n > 0 {
+			return fmt.Sprintf("%d elements match %q", n, r.Selector)
+		}
+	case model.AssertTitleMatches:
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return fmt.Sprintf("invalid pattern %q", r.Pattern)
+		}
+		if !re.MatchString(res.Title) {
+			return fmt.Sprintf("title %q does not match %q", res.Title, r.Pattern)
+		}
+	case model.AssertMetaRobotsExcludes:
+		if content, ok := metaRobotsDirective(p.Doc, r.Value); ok {
+			return fmt.Sprintf("meta robots %q contains %q", content, r.Value)
+		}
+	case model.AssertMaxBrokenLinks, model.AssertMaxBrokenInternalLinks:
+		if !slices.Contains(res.Modules, ModuleLinks) {
+			return fmt.Sprintf("links were not checked; the %q module did not run", ModuleLinks)
+		}
+		broken, what := res.BrokenLinkCount, "broken links"
+		if r.Type == model.AssertMaxBrokenInternalLinks {
+			broken, what = 0, "broken internal links"
+			for i := range p.Links {
+				if !p.Links[i].IsExternal && p.Links[i].IsBroken() {
+					broken++
+				}
+			}
+		}
+		if broken > r.Max {
+			return fmt.Sprintf("%d %s, at most %d allowed", broken, what, r.Max)
+		}
+	default:
+		return fmt.Sprintf("unknown type %q", r.Type)
+	}
+	return ""
+}
+
+// metaRobotsDirective looks for directive in the document's meta robots tags and returns the
+// content of the tag that has it.
+func metaRobotsDirective(doc *goquery.Document, directive string) (string, bool) {
+	directive = strings.ToLower(strings.TrimSpace(directive))
+	var content string
+	found := false
+	doc.Find("meta").EachWithBreak(func(_ int, m *goquery.Selection) bool {
+		if !strings.EqualFold(strings.TrimSpace(m.AttrOr("name", "")), "robots") {
+			return true
+		}
+		c := m.AttrOr("content", "")
+		for _, d := range strings.Split(c, ",") {
+			if strings.ToLower(strings.TrimSpace(d)) == directive {
+				content, found = c, true
+				return false
+			}
+		}
+		return true
+	})
+	return content, found
+}
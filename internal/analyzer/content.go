@@ -0,0 +1,60 @@
+package analyzer
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"golang.org/x/net/html/charset"
+)
+
+// ErrUnsupportedContentType is returned when the page is not an HTML document.
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// readBody reads at most max bytes of r and reports whether there was more; max <= 0 reads all.
+func readBody(r io.Reader, max int64) ([]byte, bool, error) {
+	if max <= 0 {
+		body, err := io.ReadAll(r)
+		return body, false, err
+	}
+	body, err := io.ReadAll(io.LimitReader(r, max+1))
+	if int64(len(body)) > max {
+		return body[:max], true, err
+	}
+	return body, false, err
+}
+
+// mediaType returns the media type of a Content-Type header, or "" when it is missing or invalid.
+func mediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return mt
+}
+
+// isHTML reports whether mt is a media type the analyzer can parse.
+func isHTML(mt string) bool {
+	return mt == "text/html" || mt == "application/xhtml+xml"
+}
+
+// sniffMediaType guesses the media type of a body sent without a usable Content-Type.
+func sniffMediaType(body []byte) string {
+	return mediaType(http.DetectContentType(body))
+}
+
+// decodeBody converts body to UTF-8 following the HTML encoding sniffing rules: a byte order
+// mark, then the Content-Type charset, then a <meta> declaration. It returns the decoded body
+// and the name of the encoding used.
+func decodeBody(body []byte, contentType string) ([]byte, string) {
+	enc, name, _ := charset.DetermineEncoding(body, contentType)
+	if name == "utf-8" {
+		return body, name
+	}
+	decoded, err := enc.NewDecoder().Bytes(body)
+	if err != nil {
+		return body, name
+	}
+	return decoded, name
+}
@@ -0,0 +1,20 @@
+package analyzer
+
+import (
+	"context"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+func init() {
+	Register(NewModule(ModuleLoginForm, func(_ context.Context, p *Page, res *model.AnalysisResult) {
+		res.HasLoginForm = hasLoginForm(p.Doc)
+	}))
+}
+
+// hasLoginForm reports whether a document has a form with a password field.
+func hasLoginForm(doc *goquery.Document) bool {
+	return doc.Find("form input[type='password']").Length() > 0
+}
@@ -0,0 +1,36 @@
+package analyzer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+func init() {
+	Register(NewModule(ModuleHeadings, func(_ context.Context, p *Page, res *model.AnalysisResult) {
+		countHeadings(p.Doc, res)
+	}))
+}
+
+// countHeadings counts the h1 to h6 elements of a document.
+func countHeadings(doc *goquery.Document, res *model.AnalysisResult) {
+	doc.Find("h1,h2,h3,h4,h5,h6").Each(func(_ int, s *goquery.Selection) {
+		switch strings.ToLower(goquery.NodeName(s)) {
+		case "h1":
+			res.H1Count++
+		case "h2":
+			res.H2Count++
+		case "h3":
+			res.H3Count++
+		case "h4":
+			res.H4Count++
+		case "h5":
+			res.H5Count++
+		case "h6":
+			res.H6Count++
+		}
+	})
+}
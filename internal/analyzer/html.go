@@ -1,7 +1,12 @@
 package analyzer
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
@@ -15,91 +20,188 @@ import (
 
 // HTMLAnalyzer analyzes HTML documents for various metrics.
 type htmlAnalyzer struct {
-	client *http.Client
-	check  *linkChecker
+	client    *http.Client
+	check     *linkChecker
+	limit     *hostLimiter
+	robots    *robotsCache
+	userAgent string
+	maxBody   int64
+	roots     *x509.CertPool // trust anchors for the TLS report; nil means the system pool
+	dial      dialFunc       // opens TLS probes the way the page transport connects
+}
+
+// Config tunes an HTML analyzer. The politeness limits apply per host and are shared by
+// every crawler worker that uses the analyzer.
+type Config struct {
+	UserAgent       string  // sent with every request and matched against robots.txt
+	HostConcurrency int     // requests in flight to one host; 0 means no cap
+	HostRPS         float64 // requests per second to one host; 0 means no cap
+	MaxBodyBytes    int64   // page bytes parsed, the rest is dropped; 0 means no cap
+
+	// BlockPrivateIPs refuses connections to private, loopback, link-local and other
+	// internal addresses, checked after DNS resolution and on every redirect. AllowedHosts
+	// exempts hostnames, IP addresses and CIDR ranges, e.g. internal staging hosts.
+	BlockPrivateIPs bool
+	AllowedHosts    []string
+
+	// RootCAs are the trust anchors page certificates are verified against; nil means the
+	// system pool.
+	RootCAs *x509.CertPool
+}
+
+// DefaultConfig returns the settings used by NewHTMLAnalyzer.
+func DefaultConfig() Config {
+	return Config{UserAgent: DefaultUserAgent, HostConcurrency: 2, HostRPS: 2, MaxBodyBytes: 5 << 20}
 }
 
 // NewHTMLAnalyzer creates a new HTML analyzer with default settings.
 func NewHTMLAnalyzer() *htmlAnalyzer {
+	return NewHTMLAnalyzerWithConfig(DefaultConfig())
+}
+
+// NewHTMLAnalyzerWithConfig creates a new HTML analyzer with the given settings.
+// A robots.txt Crawl-delay slows a host down further than HostRPS, never speeds it up.
+func NewHTMLAnalyzerWithConfig(cfg Config) *htmlAnalyzer {
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = DefaultUserAgent
+	}
+	limit := newHostLimiter(cfg.HostConcurrency, cfg.HostRPS)
+	check := newLinkChecker(12, 5*time.Second)
+	check.limit = limit
+	check.userAgent = cfg.UserAgent
+	robots := check.robots
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.RootCAs != nil {
+		transport.TLSClientConfig = &tls.Config{RootCAs: cfg.RootCAs}
+	}
+	if cfg.BlockPrivateIPs {
+		guard := newNetGuard(cfg.AllowedHosts)
+		guard.guard(transport)
+		checkTransport := http.DefaultTransport.(*http.Transport).Clone()
+		guard.guard(checkTransport)
+		check.client.Transport = checkTransport
+	}
 	return &htmlAnalyzer{
-		client: &http.Client{Timeout: 10 * time.Second},
-		check:  newLinkChecker(12, 5*time.Second),
+		client:    &http.Client{Timeout: 10 * time.Second, Transport: transport, CheckRedirect: checkRedirect},
+		check:     check,
+		limit:     limit,
+		robots:    robots,
+		userAgent: cfg.UserAgent,
+		maxBody:   cfg.MaxBodyBytes,
+		roots:     cfg.RootCAs,
+		dial:      transport.DialContext,
 	}
 }
 
-// Analyze fetches the HTML document from the URL and extracts various metrics.
+// Analyze fetches the HTML document from the URL, records how it was served and runs the
+// modules selected with WithModules over it, all of them by default, followed by the rules
+// attached with WithAssertions. Error pages are analyzed
+// too, with their status recorded; pages that are not HTML are refused with
+// ErrUnsupportedContentType. A page whose certificate does not verify is not fetched: it
+// fails with ErrUntrustedCertificate and a result holding only the TLS report.
 func (a *htmlAnalyzer) Analyze(
 	ctx context.Context,
 	u *url.URL,
 ) (*model.AnalysisResult, []model.Link, error) {
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	ctx = withRequestScope(ctx, u.Hostname())
+	rules := a.robots.lookup(ctx, a.client, u, a.userAgent)
+	release, err := a.limit.acquire(ctx, u.Host, rules.crawlDelay(a.userAgent))
+	if err != nil {
+		return nil, nil, err
+	}
+	timer := newFetchTimer()
+	fetchCtx, redirects := withRedirectTrace(timer.withTrace(ctx))
+	req, _ := http.NewRequestWithContext(fetchCtx, http.MethodGet, u.String(), nil)
+	prepareRequest(req, a.userAgent)
 	resp, err := a.client.Do(req)
 	if err != nil {
+		release()
+		if res := a.untrusted(ctx, err); res != nil {
+			return res, nil, fmt.Errorf("%w: %v", ErrUntrustedCertificate, err)
+		}
 		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	contentType := resp.Header.Get("Content-Type")
+	mt := mediaType(contentType)
+	if mt != "" && !isHTML(mt) {
+		release()
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedContentType, mt)
+	}
+
+	// Read the body before parsing so the download phase is timed on its own.
+	body, truncated, err := readBody(resp.Body, a.maxBody)
+	release()
+	if err != nil {
+		return nil, nil, err
+	}
+	timing := timer.page(time.Now(), int64(len(body)))
+	if mt == "" {
+		if mt = sniffMediaType(body); !isHTML(mt) {
+			return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedContentType, mt)
+		}
+	}
+	body, encoding := decodeBody(body, contentType)
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
 		return nil, nil, err
 	}
 
 	res := &model.AnalysisResult{
-		HTMLVersion:  detectHTMLVersion(doc),
-		Title:        strings.TrimSpace(doc.Find("title").First().Text()),
-		HasLoginForm: doc.Find("form input[type='password']").Length() > 0,
-	}
-
-	// headings
-	doc.Find("h1,h2,h3,h4,h5,h6").Each(func(_ int, s *goquery.Selection) {
-		switch strings.ToLower(goquery.NodeName(s)) {
-		case "h1":
-			res.H1Count++
-		case "h2":
-			res.H2Count++
-		case "h3":
-			res.H3Count++
-		case "h4":
-			res.H4Count++
-		case "h5":
-			res.H5Count++
-		case "h6":
-			res.H6Count++
-		}
-	})
-
-	seen := make(map[string]struct{})
-	var links []model.Link
-	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
-		href, _ := a.Attr("href")
-		abs := resolve(u, href)
-		if abs == "" {
-			return
-		}
-		if _, ok := seen[abs]; ok {
-			return
-		}
-		seen[abs] = struct{}{}
+		StatusCode:    resp.StatusCode,
+		ContentType:   mt,
+		Charset:       encoding,
+		BodyTruncated: truncated,
+		HTMLVersion:   detectHTMLVersion(doc),
+		Title:         strings.TrimSpace(doc.Find("title").First().Text()),
+		TLS:           inspectTLS(resp.TLS, resp.Request.URL.Hostname(), a.roots),
+		Redirects:     redirects.result(resp),
+		Robots:        rules.report(u, a.userAgent),
+		Timing:        timing,
+	}
+	if res.TLS != nil {
+		res.CertExpiresAt = &res.TLS.Chain[0].NotAfter
+	}
 
-		lnk := model.Link{
-			Href:       abs,
-			IsExternal: !sameHost(u, abs),
-		}
-		links = append(links, lnk)
-	})
-
-	links = a.check.run(ctx, links)
-	for _, l := range links {
-		if l.IsExternal {
-			res.ExternalLinkCount++
-		} else {
-			res.InternalLinkCount++
-		}
-		if l.StatusCode >= 400 && l.StatusCode < 600 {
-			res.BrokenLinkCount++
-		}
+	page := &Page{URL: u, Doc: doc, Response: resp, check: a.check}
+	res.Modules = []string{}
+	for _, m := range selectedModules(ctx) {
+		m.Analyze(ctx, page, res)
+		res.Modules = append(res.Modules, m.Name())
+	}
+	checkAssertions(ctx, page, res)
+	// A cancelled link check leaves unchecked links behind; hand back what we have with the error.
+	if err := ctx.Err(); err != nil {
+		return res, page.Links, err
+	}
+	return res, page.Links, nil
+}
+
+// untrusted builds the result for a fetch that failed certificate verification: just the
+// TLS report of the host that presented the certificate, which may be a redirect target.
+// It returns nil for any other error.
+func (a *htmlAnalyzer) untrusted(ctx context.Context, err error) *model.AnalysisResult {
+	var certErr *tls.CertificateVerificationError
+	var urlErr *url.Error
+	if !errors.As(err, &certErr) || !errors.As(err, &urlErr) {
+		return nil
 	}
-	return res, links, nil
+	u, perr := url.Parse(urlErr.URL)
+	if perr != nil {
+		return nil
+	}
+	cs, perr := probeTLS(ctx, a.dial, u)
+	if perr != nil {
+		return nil
+	}
+	res := &model.AnalysisResult{TLS: inspectTLS(cs, u.Hostname(), a.roots)}
+	if res.TLS != nil {
+		res.CertExpiresAt = &res.TLS.Chain[0].NotAfter
+	}
+	return res
 }
 
 // detectHTMLVersion checks the doctype of the HTML document to determine its version.
@@ -128,3 +230,8 @@ func sameHost(a *url.URL, raw string) bool {
 	b, err := url.Parse(raw)
 	return err == nil && a.Hostname() == b.Hostname()
 }
+
+// SameHost is the exported wrapper for sameHost, so that the crawler can scope site crawls.
+func SameHost(a *url.URL, raw string) bool {
+	return sameHost(a, raw)
+}
@@ -5,29 +5,30 @@ import (
 	"net/http"
 	"net/url"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/temoto/robotstxt"
-
 	"github.com/fuzumoe/urlinsight-backend/internal/model"
 )
 
-// robots is a cache for robots.txt data to avoid repeated requests.
-var robots sync.Map
-
 // linkChecker checks the status of links concurrently.
 type linkChecker struct {
-	conc    int
-	timeout time.Duration
-	client  *http.Client
+	conc      int
+	timeout   time.Duration
+	client    *http.Client
+	limit     *hostLimiter // shared per-host budget; nil means unlimited
+	robots    *robotsCache
+	userAgent string
 }
 
 // newLinkChecker creates a new link checker with the specified concurrency and timeout.
 func newLinkChecker(conc int, timeout time.Duration) *linkChecker {
 	return &linkChecker{
-		conc:    conc,
-		timeout: timeout,
-		client:  &http.Client{Timeout: timeout},
+		conc:      conc,
+		timeout:   timeout,
+		client:    &http.Client{Timeout: timeout, CheckRedirect: checkRedirect},
+		robots:    newRobotsCache(robotsCacheSize),
+		userAgent: DefaultUserAgent,
 	}
 }
 
@@ -36,87 +37,127 @@ func NewLinkChecker(conc int, timeout time.Duration) *linkChecker {
 	return newLinkChecker(conc, timeout)
 }
 
-// run checks the status of links in the provided analysis result.
-func (lc *linkChecker) run(ctx context.Context, links []model.Link) []model.Link {
-	in := make(chan *model.Link)
+type linkProgressKey struct{}
+
+// LinkProgressFunc is told how many of a page's links have been checked so far. It is called
+// from several goroutines at once.
+type LinkProgressFunc func(checked, total int)
+
+// WithLinkProgress attaches fn to ctx; Analyze calls it as each link check finishes.
+func WithLinkProgress(ctx context.Context, fn LinkProgressFunc) context.Context {
+	return context.WithValue(ctx, linkProgressKey{}, fn)
+}
+
+// run checks the status of links in the provided analysis result. It also returns the links
+// that robots.txt kept it from checking, in their original order.
+func (lc *linkChecker) run(ctx context.Context, links []model.Link) ([]model.Link, []string) {
+	in := make(chan int)
+	blocked := make([]bool, len(links))
+	progress, _ := ctx.Value(linkProgressKey{}).(LinkProgressFunc)
+	var checked atomic.Int64
 	var wg sync.WaitGroup
 
 	for i := 0; i < lc.conc; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for l := range in {
-				l.StatusCode = lc.head(ctx, l.Href)
+			for i := range in {
+				blocked[i] = !lc.head(ctx, &links[i])
+				if progress != nil {
+					progress(int(checked.Add(1)), len(links))
+				}
 			}
 		}()
 	}
 
 	go func() {
+		defer close(in)
 		for i := range links {
-			in <- &links[i]
+			select {
+			case <-ctx.Done():
+				return
+			case in <- i:
+			}
 		}
-		close(in)
 	}()
 
 	wg.Wait()
-	return links
+	skipped := []string{}
+	for i, b := range blocked {
+		if b {
+			skipped = append(skipped, links[i].Href)
+		}
+	}
+	return links, skipped
 }
 
 // Run is the exported wrapper for run, so that external packages can call it.
 func (lc *linkChecker) Run(ctx context.Context, links []model.Link) []model.Link {
-	return lc.run(ctx, links)
+	links, _ = lc.run(ctx, links)
+	return links
 }
 
-// head performs a HEAD request to check the link status, respecting robots.txt rules.
-func (lc *linkChecker) head(ctx context.Context, raw string) int {
-	u, _ := url.Parse(raw)
-	if !robotsAllowed(lc.client, u) {
-		return http.StatusForbidden
+// head performs a HEAD request to check the link status, respecting robots.txt rules,
+// and records the link's status code, redirect chain and time to first byte. Requests
+// that get no response leave status 0 and record why in the link's error fields.
+// It returns false when robots.txt disallows the link, which is then left unchecked.
+func (lc *linkChecker) head(ctx context.Context, l *model.Link) bool {
+	u, err := url.Parse(l.Href)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		// mailto:, tel: and the like cannot be checked over HTTP.
+		return true
+	}
+	rules := lc.robots.lookup(ctx, lc.client, u, lc.userAgent)
+	if !rules.allowed(u, lc.userAgent) {
+		return false
+	}
+	release, err := lc.limit.acquire(ctx, u.Host, rules.crawlDelay(lc.userAgent))
+	defer release()
+	if err != nil {
+		// Cancelled while queued for the host; the link stays unchecked.
+		return true
 	}
 
-	req, _ := http.NewRequestWithContext(ctx, http.MethodHead, raw, nil)
+	timer := newFetchTimer()
+	reqCtx, redirects := withRedirectTrace(timer.withTrace(ctx))
+	req, _ := http.NewRequestWithContext(reqCtx, http.MethodHead, l.Href, nil)
+	prepareRequest(req, lc.userAgent)
 	resp, err := lc.client.Do(req)
 	if err != nil {
-		return 0
+		lc.fail(ctx, l, err)
+		return true
 	}
 	resp.Body.Close()
 
 	if resp.StatusCode == http.StatusMethodNotAllowed {
+		if err := lc.limit.wait(ctx, u.Host); err != nil {
+			return true
+		}
+		redirects.reset()
+		timer.reset()
 		req.Method = http.MethodGet
 		resp2, err := lc.client.Do(req)
 		if err != nil {
-			return 0
+			lc.fail(ctx, l, err)
+			return true
 		}
 		resp2.Body.Close()
-		return resp2.StatusCode
+		resp = resp2
 	}
-	return resp.StatusCode
+	l.StatusCode = resp.StatusCode
+	l.Redirects = redirects.result(resp)
+	l.TTFBMs = timer.ttfb()
+	return true
 }
 
-// robotsAllowed checks if the link is allowed by robots.txt rules.
-func robotsAllowed(c *http.Client, u *url.URL) bool {
-	if u.Host == "" {
-		return true
-	}
-	if val, ok := robots.Load(u.Host); ok {
-		if val == nil {
-			return true
-		}
-		return val.(*robotstxt.RobotsData).TestAgent(u.Path, "*")
+// fail records why a link got no response. A check cut short by the analysis' own deadline
+// or cancellation says nothing about the link, which is left unchecked.
+func (lc *linkChecker) fail(ctx context.Context, l *model.Link, err error) {
+	if ctx.Err() != nil {
+		return
 	}
-
-	resp, err := c.Get(u.Scheme + "://" + u.Host + "/robots.txt")
-	if err != nil {
-		robots.Store(u.Host, nil)
-		return true
-	}
-	defer resp.Body.Close()
-
-	data, err := robotstxt.FromResponse(resp)
-	if err != nil {
-		robots.Store(u.Host, nil)
-		return true
+	l.StatusCode = 0
+	if l.ErrorCategory = classifyError(err); l.ErrorCategory != "" {
+		l.ErrorMessage = err.Error()
 	}
-	robots.Store(u.Host, data)
-	return data.TestAgent(u.Path, "*")
 }
@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"syscall"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+// classifyError maps a transport error onto a link error category. Cancellation is not a
+// failure of the link, so it yields an empty category and the link stays unchecked.
+func classifyError(err error) string {
+	var (
+		dnsErr     *net.DNSError
+		netErr     net.Error
+		verifyErr  *tls.CertificateVerificationError
+		recordErr  tls.RecordHeaderError
+		unknownCA  x509.UnknownAuthorityError
+		hostErr    x509.HostnameError
+		invalidErr x509.CertificateInvalidError
+	)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ""
+	case errors.Is(err, ErrBlockedAddress):
+		return model.LinkErrBlocked
+	case errors.As(err, &dnsErr):
+		if dnsErr.IsTimeout {
+			return model.LinkErrTimeout
+		}
+		return model.LinkErrDNS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return model.LinkErrTimeout
+	case errors.As(err, &verifyErr), errors.As(err, &recordErr), errors.As(err, &unknownCA),
+		errors.As(err, &hostErr), errors.As(err, &invalidErr):
+		return model.LinkErrTLS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return model.LinkErrRefused
+	case errors.Is(err, syscall.ECONNRESET):
+		return model.LinkErrReset
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return model.LinkErrUnreachable
+	}
+	return model.LinkErrOther
+}
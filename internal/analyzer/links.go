@@ -0,0 +1,60 @@
+package analyzer
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+func init() {
+	Register(NewModule(ModuleLinks, analyzeLinks))
+}
+
+// analyzeLinks checks every link of the page and counts them. Links robots.txt keeps us from
+// checking are listed in the robots report instead.
+func analyzeLinks(ctx context.Context, p *Page, res *model.AnalysisResult) {
+	links, skipped := p.check.run(ctx, extractLinks(p.Doc, p.URL))
+	if res.Robots != nil {
+		res.Robots.SkippedLinks = skipped
+	}
+	for _, l := range links {
+		if l.IsExternal {
+			res.ExternalLinkCount++
+		} else {
+			res.InternalLinkCount++
+		}
+		if l.IsBroken() {
+			res.BrokenLinkCount++
+		}
+		if l.ErrorCategory != "" {
+			res.ErrorLinkCount++
+		}
+	}
+	p.Links = links
+}
+
+// extractLinks returns the distinct anchors of a document, resolved against base.
+func extractLinks(doc *goquery.Document, base *url.URL) []model.Link {
+	seen := make(map[string]struct{})
+	var links []model.Link
+	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		abs := resolve(base, href)
+		if abs == "" {
+			return
+		}
+		if _, ok := seen[abs]; ok {
+			return
+		}
+		seen[abs] = struct{}{}
+
+		links = append(links, model.Link{
+			Href:       abs,
+			IsExternal: !sameHost(base, abs),
+		})
+	})
+	return links
+}
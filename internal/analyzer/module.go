@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+// Names of the built-in modules.
+const (
+	ModuleHeadings       = "headings"
+	ModuleLinks          = "links"
+	ModuleLoginForm      = "login_form"
+	ModuleSEO            = "seo"
+	ModuleStructuredData = "structured_data"
+	ModuleAccessibility  = "accessibility"
+	ModuleSecurity       = "security"
+)
+
+// Page is a fetched and parsed document, as handed to each module.
+type Page struct {
+	URL      *url.URL // the URL requested; relative references resolve against it
+	Doc      *goquery.Document
+	Response *http.Response // headers, cookies and TLS state; the body has been read
+	Links    []model.Link   // links found on the page, returned by Analyze
+
+	check *linkChecker
+}
+
+// Module is one check run against a page. It fills its own section of the result and leaves
+// the rest alone, so modules can run in any order and any combination.
+type Module interface {
+	Name() string
+	Analyze(ctx context.Context, p *Page, res *model.AnalysisResult)
+}
+
+type moduleFunc struct {
+	name string
+	fn   func(ctx context.Context, p *Page, res *model.AnalysisResult)
+}
+
+func (m moduleFunc) Name() string { return m.name }
+
+func (m moduleFunc) Analyze(ctx context.Context, p *Page, res *model.AnalysisResult) {
+	m.fn(ctx, p, res)
+}
+
+// NewModule wraps fn as a Module called name.
+func NewModule(name string, fn func(ctx context.Context, p *Page, res *model.AnalysisResult)) Module {
+	return moduleFunc{name: name, fn: fn}
+}
+
+var (
+	modulesMu sync.RWMutex
+	modules   = make(map[string]Module)
+)
+
+// Register makes a module available to every analyzer. It is meant to be called from init
+// and panics if the name is empty or already taken.
+func Register(m Module) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+	name := m.Name()
+	if name == "" {
+		panic("analyzer: Register called with an unnamed module")
+	}
+	if _, dup := modules[name]; dup {
+		panic("analyzer: Register called twice for module " + name)
+	}
+	modules[name] = m
+}
+
+// ModuleNames returns the names of the registered modules, sorted.
+func ModuleNames() []string {
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateModules checks that every name is a registered module.
+func ValidateModules(names []string) error {
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+	for _, name := range names {
+		if _, ok := modules[name]; !ok {
+			return fmt.Errorf("unknown analysis module %q", name)
+		}
+	}
+	return nil
+}
+
+type modulesKey struct{}
+
+// WithModules selects the modules Analyze runs; none selects all of them.
+func WithModules(ctx context.Context, names []string) context.Context {
+	return context.WithValue(ctx, modulesKey{}, names)
+}
+
+// selectedModules returns the modules selected in ctx in name order. Unknown names are ignored.
+func selectedModules(ctx context.Context) []Module {
+	names, _ := ctx.Value(modulesKey{}).([]string)
+	if len(names) == 0 {
+		names = ModuleNames()
+	} else {
+		names = append([]string(nil), names...)
+		sort.Strings(names)
+	}
+
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+	selected := make([]Module, 0, len(names))
+	for i, name := range names {
+		if m, ok := modules[name]; ok && (i == 0 || names[i-1] != name) {
+			selected = append(selected, m)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,157 @@
+package analyzer
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// hostLimiterSize bounds how many hosts keep a budget; idle ones beyond it are forgotten.
+const hostLimiterSize = 1024
+
+// hostLimiter caps concurrent requests and spaces them out per host. One limiter is shared by
+// the page fetch and the link checker of an analyzer, so every crawler worker using that
+// analyzer draws from the same per-host budget.
+type hostLimiter struct {
+	conc  int
+	limit rate.Limit
+	size  int
+
+	mu    sync.Mutex
+	order *list.List // of *hostSlot, most recently used first
+	hosts map[string]*list.Element
+}
+
+// hostSlot is the budget of a single host.
+type hostSlot struct {
+	host  string
+	sem   chan struct{}
+	lim   *rate.Limiter
+	users int // requests holding or waiting for the slot; guarded by hostLimiter.mu
+}
+
+// newHostLimiter allows conc requests in flight and rps requests per second to each host.
+// A non-positive value disables that limit.
+func newHostLimiter(conc int, rps float64) *hostLimiter {
+	limit := rate.Inf
+	if rps > 0 {
+		limit = rate.Limit(rps)
+	}
+	return &hostLimiter{
+		conc:  conc,
+		limit: limit,
+		size:  hostLimiterSize,
+		order: list.New(),
+		hosts: make(map[string]*list.Element),
+	}
+}
+
+// slot returns the budget for host, creating it on first use, and counts the caller as a
+// user until it calls put.
+func (h *hostLimiter) slot(host string) *hostSlot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if el, ok := h.hosts[host]; ok {
+		h.order.MoveToFront(el)
+		s := el.Value.(*hostSlot)
+		s.users++
+		return s
+	}
+	s := &hostSlot{host: host, lim: rate.NewLimiter(h.limit, 1), users: 1}
+	if h.conc > 0 {
+		s.sem = make(chan struct{}, h.conc)
+	}
+	h.hosts[host] = h.order.PushFront(s)
+	h.evict()
+	return s
+}
+
+// put gives back a slot taken with slot.
+func (h *hostLimiter) put(s *hostSlot) {
+	h.mu.Lock()
+	s.users--
+	h.mu.Unlock()
+}
+
+// evict forgets the least recently used hosts beyond the size bound. Only slots that are
+// unused and would let a request through right away go, so no limit is ever loosened; the
+// limiter may run over its size while more hosts than that are busy. The caller holds h.mu.
+func (h *hostLimiter) evict() {
+	for el := h.order.Back(); el != nil && h.order.Len() > h.size; {
+		prev := el.Prev()
+		if s := el.Value.(*hostSlot); s.users == 0 && s.lim.Tokens() >= 1 {
+			h.order.Remove(el)
+			delete(h.hosts, s.host)
+		}
+		el = prev
+	}
+}
+
+// acquire blocks until host has a free slot and its next request is due. A robots.txt
+// Crawl-delay longer than the configured rate slows the host down for as long as robots.txt
+// asks for it. The returned func gives the slot back; it is never nil, even when ctx ends
+// first.
+func (h *hostLimiter) acquire(ctx context.Context, host string, crawlDelay time.Duration) (func(), error) {
+	if h == nil {
+		return func() {}, nil
+	}
+	s := h.slot(host)
+	limit := h.limit
+	if crawlDelay > 0 && rate.Every(crawlDelay) < limit {
+		limit = rate.Every(crawlDelay)
+	}
+	if s.lim.Limit() != limit {
+		s.lim.SetLimit(limit)
+	}
+
+	release := func() { h.put(s) }
+	if s.sem != nil {
+		select {
+		case s.sem <- struct{}{}:
+			release = func() {
+				<-s.sem
+				h.put(s)
+			}
+		case <-ctx.Done():
+			h.put(s)
+			return func() {}, ctx.Err()
+		}
+	}
+	if err := pace(ctx, s.lim); err != nil {
+		release()
+		return func() {}, err
+	}
+	return release, nil
+}
+
+// wait spaces out a follow-up request to a host whose slot is already held.
+func (h *hostLimiter) wait(ctx context.Context, host string) error {
+	if h == nil {
+		return nil
+	}
+	s := h.slot(host)
+	defer h.put(s)
+	return pace(ctx, s.lim)
+}
+
+// pace sleeps until lim allows the next request. Unlike rate.Limiter.Wait it waits out the
+// context instead of failing early when the deadline is too close, so callers see ctx.Err().
+func pace(ctx context.Context, lim *rate.Limiter) error {
+	r := lim.Reserve()
+	d := r.Delay()
+	if d == 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
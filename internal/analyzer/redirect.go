@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+const (
+	// excessiveRedirects is the longest chain that is not flagged.
+	excessiveRedirects = 3
+	// maxRedirects stops following after this many hops, like net/http's default.
+	maxRedirects = 10
+)
+
+type redirectKey struct{}
+
+// redirectTrace collects the hops of one request. Clients are shared between goroutines,
+// so the trace travels in the request context rather than on the client.
+type redirectTrace struct {
+	mu    sync.Mutex
+	chain model.RedirectChain
+	seen  map[string]bool
+}
+
+func withRedirectTrace(ctx context.Context) (context.Context, *redirectTrace) {
+	t := &redirectTrace{chain: model.RedirectChain{Hops: []model.RedirectHop{}}}
+	return context.WithValue(ctx, redirectKey{}, t), t
+}
+
+// result returns the recorded chain, or nil if the request was not redirected.
+func (t *redirectTrace) result(final *http.Response) *model.RedirectChain {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.chain.Hops) == 0 {
+		return nil
+	}
+	c := t.chain
+	c.Hops = append([]model.RedirectHop{}, t.chain.Hops...)
+	if final != nil && final.Request != nil {
+		c.FinalURL = final.Request.URL.String()
+	}
+	c.Excessive = len(c.Hops) > excessiveRedirects
+	return &c
+}
+
+// reset forgets earlier hops, for when a request is retried with another method.
+func (t *redirectTrace) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.chain = model.RedirectChain{Hops: []model.RedirectHop{}}
+	t.seen = nil
+}
+
+// checkRedirect is an http.Client CheckRedirect hook that records every hop and stops
+// on loops or overly long chains, handing back the last redirect response. Custom request
+// headers and cookies are dropped when a redirect leaves the analyzed host.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	scrubRequest(req)
+	t, _ := req.Context().Value(redirectKey{}).(*redirectTrace)
+	if t == nil {
+		if len(via) >= maxRedirects {
+			return http.ErrUseLastResponse
+		}
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	prev := via[len(via)-1]
+	hop := model.RedirectHop{URL: prev.URL.String()}
+	if req.Response != nil {
+		hop.StatusCode = req.Response.StatusCode
+		hop.Location = req.Response.Header.Get("Location")
+	}
+	t.chain.Hops = append(t.chain.Hops, hop)
+
+	if prev.URL.Scheme == "https" && req.URL.Scheme == "http" {
+		t.chain.Downgrade = true
+	}
+	if t.seen == nil {
+		t.seen = map[string]bool{via[0].URL.String(): true}
+	}
+	next := req.URL.String()
+	if t.seen[next] {
+		t.chain.Loop = true
+		return http.ErrUseLastResponse
+	}
+	t.seen[next] = true
+
+	if len(via) >= maxRedirects {
+		return http.ErrUseLastResponse
+	}
+	return nil
+}
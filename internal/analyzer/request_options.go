@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+// DefaultUserAgent identifies the crawler when no User-Agent is configured.
+const DefaultUserAgent = "URLInsight-Bot/1.0"
+
+type requestOptionsKey struct{}
+
+// requestScope ties a URL's custom headers and cookies to the host being analyzed.
+type requestScope struct {
+	host string
+	opts model.RequestOptions
+}
+
+// WithRequestOptions attaches per-URL headers and cookies to ctx. Analyze sends them with the
+// page fetch and with link checks on the same host, never to other hosts.
+func WithRequestOptions(ctx context.Context, opts model.RequestOptions) context.Context {
+	return context.WithValue(ctx, requestOptionsKey{}, opts)
+}
+
+// withRequestScope binds the options in ctx, if any, to host.
+func withRequestScope(ctx context.Context, host string) context.Context {
+	opts, ok := ctx.Value(requestOptionsKey{}).(model.RequestOptions)
+	if !ok || (len(opts.Headers) == 0 && len(opts.Cookies) == 0) {
+		return ctx
+	}
+	return context.WithValue(ctx, requestOptionsKey{}, &requestScope{host: host, opts: opts})
+}
+
+// prepareRequest sets the User-Agent and, for requests to the scoped host, the custom headers
+// and cookies. Custom headers are applied last so they may override the User-Agent.
+func prepareRequest(req *http.Request, userAgent string) {
+	req.Header.Set("User-Agent", userAgent)
+	s, _ := req.Context().Value(requestOptionsKey{}).(*requestScope)
+	if s == nil || req.URL.Hostname() != s.host {
+		return
+	}
+	for name, value := range s.opts.Headers {
+		req.Header.Set(name, value)
+	}
+	for name, value := range s.opts.Cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+}
+
+// scrubRequest drops the custom headers and cookies from a redirect that leaves the scoped host.
+func scrubRequest(req *http.Request) {
+	s, _ := req.Context().Value(requestOptionsKey{}).(*requestScope)
+	if s == nil || req.URL.Hostname() == s.host {
+		return
+	}
+	for name := range s.opts.Headers {
+		req.Header.Del(name)
+	}
+	if len(s.opts.Cookies) > 0 {
+		req.Header.Del("Cookie")
+	}
+}
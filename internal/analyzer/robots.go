@@ -0,0 +1,209 @@
+package analyzer
+
+import (
+	"container/list"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/temoto/robotstxt"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+const (
+	// robotsTTL is how long fetched rules are trusted; RFC 9309 caps it at 24 hours.
+	robotsTTL = 24 * time.Hour
+	// robotsRetryTTL is how soon a host whose robots.txt could not be fetched is tried again.
+	robotsRetryTTL = 5 * time.Minute
+	// robotsCacheSize bounds the number of hosts kept; the least recently used go first.
+	robotsCacheSize = 1024
+	// maxRobotsBytes is how much of a robots.txt is parsed; RFC 9309 asks for at least 500 KiB.
+	maxRobotsBytes = 500 << 10
+)
+
+// robotsEntry is the cached outcome of fetching one host's robots.txt.
+type robotsEntry struct {
+	status  string
+	code    int
+	data    *robotstxt.RobotsData // set when status is model.RobotsOK
+	expires time.Time
+}
+
+// allowed reports whether userAgent may fetch u.
+func (e *robotsEntry) allowed(u *url.URL, userAgent string) bool {
+	switch e.status {
+	case model.RobotsOK:
+		return e.data.FindGroup(productToken(userAgent)).Test(u.RequestURI())
+	case model.RobotsUnreachable:
+		return false
+	default:
+		return true
+	}
+}
+
+// crawlDelay returns the Crawl-delay asked of userAgent, or zero.
+func (e *robotsEntry) crawlDelay(userAgent string) time.Duration {
+	if e.status != model.RobotsOK {
+		return 0
+	}
+	return e.data.FindGroup(productToken(userAgent)).CrawlDelay
+}
+
+// report describes the entry as it applies to the page u.
+func (e *robotsEntry) report(u *url.URL, userAgent string) *model.RobotsReport {
+	r := &model.RobotsReport{
+		Status:         e.status,
+		StatusCode:     e.code,
+		PageDisallowed: !e.allowed(u, userAgent),
+		CrawlDelay:     e.crawlDelay(userAgent).Seconds(),
+		Sitemaps:       []string{},
+		SkippedLinks:   []string{},
+	}
+	if e.data != nil && e.data.Sitemaps != nil {
+		r.Sitemaps = e.data.Sitemaps
+	}
+	return r
+}
+
+// productToken returns the part of a User-Agent that robots.txt groups are matched against,
+// e.g. "urlinsight-bot" for "URLInsight-Bot/1.0 (+https://example.com)".
+func productToken(userAgent string) string {
+	token := userAgent
+	if i := strings.IndexAny(token, "/ "); i >= 0 {
+		token = token[:i]
+	}
+	return strings.ToLower(token)
+}
+
+// robotsCache keeps robots.txt outcomes per scheme and host, expiring and bounded in size.
+// Concurrent lookups of the same host share a single fetch.
+type robotsCache struct {
+	mu       sync.Mutex
+	size     int
+	order    *list.List // of *robotsItem, most recently used first
+	items    map[string]*list.Element
+	inflight map[string]chan struct{}
+	now      func() time.Time
+}
+
+type robotsItem struct {
+	key   string
+	entry *robotsEntry
+}
+
+func newRobotsCache(size int) *robotsCache {
+	return &robotsCache{
+		size:     size,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+		inflight: make(map[string]chan struct{}),
+		now:      time.Now,
+	}
+}
+
+// lookup returns the robots.txt outcome for u's host, fetching it with c when the cached one
+// is missing or expired. A fetch cut short by ctx is not cached, so one cancelled analysis
+// does not hold back the host for the others.
+func (rc *robotsCache) lookup(ctx context.Context, c *http.Client, u *url.URL, userAgent string) *robotsEntry {
+	key := u.Scheme + "://" + u.Host
+	for {
+		rc.mu.Lock()
+		var stale *robotsEntry
+		if el, ok := rc.items[key]; ok {
+			rc.order.MoveToFront(el)
+			stale = el.Value.(*robotsItem).entry
+			if rc.now().Before(stale.expires) {
+				rc.mu.Unlock()
+				return stale
+			}
+		}
+		if wait, busy := rc.inflight[key]; busy {
+			rc.mu.Unlock()
+			select {
+			case <-wait:
+				continue
+			case <-ctx.Done():
+				return &robotsEntry{status: model.RobotsFailed}
+			}
+		}
+		done := make(chan struct{})
+		rc.inflight[key] = done
+		rc.mu.Unlock()
+
+		fresh, ttl := fetchRobots(ctx, c, key, userAgent, stale)
+		fresh.expires = rc.now().Add(ttl)
+
+		rc.mu.Lock()
+		if ctx.Err() == nil {
+			rc.store(key, fresh)
+		}
+		delete(rc.inflight, key)
+		close(done)
+		rc.mu.Unlock()
+		return fresh
+	}
+}
+
+// store caches e under key, evicting the least recently used hosts beyond the size bound.
+// The caller holds rc.mu.
+func (rc *robotsCache) store(key string, e *robotsEntry) {
+	if el, ok := rc.items[key]; ok {
+		el.Value.(*robotsItem).entry = e
+		rc.order.MoveToFront(el)
+		return
+	}
+	rc.items[key] = rc.order.PushFront(&robotsItem{key: key, entry: e})
+	for rc.order.Len() > rc.size {
+		oldest := rc.order.Back()
+		rc.order.Remove(oldest)
+		delete(rc.items, oldest.Value.(*robotsItem).key)
+	}
+}
+
+// fetchRobots fetches and classifies a robots.txt per RFC 9309: 2xx rules apply, 4xx means no
+// restrictions and 5xx means complete disallow. While a host is failing, the previous good
+// rules are kept if there are any. It also returns how long the outcome may be cached.
+func fetchRobots(ctx context.Context, c *http.Client, origin, userAgent string, stale *robotsEntry) (*robotsEntry, time.Duration) {
+	failing := func(e *robotsEntry) (*robotsEntry, time.Duration) {
+		if stale != nil && stale.status == model.RobotsOK {
+			kept := *stale
+			return &kept, robotsRetryTTL
+		}
+		return e, robotsRetryTTL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return &robotsEntry{status: model.RobotsFailed}, robotsRetryTTL
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := c.Do(req)
+	if err != nil {
+		// A host we cannot reach fails its link checks anyway; let them report why.
+		return failing(&robotsEntry{status: model.RobotsFailed})
+	}
+	defer resp.Body.Close()
+
+	switch code := resp.StatusCode; {
+	case code >= 200 && code < 300:
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxRobotsBytes))
+		if err != nil {
+			return failing(&robotsEntry{status: model.RobotsFailed, code: code})
+		}
+		data, err := robotstxt.FromBytes(body)
+		if err != nil {
+			return &robotsEntry{status: model.RobotsUnavailable, code: code}, robotsTTL
+		}
+		return &robotsEntry{status: model.RobotsOK, code: code, data: data}, robotsTTL
+	case code >= 500:
+		return failing(&robotsEntry{status: model.RobotsUnreachable, code: code})
+	default:
+		// 4xx, and redirects that were not followed to the end.
+		return &robotsEntry{status: model.RobotsUnavailable, code: code}, robotsTTL
+	}
+}
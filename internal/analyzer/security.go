@@ -0,0 +1,227 @@
+package analyzer
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+func init() {
+	Register(NewModule(ModuleSecurity, func(_ context.Context, p *Page, res *model.AnalysisResult) {
+		res.Security = auditSecurity(p.Response)
+	}))
+}
+
+// minHSTSMaxAge is the shortest HSTS lifetime (180 days) we accept without a warning.
+const minHSTSMaxAge = 180 * 24 * 60 * 60
+
+// Cookie problems cost cookiePenalty points each, up to maxCookiePenalty in total.
+const (
+	cookiePenalty    = 5
+	maxCookiePenalty = 20
+)
+
+// headerRule checks one header; weight is the score it contributes when it passes.
+type headerRule struct {
+	name   string
+	weight int
+	check  func(h http.Header, https bool) (status, msg string)
+}
+
+var headerRules = []headerRule{
+	{"Content-Security-Policy", 25, checkCSP},
+	{"Strict-Transport-Security", 25, checkHSTS},
+	{"X-Frame-Options", 15, checkFrameOptions},
+	{"X-Content-Type-Options", 15, checkContentTypeOptions},
+	{"Referrer-Policy", 10, checkReferrerPolicy},
+	{"Permissions-Policy", 10, checkPermissionsPolicy},
+}
+
+// auditSecurity grades the security headers and cookies of the final response.
+func auditSecurity(resp *http.Response) *model.SecurityReport {
+	https := resp.Request != nil && resp.Request.URL.Scheme == "https"
+	r := &model.SecurityReport{
+		Headers: []model.HeaderCheck{},
+		Cookies: []model.CookieCheck{},
+	}
+
+	for _, rule := range headerRules {
+		status, msg := rule.check(resp.Header, https)
+		switch status {
+		case model.CheckPass:
+			r.Score += rule.weight
+		case model.CheckWarn:
+			r.Score += rule.weight / 2
+		}
+		r.Headers = append(r.Headers, model.HeaderCheck{
+			Name:    rule.name,
+			Value:   resp.Header.Get(rule.name),
+			Status:  status,
+			Message: msg,
+		})
+	}
+
+	penalty := 0
+	for _, c := range resp.Cookies() {
+		cc := checkCookie(c, https)
+		if len(cc.Issues) > 0 {
+			penalty += cookiePenalty
+		}
+		r.Cookies = append(r.Cookies, cc)
+	}
+	r.Score -= min(penalty, maxCookiePenalty)
+	r.Score = max(r.Score, 0)
+	r.Grade = model.SecurityGrade(r.Score)
+	return r
+}
+
+func checkCSP(h http.Header, _ bool) (string, string) {
+	csp := h.Get("Content-Security-Policy")
+	if csp == "" {
+		if h.Get("Content-Security-Policy-Report-Only") != "" {
+			return model.CheckWarn, "policy is report-only"
+		}
+		return model.CheckFail, "missing"
+	}
+	for _, d := range directives(csp) {
+		if d.name != "script-src" && d.name != "default-src" {
+			continue
+		}
+		for _, src := range d.values {
+			switch src = strings.ToLower(src); src {
+			case "'unsafe-inline'", "'unsafe-eval'", "*":
+				return model.CheckWarn, d.name + " allows " + src
+			}
+		}
+	}
+	return model.CheckPass, ""
+}
+
+func checkHSTS(h http.Header, https bool) (string, string) {
+	if !https {
+		return model.CheckFail, "page is not served over HTTPS"
+	}
+	v := h.Get("Strict-Transport-Security")
+	if v == "" {
+		return model.CheckFail, "missing"
+	}
+	maxAge := -1
+	for _, part := range strings.Split(v, ";") {
+		k, val, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if strings.EqualFold(k, "max-age") {
+			if n, err := strconv.Atoi(strings.Trim(val, `"`)); err == nil {
+				maxAge = n
+			}
+		}
+	}
+	switch {
+	case maxAge < 0:
+		return model.CheckFail, "max-age is missing or invalid"
+	case maxAge == 0:
+		return model.CheckFail, "max-age=0 disables HSTS"
+	case maxAge < minHSTSMaxAge:
+		return model.CheckWarn, "max-age is shorter than 180 days"
+	}
+	return model.CheckPass, ""
+}
+
+func checkFrameOptions(h http.Header, _ bool) (string, string) {
+	switch v := strings.ToUpper(strings.TrimSpace(h.Get("X-Frame-Options"))); {
+	case v == "DENY", v == "SAMEORIGIN":
+		return model.CheckPass, ""
+	case strings.HasPrefix(v, "ALLOW-FROM"):
+		return model.CheckWarn, "ALLOW-FROM is not supported by modern browsers"
+	case v != "":
+		return model.CheckFail, "unrecognised value"
+	}
+	for _, d := range directives(h.Get("Content-Security-Policy")) {
+		if d.name == "frame-ancestors" {
+			return model.CheckPass, "covered by CSP frame-ancestors"
+		}
+	}
+	return model.CheckFail, "missing"
+}
+
+func checkContentTypeOptions(h http.Header, _ bool) (string, string) {
+	switch v := strings.TrimSpace(h.Get("X-Content-Type-Options")); {
+	case strings.EqualFold(v, "nosniff"):
+		return model.CheckPass, ""
+	case v == "":
+		return model.CheckFail, "missing"
+	}
+	return model.CheckFail, "value must be nosniff"
+}
+
+func checkReferrerPolicy(h http.Header, _ bool) (string, string) {
+	v := h.Get("Referrer-Policy")
+	if v == "" {
+		return model.CheckFail, "missing"
+	}
+	// Browsers use the last policy they understand.
+	policies := strings.Split(v, ",")
+	switch p := strings.ToLower(strings.TrimSpace(policies[len(policies)-1])); p {
+	case "no-referrer", "same-origin", "strict-origin", "strict-origin-when-cross-origin":
+		return model.CheckPass, ""
+	case "unsafe-url", "origin-when-cross-origin", "no-referrer-when-downgrade", "origin":
+		return model.CheckWarn, p + " leaks referrer data cross-origin"
+	}
+	return model.CheckFail, "unrecognised value"
+}
+
+func checkPermissionsPolicy(h http.Header, _ bool) (string, string) {
+	if h.Get("Permissions-Policy") == "" {
+		return model.CheckFail, "missing"
+	}
+	return model.CheckPass, ""
+}
+
+func checkCookie(c *http.Cookie, https bool) model.CookieCheck {
+	cc := model.CookieCheck{
+		Name:     c.Name,
+		Secure:   c.Secure,
+		HTTPOnly: c.HttpOnly,
+		Issues:   []string{},
+	}
+	switch c.SameSite {
+	case http.SameSiteLaxMode:
+		cc.SameSite = "Lax"
+	case http.SameSiteStrictMode:
+		cc.SameSite = "Strict"
+	case http.SameSiteNoneMode:
+		cc.SameSite = "None"
+	}
+
+	if https && !c.Secure {
+		cc.Issues = append(cc.Issues, "missing Secure")
+	}
+	if !c.HttpOnly {
+		cc.Issues = append(cc.Issues, "missing HttpOnly")
+	}
+	if cc.SameSite == "" {
+		cc.Issues = append(cc.Issues, "missing SameSite")
+	}
+	if cc.SameSite == "None" && !c.Secure {
+		cc.Issues = append(cc.Issues, "SameSite=None requires Secure")
+	}
+	return cc
+}
+
+type cspDirective struct {
+	name   string
+	values []string
+}
+
+func directives(csp string) []cspDirective {
+	var out []cspDirective
+	for _, part := range strings.Split(csp, ";") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		out = append(out, cspDirective{name: strings.ToLower(fields[0]), values: fields[1:]})
+	}
+	return out
+}
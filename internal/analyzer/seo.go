@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+func init() {
+	Register(NewModule(ModuleSEO, func(_ context.Context, p *Page, res *model.AnalysisResult) {
+		res.SEO = extractSEO(p.Doc, p.URL)
+	}))
+}
+
+// extractSEO collects the SEO-relevant meta and link tags of a document.
+// Canonical and hreflang targets are resolved against the page URL.
+func extractSEO(doc *goquery.Document, base *url.URL) *model.SEOMetadata {
+	seo := &model.SEOMetadata{
+		Hreflang:    []model.HreflangLink{},
+		OpenGraph:   map[string]string{},
+		TwitterCard: map[string]string{},
+	}
+
+	doc.Find("meta").Each(func(_ int, m *goquery.Selection) {
+		content := strings.TrimSpace(m.AttrOr("content", ""))
+		name := strings.ToLower(strings.TrimSpace(m.AttrOr("name", "")))
+		property := strings.ToLower(strings.TrimSpace(m.AttrOr("property", "")))
+
+		switch {
+		case name == "description":
+			setOnce(&seo.MetaDescription, content)
+		case name == "robots":
+			setOnce(&seo.MetaRobots, content)
+		case name == "viewport":
+			setOnce(&seo.Viewport, content)
+		case strings.HasPrefix(property, "og:"):
+			addOnce(seo.OpenGraph, property, content)
+		// Twitter documents name=, but property= is common enough to accept too.
+		case strings.HasPrefix(name, "twitter:"):
+			addOnce(seo.TwitterCard, name, content)
+		case strings.HasPrefix(property, "twitter:"):
+			addOnce(seo.TwitterCard, property, content)
+		}
+	})
+
+	doc.Find("link[rel][href]").Each(func(_ int, l *goquery.Selection) {
+		rel := strings.Fields(strings.ToLower(l.AttrOr("rel", "")))
+		href := resolve(base, l.AttrOr("href", ""))
+		if href == "" {
+			return
+		}
+		for _, r := range rel {
+			switch r {
+			case "canonical":
+				setOnce(&seo.Canonical, href)
+			case "alternate":
+				if lang := strings.TrimSpace(l.AttrOr("hreflang", "")); lang != "" {
+					seo.Hreflang = append(seo.Hreflang, model.HreflangLink{Lang: lang, Href: href})
+				}
+			}
+		}
+	})
+
+	return seo
+}
+
+// setOnce keeps the first non-empty value, as search engines do for duplicate tags.
+func setOnce(dst *string, v string) {
+	if *dst == "" {
+		*dst = v
+	}
+}
+
+func addOnce(m map[string]string, key, v string) {
+	if _, ok := m[key]; !ok && v != "" {
+		m[key] = v
+	}
+}
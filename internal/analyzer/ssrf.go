@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrBlockedAddress is returned when a request would reach a private, loopback, link-local
+// or otherwise internal address that is not on the allowlist.
+var ErrBlockedAddress = errors.New("address is not publicly routable")
+
+// blockedPrefixes are special-purpose ranges the netip predicates do not cover.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, which can embed any IPv4 address
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+	netip.MustParsePrefix("2002::/16"),      // 6to4, likewise
+	netip.MustParsePrefix("fec0::/10"),      // deprecated site-local
+	netip.MustParsePrefix("100::/64"),       // discard-only
+	netip.MustParsePrefix("2001::/32"),      // Teredo
+}
+
+// netGuard refuses connections to internal addresses, except for allowlisted hosts and ranges.
+type netGuard struct {
+	hosts    map[string]bool // hostnames that may resolve to any address
+	prefixes []netip.Prefix  // addresses that are always allowed
+}
+
+// newNetGuard builds a guard from an allowlist of hostnames, IP addresses and CIDR ranges.
+func newNetGuard(allowed []string) *netGuard {
+	g := &netGuard{hosts: make(map[string]bool)}
+	for _, entry := range allowed {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if p, err := netip.ParsePrefix(entry); err == nil {
+			g.prefixes = append(g.prefixes, p.Masked())
+		} else if ip, err := netip.ParseAddr(entry); err == nil {
+			g.prefixes = append(g.prefixes, netip.PrefixFrom(ip, ip.BitLen()))
+		} else {
+			g.hosts[entry] = true
+		}
+	}
+	return g
+}
+
+// blocked reports whether ip is internal and not allowlisted.
+func (g *netGuard) blocked(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	for _, p := range g.prefixes {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	// Link-local covers the 169.254.169.254 metadata service; private covers fd00:ec2::254.
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, p := range blockedPrefixes {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// dialContext dials like d, checking every address it connects to. The check runs on the
+// address actually dialed, so neither a DNS answer nor a redirect can slip past it.
+func (g *netGuard) dialContext(d *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	guarded := *d
+	guarded.Control = func(_, address string, _ syscall.RawConn) error {
+		ap, err := netip.ParseAddrPort(address)
+		if err != nil {
+			return err
+		}
+		if g.blocked(ap.Addr()) {
+			return fmt.Errorf("%w: %s", ErrBlockedAddress, ap.Addr())
+		}
+		return nil
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if host, _, err := net.SplitHostPort(addr); err == nil && g.hosts[strings.ToLower(host)] {
+			return d.DialContext(ctx, network, addr)
+		}
+		return guarded.DialContext(ctx, network, addr)
+	}
+}
+
+// guard makes t dial through g. Proxies are switched off, since a proxy would resolve and
+// connect to hosts out of the guard's sight.
+func (g *netGuard) guard(t *http.Transport) {
+	t.Proxy = nil
+	t.DialContext = g.dialContext(&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second})
+}
+
+// GuardedTransport returns an HTTP transport that refuses internal addresses the way
+// Config.BlockPrivateIPs does, for other clients that call user-supplied URLs.
+func GuardedTransport(allowed []string) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	newNetGuard(allowed).guard(t)
+	return t
+}
@@ -0,0 +1,164 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+func init() {
+	Register(NewModule(ModuleStructuredData, func(_ context.Context, p *Page, res *model.AnalysisResult) {
+		res.StructuredData = extractStructuredData(p.Doc)
+	}))
+}
+
+// extractStructuredData reads JSON-LD blocks plus microdata and RDFa attributes.
+func extractStructuredData(doc *goquery.Document) *model.StructuredData {
+	sd := &model.StructuredData{
+		Items:  []model.StructuredDataItem{},
+		Errors: []model.StructuredDataError{},
+	}
+	extractJSONLD(doc, sd)
+	extractAttributeItems(doc, sd, model.FormatMicrodata, "itemscope", "itemtype", "itemprop")
+	extractAttributeItems(doc, sd, model.FormatRDFa, "typeof", "typeof", "property")
+	return sd
+}
+
+func extractJSONLD(doc *goquery.Document, sd *model.StructuredData) {
+	doc.Find(`script[type="application/ld+json"]`).Each(func(i int, s *goquery.Selection) {
+		var v any
+		if err := json.Unmarshal([]byte(s.Text()), &v); err != nil {
+			sd.Errors = append(sd.Errors, model.StructuredDataError{
+				Format: model.FormatJSONLD, Block: i, Message: err.Error(),
+			})
+			return
+		}
+		before := len(sd.Items)
+		walkJSONLD(v, sd)
+		if len(sd.Items) == before {
+			sd.Errors = append(sd.Errors, model.StructuredDataError{
+				Format: model.FormatJSONLD, Block: i, Message: "no entity with @type",
+			})
+		}
+	})
+}
+
+// walkJSONLD records every top-level entity, following arrays and @graph containers.
+func walkJSONLD(v any, sd *model.StructuredData) {
+	switch t := v.(type) {
+	case []any:
+		for _, e := range t {
+			walkJSONLD(e, sd)
+		}
+	case map[string]any:
+		if g, ok := t["@graph"]; ok {
+			walkJSONLD(g, sd)
+		}
+		types := jsonLDTypes(t["@type"])
+		if len(types) == 0 {
+			return
+		}
+		var props []string
+		for k := range t {
+			if !strings.HasPrefix(k, "@") {
+				props = append(props, k)
+			}
+		}
+		sort.Strings(props)
+		for _, typ := range types {
+			sd.Items = append(sd.Items, model.StructuredDataItem{
+				Format: model.FormatJSONLD, Type: typ, Properties: nonNil(props),
+			})
+		}
+	}
+}
+
+func jsonLDTypes(v any) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{schemaType(t)}
+	case []any:
+		var out []string
+		for _, e := range t {
+			if s, ok := e.(string); ok {
+				out = append(out, schemaType(s))
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// extractAttributeItems handles microdata and RDFa, which share a shape: a scope element
+// carrying the type, and property attributes on descendants. A property belongs to the nearest
+// enclosing scope above it, so nested entities are attributed to their parent, not themselves.
+func extractAttributeItems(doc *goquery.Document, sd *model.StructuredData, format, scopeAttr, typeAttr, propAttr string) {
+	scopeSel := "[" + scopeAttr + "]"
+	doc.Find(scopeSel).Each(func(i int, scope *goquery.Selection) {
+		// Nested scopes that are themselves a property value are not top-level items.
+		if _, nested := scope.Attr(propAttr); nested && scope.Parent().Closest(scopeSel).Length() > 0 {
+			return
+		}
+		rawTypes := strings.Fields(scope.AttrOr(typeAttr, ""))
+		if len(rawTypes) == 0 {
+			sd.Errors = append(sd.Errors, model.StructuredDataError{
+				Format: format, Block: i, Message: "missing " + typeAttr,
+			})
+			return
+		}
+
+		owner := scope.Get(0)
+		seen := map[string]struct{}{}
+		var props []string
+		scope.Find("[" + propAttr + "]").Each(func(_ int, p *goquery.Selection) {
+			if closestNode(p.Parent(), scopeSel) != owner {
+				return
+			}
+			for _, name := range strings.Fields(p.AttrOr(propAttr, "")) {
+				name = schemaType(name)
+				if _, ok := seen[name]; !ok {
+					seen[name] = struct{}{}
+					props = append(props, name)
+				}
+			}
+		})
+		sort.Strings(props)
+		for _, t := range rawTypes {
+			sd.Items = append(sd.Items, model.StructuredDataItem{
+				Format: format, Type: schemaType(t), Properties: nonNil(props),
+			})
+		}
+	})
+}
+
+func closestNode(s *goquery.Selection, sel string) *html.Node {
+	c := s.Closest(sel)
+	if c.Length() == 0 {
+		return nil
+	}
+	return c.Get(0)
+}
+
+// schemaType strips the schema.org vocabulary from a type or property name.
+func schemaType(t string) string {
+	t = strings.TrimSpace(t)
+	for _, p := range []string{"https://schema.org/", "http://schema.org/", "schema:"} {
+		if strings.HasPrefix(t, p) {
+			return strings.TrimPrefix(t, p)
+		}
+	}
+	return t
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
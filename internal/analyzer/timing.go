@@ -0,0 +1,110 @@
+package analyzer
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+// fetchTimer measures the phases of one request via httptrace.
+type fetchTimer struct {
+	mu         sync.Mutex
+	start      time.Time
+	dnsStart   time.Time
+	connStarts map[string]time.Time // by address; dual-stack dials race several at once
+	tlsStart   time.Time
+	firstByte  time.Time
+	dns        time.Duration
+	connect    time.Duration
+	tls        time.Duration
+}
+
+func newFetchTimer() *fetchTimer {
+	return &fetchTimer{start: time.Now(), connStarts: make(map[string]time.Time)}
+}
+
+// withTrace attaches the timer's hooks to ctx.
+func (t *fetchTimer) withTrace(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.add(&t.dns, &t.dnsStart) },
+		ConnectStart:         t.connectStart,
+		ConnectDone:          t.connectDone,
+		TLSHandshakeStart:    func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.add(&t.tls, &t.tlsStart) },
+		GotFirstResponseByte: func() { t.mark(&t.firstByte) },
+	})
+}
+
+// reset starts the measurement over, for when a request is retried with another method.
+func (t *fetchTimer) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.start, t.firstByte = time.Now(), time.Time{}
+	t.connStarts = make(map[string]time.Time)
+	t.dns, t.connect, t.tls = 0, 0, 0
+}
+
+func (t *fetchTimer) mark(at *time.Time) {
+	t.mu.Lock()
+	*at = time.Now()
+	t.mu.Unlock()
+}
+
+// add adds the time elapsed since *since to *d; both are read under the lock mark writes with.
+func (t *fetchTimer) add(d *time.Duration, since *time.Time) {
+	t.mu.Lock()
+	*d += time.Since(*since)
+	t.mu.Unlock()
+}
+
+func (t *fetchTimer) connectStart(_, addr string) {
+	t.mu.Lock()
+	t.connStarts[addr] = time.Now()
+	t.mu.Unlock()
+}
+
+// connectDone counts the dial to addr if it succeeded; the attempts a dual-stack dial
+// abandons overlap the one that won and are not part of the connect phase.
+func (t *fetchTimer) connectDone(_, addr string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	start, ok := t.connStarts[addr]
+	delete(t.connStarts, addr)
+	if ok && err == nil {
+		t.connect += time.Since(start)
+	}
+}
+
+// ttfb is the time from the start of the request to the first byte of the last response.
+func (t *fetchTimer) ttfb() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.firstByte.IsZero() {
+		return 0
+	}
+	return t.firstByte.Sub(t.start).Milliseconds()
+}
+
+// page summarises the fetch once the body has been read at done.
+func (t *fetchTimer) page(done time.Time, size int64) model.PageTiming {
+	ttfb := t.ttfb()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	pt := model.PageTiming{
+		DNSMs:         t.dns.Milliseconds(),
+		ConnectMs:     t.connect.Milliseconds(),
+		TLSMs:         t.tls.Milliseconds(),
+		TTFBMs:        ttfb,
+		TotalMs:       done.Sub(t.start).Milliseconds(),
+		ResponseBytes: size,
+	}
+	if !t.firstByte.IsZero() {
+		pt.DownloadMs = done.Sub(t.firstByte).Milliseconds()
+	}
+	return pt
+}
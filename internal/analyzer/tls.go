@@ -0,0 +1,107 @@
+package analyzer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+// ErrUntrustedCertificate is returned when a page's certificate does not verify. The page
+// is not fetched; the analysis result carries only the TLS report.
+var ErrUntrustedCertificate = errors.New("untrusted TLS certificate")
+
+// errProbeDone aborts a probe handshake once the peer's chain has been recorded.
+var errProbeDone = errors.New("tls probe done")
+
+// dialFunc opens the TCP connection for a TLS probe.
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// probeTLS records the session and chain that u's server presents without verifying
+// them, for the report on a page whose certificate failed verification. The handshake is
+// aborted as soon as the chain is seen, so nothing is ever sent over the connection.
+func probeTLS(ctx context.Context, dial dialFunc, u *url.URL) (*tls.ConnectionState, error) {
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+	conn, err := dial(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var state *tls.ConnectionState
+	tc := tls.Client(conn, &tls.Config{
+		ServerName:         u.Hostname(),
+		InsecureSkipVerify: true, //nolint:gosec // the chain is only reported, never trusted
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			state = &cs
+			return errProbeDone
+		},
+	})
+	if err := tc.HandshakeContext(ctx); state == nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// inspectTLS records the negotiated session and peer chain. The chain is verified here
+// against roots (nil means the system pool) so that the report says why a probed chain
+// failed.
+func inspectTLS(cs *tls.ConnectionState, host string, roots *x509.CertPool) *model.TLSReport {
+	if cs == nil || len(cs.PeerCertificates) == 0 {
+		return nil
+	}
+	r := &model.TLSReport{
+		Version:     tls.VersionName(cs.Version),
+		CipherSuite: tls.CipherSuiteName(cs.CipherSuite),
+		Chain:       make([]model.CertificateInfo, 0, len(cs.PeerCertificates)),
+	}
+	for _, c := range cs.PeerCertificates {
+		r.Chain = append(r.Chain, model.CertificateInfo{
+			Subject:      c.Subject.String(),
+			Issuer:       c.Issuer.String(),
+			DNSNames:     append([]string{}, c.DNSNames...),
+			SerialNumber: c.SerialNumber.String(),
+			KeyType:      keyType(c),
+			NotBefore:    c.NotBefore,
+			NotAfter:     c.NotAfter,
+		})
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, c := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	r.Verified = err == nil
+	if err != nil {
+		r.VerifyError = err.Error()
+	}
+	return r
+}
+
+func keyType(c *x509.Certificate) string {
+	switch k := c.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA-%d", k.N.BitLen())
+	case *ecdsa.PublicKey:
+		return "ECDSA-" + k.Curve.Params().Name
+	case ed25519.PublicKey:
+		return "Ed25519"
+	}
+	return c.PublicKeyAlgorithm.String()
+}
@@ -17,11 +17,15 @@ import (
 	"github.com/fuzumoe/urlinsight-backend/internal/analyzer"
 	"github.com/fuzumoe/urlinsight-backend/internal/crawler"
 	"github.com/fuzumoe/urlinsight-backend/internal/handler"
+	"github.com/fuzumoe/urlinsight-backend/internal/importer"
 	"github.com/fuzumoe/urlinsight-backend/internal/middleware"
 	"github.com/fuzumoe/urlinsight-backend/internal/model"
 	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+	"github.com/fuzumoe/urlinsight-backend/internal/scheduler"
 	"github.com/fuzumoe/urlinsight-backend/internal/server"
 	"github.com/fuzumoe/urlinsight-backend/internal/service"
+	"github.com/fuzumoe/urlinsight-backend/internal/stream"
+	"github.com/fuzumoe/urlinsight-backend/internal/webhook"
 )
 
 // hookable for tests.
@@ -60,6 +64,11 @@ func Run() error {
 	userRepo := repository.NewUserRepo(db)
 	authRepo := repository.NewTokenRepo(db)
 	urlRepo := repository.NewURLRepo(db)
+	jobRepo := repository.NewCrawlJobRepo(db)
+	scheduleRepo := repository.NewScheduleRepo(db)
+	analysisRepo := repository.NewAnalysisResultRepo(db)
+	webhookRepo := repository.NewWebhookRepo(db)
+	exportRepo := repository.NewExportRepo(db)
 
 	// Instantiate services.
 	healthSvc := service.NewHealthService(db, "URLInsight Backend")
@@ -71,11 +80,41 @@ func Run() error {
 		cfg.JWTLifetime,
 	)
 
+	// Webhook deliveries and sitemap imports go through the same internal-address guard as crawling.
+	var guarded http.RoundTripper
+	if cfg.BlockPrivateIPs {
+		guarded = analyzer.GuardedTransport(cfg.AllowedHosts)
+	}
+	webhookOpts := webhook.Options{MaxAttempts: cfg.WebhookMaxAttempts, Timeout: cfg.WebhookTimeout, Transport: guarded}
+	webhookDispatcher := webhook.New(webhookRepo, webhookOpts)
+
+	// Live progress of this instance's crawls, streamed to clients over SSE.
+	progressBroker := stream.NewBroker()
+
 	// Initialize analyzers and crawlers.
-	htmlAnalyzer := analyzer.NewHTMLAnalyzer()
-	crawlerPool := crawler.New(urlRepo, htmlAnalyzer, cfg.NumberOfCrawlers, cfg.MaxConcurrentCrawls, cfg.CrawlTimeout)
+	htmlAnalyzer := analyzer.NewHTMLAnalyzerWithConfig(analyzer.Config{
+		UserAgent:       cfg.UserAgent,
+		HostConcurrency: cfg.HostConcurrency,
+		HostRPS:         cfg.HostRPS,
+		MaxBodyBytes:    cfg.MaxBodyBytes,
+		BlockPrivateIPs: cfg.BlockPrivateIPs,
+		AllowedHosts:    cfg.AllowedHosts,
+	})
+	crawlerPool := crawler.NewWithOptions(urlRepo, htmlAnalyzer, cfg.NumberOfCrawlers, cfg.MaxConcurrentCrawls, cfg.CrawlTimeout, crawler.Options{
+		MaxSiteDepth: cfg.SiteCrawlMaxDepth,
+		MaxSitePages: cfg.SiteCrawlMaxPages,
+		Queue:        crawler.NewDBQueue(jobRepo, cfg.QueueLease, cfg.QueuePollInterval, cfg.QueueMaxAttempts),
+		Notifier:     webhookDispatcher,
+		Progress:     progressBroker,
+	})
 
 	urlSvc := service.NewURLService(urlRepo, crawlerPool)
+	bulkSvc := service.NewBulkService(urlRepo, urlSvc, importer.NewSitemapFetcher(guarded, cfg.UserAgent))
+	scheduleSvc := service.NewScheduleService(scheduleRepo, urlRepo)
+	analysisSvc := service.NewAnalysisService(analysisRepo)
+	webhookSvc := service.NewWebhookService(webhookRepo, webhookDispatcher)
+	exportSvc := service.NewExportService(exportRepo)
+	urlScheduler := scheduler.New(scheduleRepo, urlSvc, cfg.SchedulerTick)
 
 	// Create a cancellable context for graceful shutdown.
 	ctx, cancel := context.WithCancel(context.Background())
@@ -84,6 +123,12 @@ func Run() error {
 	// Start the crawler pool in its own goroutine using the external context.
 	go crawlerPool.Start(ctx)
 
+	// Start the scheduler that re-queues URLs on their recurring schedules.
+	go urlScheduler.Start(ctx)
+
+	// Start sending queued webhook deliveries.
+	go webhookDispatcher.Start(ctx)
+
 	// Set up signal handling to cancel the context on termination signals.
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
@@ -146,6 +191,12 @@ func Run() error {
 	healthH := handler.NewHealthHandler(healthSvc)
 	authH := handler.NewAuthHandler(authSVC, userSvc)
 	urlH := handler.NewURLHandler(urlSvc)
+	bulkH := handler.NewBulkHandler(bulkSvc)
+	scheduleH := handler.NewScheduleHandler(scheduleSvc)
+	analysisH := handler.NewAnalysisHandler(analysisSvc)
+	webhookH := handler.NewWebhookHandler(webhookSvc)
+	streamH := handler.NewStreamHandler(urlSvc, progressBroker, 15*time.Second)
+	exportH := handler.NewExportHandler(exportSvc)
 
 	// Build router and register routes.
 	router := gin.New()
@@ -164,6 +215,24 @@ func Run() error {
 			// Register URL routes (assumed to be protected).
 			urlH.RegisterProtectedRoutes(rg)
 		}),
+		RouteRegistrarFunc(func(rg *gin.RouterGroup) {
+			bulkH.RegisterProtectedRoutes(rg)
+		}),
+		RouteRegistrarFunc(func(rg *gin.RouterGroup) {
+			scheduleH.RegisterProtectedRoutes(rg)
+		}),
+		RouteRegistrarFunc(func(rg *gin.RouterGroup) {
+			analysisH.RegisterProtectedRoutes(rg)
+		}),
+		RouteRegistrarFunc(func(rg *gin.RouterGroup) {
+			webhookH.RegisterProtectedRoutes(rg)
+		}),
+		RouteRegistrarFunc(func(rg *gin.RouterGroup) {
+			streamH.RegisterProtectedRoutes(rg)
+		}),
+		RouteRegistrarFunc(func(rg *gin.RouterGroup) {
+			exportH.RegisterProtectedRoutes(rg)
+		}),
 	}
 	server.RegisterRoutes(
 		router,
@@ -0,0 +1,62 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// StopCause is the cancellation cause attached to an analysis stopped on request.
+type StopCause struct {
+	KeepPartial bool // Persist whatever was collected before the stop instead of discarding it.
+}
+
+func (StopCause) Error() string { return "analysis stopped on request" }
+
+// stopCause returns the StopCause ctx was cancelled with, if any.
+func stopCause(ctx context.Context) (StopCause, bool) {
+	var sc StopCause
+	if errors.As(context.Cause(ctx), &sc) {
+		return sc, true
+	}
+	return sc, false
+}
+
+// runRegistry tracks the cancel func of every analysis running in this process.
+type runRegistry struct {
+	mu      sync.Mutex
+	cancels map[uint]context.CancelCauseFunc
+}
+
+func newRunRegistry() *runRegistry {
+	return &runRegistry{cancels: make(map[uint]context.CancelCauseFunc)}
+}
+
+// begin derives a cancellable context for the URL's analysis; the returned func
+// must be called once the analysis is over. A nil registry only derives the context.
+func (r *runRegistry) begin(parent context.Context, id uint) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(parent)
+	if r == nil {
+		return ctx, func() { cancel(nil) }
+	}
+	r.mu.Lock()
+	r.cancels[id] = cancel
+	r.mu.Unlock()
+	return ctx, func() {
+		r.mu.Lock()
+		delete(r.cancels, id)
+		r.mu.Unlock()
+		cancel(nil)
+	}
+}
+
+// cancel aborts the URL's running analysis with cause and reports whether one was running.
+func (r *runRegistry) cancel(id uint, cause error) bool {
+	r.mu.Lock()
+	cancel, ok := r.cancels[id]
+	r.mu.Unlock()
+	if ok {
+		cancel(cause)
+	}
+	return ok
+}
@@ -2,23 +2,57 @@ package crawler
 
 import (
 	"context"
+	"errors"
 	"log"
 	"sync"
 	"time"
 
 	"github.com/fuzumoe/urlinsight-backend/internal/analyzer"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
 	"github.com/fuzumoe/urlinsight-backend/internal/repository"
 )
 
 // Pool defines the interface for a crawler pool that manages multiple workers.
 type Pool interface {
 	Start(ctx context.Context)
-	Enqueue(id uint)
+	Enqueue(id uint) error
+	// Cancel aborts the URL's analysis if it is running in this pool and reports whether it was.
+	Cancel(id uint, keepPartial bool) bool
 	Shutdown()
 }
 
+const (
+	defaultMaxSiteDepth = 3
+	defaultMaxSitePages = 100
+)
+
+// Notifier is told how every analysis that ran ended; webhook.Dispatcher satisfies it.
+type Notifier interface {
+	AnalysisFinished(e model.AnalysisEvent)
+}
+
+// ProgressReporter receives live status changes and progress of analyses; stream.Broker
+// satisfies it. Report must not block.
+type ProgressReporter interface {
+	Report(e model.ProgressEvent)
+}
+
+// Options tunes optional crawler behaviour.
+type Options struct {
+	MaxSiteDepth int              // Upper bound (and default) for links followed away from the root in site mode.
+	MaxSitePages int              // Upper bound (and default) for pages analyzed per site crawl.
+	Queue        Queue            // Job store; nil selects an in-memory queue sized by the pool's buffer.
+	Notifier     Notifier         // Told when an analysis is done, failed or stopped; nil tells no one.
+	Progress     ProgressReporter // Told of status changes and progress while analyses run; may be nil.
+}
+
 // New creates a new crawler pool with the specified number of workers and buffer size.
 func New(repo repository.URLRepository, a analyzer.Analyzer, workers, buf int, crawlTimeout time.Duration) Pool {
+	return NewWithOptions(repo, a, workers, buf, crawlTimeout, Options{})
+}
+
+// NewWithOptions creates a new crawler pool like New, applying the given options.
+func NewWithOptions(repo repository.URLRepository, a analyzer.Analyzer, workers, buf int, crawlTimeout time.Duration, opts Options) Pool {
 	if workers <= 0 {
 		workers = 4
 	}
@@ -28,6 +62,15 @@ func New(repo repository.URLRepository, a analyzer.Analyzer, workers, buf int, c
 	if crawlTimeout <= 0 {
 		crawlTimeout = 30 * time.Second
 	}
+	if opts.MaxSiteDepth <= 0 {
+		opts.MaxSiteDepth = defaultMaxSiteDepth
+	}
+	if opts.MaxSitePages <= 0 {
+		opts.MaxSitePages = defaultMaxSitePages
+	}
+	if opts.Queue == nil {
+		opts.Queue = NewMemoryQueue(buf)
+	}
 
 	// Start with a background context.
 	ctx, cancel := context.WithCancel(context.Background())
@@ -36,10 +79,12 @@ func New(repo repository.URLRepository, a analyzer.Analyzer, workers, buf int, c
 		repo:         repo,
 		analyzer:     a,
 		workers:      workers,
-		tasks:        make(chan uint, buf),
+		queue:        opts.Queue,
 		ctx:          ctx,
 		cancel:       cancel,
 		crawlTimeout: crawlTimeout,
+		opts:         opts,
+		runs:         newRunRegistry(),
 	}
 }
 
@@ -48,11 +93,14 @@ type pool struct {
 	repo         repository.URLRepository
 	analyzer     analyzer.Analyzer
 	workers      int
-	tasks        chan uint
+	queue        Queue
 	ctx          context.Context
 	cancel       context.CancelFunc
 	wg           sync.WaitGroup
 	crawlTimeout time.Duration
+	opts         Options
+	runs         *runRegistry
+	shutdownOnce sync.Once
 }
 
 // Start initializes the workers and begins processing tasks.
@@ -61,16 +109,26 @@ func (p *pool) Start(ctx context.Context) {
 	childCtx, cancel := context.WithCancel(ctx)
 	// Overwrite our internal context with the child context.
 	p.ctx = childCtx
+	p.cancel = cancel
 	// Ensure that when Start() exits, we cancel the child context.
 	defer cancel()
 
+	// Pick up whatever a previous process left behind before taking new work.
+	if r, ok := p.queue.(recoverer); ok {
+		if err := r.Recover(); err != nil {
+			log.Printf("[crawler] recovery failed: %v", err)
+		}
+	}
+
 	// Spin up workers.
 	for i := 0; i < p.workers; i++ {
 		w := newWorker(i+1, p.ctx, p.repo, p.analyzer, p.crawlTimeout)
+		w.opts = p.opts
+		w.runs = p.runs
 		p.wg.Add(1)
 		go func() {
 			defer p.wg.Done()
-			w.run(p.tasks)
+			p.consume(w)
 		}()
 	}
 
@@ -79,19 +137,92 @@ func (p *pool) Start(ctx context.Context) {
 	p.Shutdown()
 }
 
-// Enqueue drops a URL-row ID onto the buffered channel.
-func (p *pool) Enqueue(id uint) {
-	select {
-	case <-p.ctx.Done():
-	case p.tasks <- id:
-	default:
-		log.Printf("[crawler] queue full – dropping id=%d", id)
+// consume claims jobs from the queue and hands them to the worker until the pool stops.
+func (p *pool) consume(w *worker) {
+	for {
+		job, err := p.queue.Pop(p.ctx)
+		if err != nil {
+			if p.ctx.Err() != nil || errors.Is(err, ErrQueueClosed) {
+				return
+			}
+			log.Printf("[crawler:%d] %v", w.id, err)
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		if job.URLID == 0 {
+			_ = p.queue.Ack(job)
+			continue
+		}
+		p.runJob(w, job)
 	}
 }
 
-// Shutdown cancels the context, waits for all workers to finish, and then closes the tasks channel.
+// runJob processes one claimed job, keeping its lease alive, and acknowledges it afterwards.
+// A job interrupted by shutdown is released so the next process picks it up again; the
+// in-memory queue cannot hand it on, so its URL is marked as stopped instead.
+func (p *pool) runJob(w *worker, job *Job) {
+	done := make(chan struct{})
+	if lk, ok := p.queue.(leaseKeeper); ok {
+		go keepLease(lk, job, done)
+	}
+	interrupted := w.process(job.URLID)
+	close(done)
+
+	if interrupted {
+		if err := p.queue.Release(job); err != nil {
+			log.Printf("[crawler:%d] id=%d – release: %v", w.id, job.URLID, err)
+		}
+		if _, durable := p.queue.(recoverer); !durable {
+			_ = p.repo.UpdateStatus(job.URLID, model.StatusStopped)
+		}
+		return
+	}
+	if err := p.queue.Ack(job); err != nil {
+		log.Printf("[crawler:%d] id=%d – ack: %v", w.id, job.URLID, err)
+	}
+}
+
+// keepLease renews the job's lease until done is closed.
+func keepLease(lk leaseKeeper, job *Job, done <-chan struct{}) {
+	t := time.NewTicker(lk.TouchInterval())
+	defer t.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+			if err := lk.Touch(job); err != nil {
+				log.Printf("[crawler] id=%d – lease renewal: %v", job.URLID, err)
+			}
+		}
+	}
+}
+
+// Enqueue adds a URL-row ID to the queue.
+func (p *pool) Enqueue(id uint) error {
+	if err := p.queue.Push(id); err != nil {
+		return err
+	}
+	reportStatus(p.opts.Progress, id, model.StatusQueued, "")
+	return nil
+}
+
+// Cancel aborts a running analysis; the worker records it as stopped.
+func (p *pool) Cancel(id uint, keepPartial bool) bool {
+	return p.runs.cancel(id, StopCause{KeepPartial: keepPartial})
+}
+
+// Shutdown cancels the context, waits for all workers to finish, and then closes the queue.
 func (p *pool) Shutdown() {
-	p.cancel()
-	p.wg.Wait()
-	close(p.tasks)
+	p.shutdownOnce.Do(func() {
+		p.cancel()
+		p.wg.Wait()
+		if c, ok := p.queue.(interface{ Close() }); ok {
+			c.Close()
+		}
+	})
 }
@@ -0,0 +1,59 @@
+package crawler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/analyzer"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+// progressInterval is the least time between two link-progress reports for one page.
+const progressInterval = 250 * time.Millisecond
+
+// reportStatus tells r, if any, that the URL's status changed.
+func reportStatus(r ProgressReporter, id uint, status, errMsg string) {
+	if r == nil {
+		return
+	}
+	r.Report(model.ProgressEvent{URLID: id, Type: model.ProgressStatus, Status: status, Error: errMsg, At: time.Now()})
+}
+
+// report passes e to the pool's progress reporter, if any.
+func (w *worker) report(e model.ProgressEvent) {
+	if w.opts.Progress == nil {
+		return
+	}
+	e.At = time.Now()
+	w.opts.Progress.Report(e)
+}
+
+// withLinkProgress reports the link checks of pageURL as they finish, at most once per
+// progressInterval apart from the last one. pagesCrawled is the count before this page.
+func (w *worker) withLinkProgress(ctx context.Context, id uint, pageURL string, pagesCrawled int) context.Context {
+	if w.opts.Progress == nil {
+		return ctx
+	}
+	var mu sync.Mutex
+	var last time.Time
+	reported := 0
+	return analyzer.WithLinkProgress(ctx, func(checked, total int) {
+		mu.Lock()
+		defer mu.Unlock()
+		// Checks finish concurrently, so a lower count can arrive after a higher one.
+		if checked <= reported || (checked < total && time.Since(last) < progressInterval) {
+			return
+		}
+		last, reported = time.Now(), checked
+		w.report(model.ProgressEvent{
+			URLID:        id,
+			Type:         model.ProgressUpdate,
+			Status:       model.StatusRunning,
+			PageURL:      pageURL,
+			PagesCrawled: pagesCrawled,
+			LinksChecked: checked,
+			LinksTotal:   total,
+		})
+	})
+}
@@ -0,0 +1,193 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+)
+
+var (
+	// ErrQueueFull is returned by the in-memory queue when its buffer is exhausted.
+	ErrQueueFull = errors.New("crawl queue is full")
+	// ErrQueueClosed is returned once the queue has been shut down.
+	ErrQueueClosed = errors.New("crawl queue is closed")
+)
+
+// Job is a unit of work claimed from a Queue.
+type Job struct {
+	ID    uint // Queue-specific job ID (zero for the in-memory queue).
+	URLID uint
+}
+
+// Queue is the store the pool claims URL analyses from.
+type Queue interface {
+	// Push adds a URL to the queue.
+	Push(urlID uint) error
+	// Pop blocks until a job is available or ctx is done.
+	Pop(ctx context.Context) (*Job, error)
+	// Ack marks a job as handled.
+	Ack(job *Job) error
+	// Release hands an unfinished job back so it is picked up again.
+	Release(job *Job) error
+}
+
+// leaseKeeper is implemented by queues whose claims expire unless renewed.
+type leaseKeeper interface {
+	Touch(job *Job) error
+	TouchInterval() time.Duration
+}
+
+// recoverer is implemented by queues that can repair state left by a crashed process.
+type recoverer interface {
+	Recover() error
+}
+
+// memoryQueue is a bounded in-process queue; its contents do not survive a restart.
+type memoryQueue struct {
+	mu     sync.RWMutex
+	ch     chan uint
+	closed bool
+}
+
+// NewMemoryQueue creates an in-memory queue that holds at most buf pending IDs.
+func NewMemoryQueue(buf int) Queue {
+	if buf <= 0 {
+		buf = 128
+	}
+	return &memoryQueue{ch: make(chan uint, buf)}
+}
+
+func (q *memoryQueue) Push(urlID uint) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	select {
+	case q.ch <- urlID:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (q *memoryQueue) Pop(ctx context.Context) (*Job, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case id, ok := <-q.ch:
+		if !ok {
+			return nil, ErrQueueClosed
+		}
+		return &Job{URLID: id}, nil
+	}
+}
+
+func (q *memoryQueue) Ack(*Job) error     { return nil }
+func (q *memoryQueue) Release(*Job) error { return nil }
+
+// Close stops accepting new IDs and wakes up blocked consumers.
+func (q *memoryQueue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.closed {
+		q.closed = true
+		close(q.ch)
+	}
+}
+
+// dbQueue is a durable queue backed by the crawl_jobs table.
+type dbQueue struct {
+	repo        repository.CrawlJobRepository
+	owner       string
+	lease       time.Duration
+	poll        time.Duration
+	maxAttempts int
+	wake        chan struct{}
+}
+
+// NewDBQueue creates a durable queue. Claimed jobs are leased for lease and renewed while
+// they are being processed; idle consumers poll for new jobs every poll interval.
+func NewDBQueue(repo repository.CrawlJobRepository, lease, poll time.Duration, maxAttempts int) Queue {
+	if lease <= 0 {
+		lease = 2 * time.Minute
+	}
+	if poll <= 0 {
+		poll = time.Second
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	host, _ := os.Hostname()
+	return &dbQueue{
+		repo:        repo,
+		owner:       fmt.Sprintf("%s-%d-%d", host, os.Getpid(), time.Now().UnixNano()),
+		lease:       lease,
+		poll:        poll,
+		maxAttempts: maxAttempts,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+func (q *dbQueue) Push(urlID uint) error {
+	if err := q.repo.Enqueue(urlID); err != nil {
+		return fmt.Errorf("enqueue url %d: %w", urlID, err)
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (q *dbQueue) Pop(ctx context.Context) (*Job, error) {
+	for {
+		job, err := q.repo.Claim(q.owner, q.lease, q.maxAttempts)
+		if err != nil {
+			return nil, fmt.Errorf("claim job: %w", err)
+		}
+		if job != nil {
+			return &Job{ID: job.ID, URLID: job.URLID}, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-q.wake:
+		case <-time.After(q.poll):
+		}
+	}
+}
+
+func (q *dbQueue) Ack(job *Job) error {
+	return q.repo.Complete(job.ID, q.owner)
+}
+
+func (q *dbQueue) Release(job *Job) error {
+	return q.repo.Release(job.ID, q.owner)
+}
+
+func (q *dbQueue) Touch(job *Job) error {
+	return q.repo.Extend(job.ID, q.owner, q.lease)
+}
+
+// TouchInterval renews leases well before they expire.
+func (q *dbQueue) TouchInterval() time.Duration {
+	return q.lease / 3
+}
+
+func (q *dbQueue) Recover() error {
+	n, err := q.repo.Recover(q.maxAttempts)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("[crawler] recovered %d interrupted job(s)", n)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/analyzer"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+// crawlItem is a page waiting to be analyzed during a site crawl.
+type crawlItem struct {
+	u     *url.URL
+	depth int
+}
+
+// processSite crawls the URL's site breadth-first and persists every page plus a summary.
+// A stop request that asks to keep partial results persists the pages crawled so far.
+func (w *worker) processSite(ctx context.Context, id uint, rec *model.URL, logf func(string, ...any)) (interrupted bool) {
+	start := time.Now()
+
+	pages, summary, err := w.crawlSite(ctx, rec)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			if w.interrupted(ctx) {
+				logf("site crawl interrupted by shutdown")
+				return true
+			}
+			if sc, ok := stopCause(ctx); ok && sc.KeepPartial && len(pages) > 0 {
+				if err := w.repo.SaveSiteResults(id, pages, summary); err != nil {
+					logf("save partial site: %v", err)
+				}
+			}
+			_ = w.repo.UpdateStatus(id, model.StatusStopped)
+			w.notify(analysisEvent(id, rec, model.StatusStopped, nil))
+			logf("site crawl stopped by timeout or cancellation")
+			return false
+		}
+		if errors.Is(err, analyzer.ErrUntrustedCertificate) && len(pages) > 0 {
+			if err := w.repo.SaveSiteResults(id, pages, summary); err != nil {
+				logf("save certificate report: %v", err)
+			}
+		}
+		setErr(w.repo, id, err)
+		w.notify(analysisEvent(id, rec, model.StatusError, err))
+		logf("site crawl: %v", err)
+		return false
+	}
+
+	if sc, ok := stopCause(ctx); ok && !sc.KeepPartial {
+		_ = w.repo.UpdateStatus(id, model.StatusStopped)
+		w.notify(analysisEvent(id, rec, model.StatusStopped, nil))
+		logf("site crawl stopped on request")
+		return false
+	}
+
+	if err := w.repo.SaveSiteResults(id, pages, summary); err != nil {
+		setErr(w.repo, id, err)
+		w.notify(analysisEvent(id, rec, model.StatusError, err))
+		logf("save site: %v", err)
+		return false
+	}
+
+	updated, err := w.repo.FindByID(id)
+	if err != nil {
+		logf("lookup after site crawl failed: %v", err)
+		return false
+	}
+	e := analysisEvent(id, rec, model.StatusStopped, nil)
+	if updated.Status != model.StatusStopped {
+		_ = w.repo.UpdateStatus(id, model.StatusDone)
+		e.Status = model.StatusDone
+	}
+	e.PagesCrawled, e.BrokenLinkCount = summary.PagesCrawled, summary.BrokenLinkCount
+	if len(pages) > 0 {
+		// Assertions only run on the root page, so its health is the site's.
+		e.Health = pages[0].Result.Health
+	}
+	w.notify(e)
+	logf("site done in %s (pages=%d failed=%d)",
+		time.Since(start).Truncate(time.Millisecond), summary.PagesCrawled, summary.PagesFailed)
+	return false
+}
+
+// crawlSite follows internal links from the root URL up to the depth and page budget.
+// Each page gets its own crawl timeout; a failing root page fails the whole crawl.
+// On cancellation the pages crawled so far are returned along with the error.
+func (w *worker) crawlSite(ctx context.Context, rec *model.URL) ([]model.PageResult, *model.SiteSummary, error) {
+	root := rec.URL()
+	if root == nil || root.Host == "" {
+		return nil, nil, fmt.Errorf("invalid url %q", rec.OriginalURL)
+	}
+	maxDepth, maxPages := w.siteLimits(rec)
+
+	root.Fragment = ""
+	queue := []crawlItem{{u: root}}
+	seen := map[string]struct{}{root.String(): {}}
+	summary := &model.SiteSummary{}
+	var pages []model.PageResult
+
+	for len(queue) > 0 && len(pages) < maxPages {
+		if err := ctx.Err(); err != nil {
+			return pages, summary, err
+		}
+		next := queue[0]
+		queue = queue[1:]
+
+		pageCtx, cancel := context.WithTimeout(ctx, w.crawlTimeout)
+		if next.depth > 0 {
+			// Assertions describe the URL itself, not every page it links to.
+			pageCtx = analyzer.WithAssertions(pageCtx, nil)
+		}
+		pageCtx = w.withLinkProgress(pageCtx, rec.ID, next.u.String(), len(pages))
+		res, links, err := w.analyzer.Analyze(pageCtx, next.u)
+		cancel()
+		if timedOut(ctx, res, err) {
+			res.LinksIncomplete = true
+			err = nil
+		}
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return pages, summary, ctxErr
+			}
+			if next.depth == 0 {
+				// An untrusted root certificate fails the crawl but keeps its TLS report.
+				if errors.Is(err, analyzer.ErrUntrustedCertificate) && res != nil {
+					res.PageURL = next.u.String()
+					return []model.PageResult{{Result: res}}, &model.SiteSummary{}, err
+				}
+				return nil, nil, err
+			}
+			// Links to images, PDFs and the like are not pages; they are not failures either.
+			if errors.Is(err, analyzer.ErrUnsupportedContentType) {
+				continue
+			}
+			summary.PagesFailed++
+			continue
+		}
+
+		res.PageURL = next.u.String()
+		res.Depth = next.depth
+		summary.Add(res)
+		pages = append(pages, model.PageResult{Result: res, Links: links})
+		w.report(model.ProgressEvent{
+			URLID:        rec.ID,
+			Type:         model.ProgressUpdate,
+			Status:       model.StatusRunning,
+			PageURL:      res.PageURL,
+			PagesCrawled: len(pages),
+		})
+
+		if next.depth >= maxDepth {
+			continue
+		}
+		// Pages robots.txt keeps us from checking are not crawled either.
+		disallowed := map[string]bool{}
+		if res.Robots != nil {
+			for _, href := range res.Robots.SkippedLinks {
+				disallowed[href] = true
+			}
+		}
+		for _, l := range links {
+			if l.IsExternal || !analyzer.SameHost(root, l.Href) || l.IsBroken() || disallowed[l.Href] {
+				continue
+			}
+			u, err := url.Parse(l.Href)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+				continue
+			}
+			u.Fragment = ""
+			key := u.String()
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			queue = append(queue, crawlItem{u: u, depth: next.depth + 1})
+		}
+	}
+	return pages, summary, nil
+}
+
+// siteLimits resolves the URL's depth and page budget against the pool-wide caps.
+func (w *worker) siteLimits(rec *model.URL) (depth, pages int) {
+	depth, pages = w.opts.MaxSiteDepth, w.opts.MaxSitePages
+	if depth <= 0 {
+		depth = defaultMaxSiteDepth
+	}
+	if pages <= 0 {
+		pages = defaultMaxSitePages
+	}
+	if rec.MaxDepth > 0 && rec.MaxDepth < depth {
+		depth = rec.MaxDepth
+	}
+	if rec.MaxPages > 0 && rec.MaxPages < pages {
+		pages = rec.MaxPages
+	}
+	return depth, pages
+}
@@ -20,6 +20,8 @@ type worker struct {
 	repo         repository.URLRepository
 	analyzer     analyzer.Analyzer
 	crawlTimeout time.Duration
+	opts         Options
+	runs         *runRegistry
 }
 
 // newWorker creates a new worker instance with crawlTimeout.
@@ -61,67 +63,150 @@ func (w *worker) Run(tasks <-chan uint) {
 	w.run(tasks)
 }
 
-// process handles a single URL analysis task.
-func (w *worker) process(id uint) {
+// process handles a single URL analysis task. It reports whether the pool shutting down
+// interrupted the analysis; the URL's status is then left for the queue to settle.
+func (w *worker) process(id uint) (interrupted bool) {
 	logf := func(fmtStr string, v ...any) {
 		log.Printf("[crawler:%d] id=%d – "+fmtStr, append([]any{id}, v...)...)
 	}
 
-	// Update status to running.
-	if err := w.repo.UpdateStatus(id, model.StatusRunning); err != nil {
-		logf("cannot set running: %v", err)
-		return
-	}
-
 	// Fetch the record.
 	rec, err := w.repo.FindByID(id)
 	if err != nil {
 		setErr(w.repo, id, err)
 		logf("lookup: %v", err)
-		return
+		return false
 	}
 
-	// Allow a stop request to take precedence.
+	// Allow a stop request issued while the URL was queued to take precedence.
 	if rec.Status == model.StatusStopped {
 		logf("aborting analysis because status is 'stopped'")
-		return
+		return false
+	}
+
+	// Update status to running.
+	if err := w.repo.UpdateStatus(id, model.StatusRunning); err != nil {
+		logf("cannot set running: %v", err)
+		return false
+	}
+	reportStatus(w.opts.Progress, id, model.StatusRunning, "")
+
+	// Register the analysis so a stop request can cancel it.
+	runCtx, finish := w.runs.begin(w.ctx, id)
+	defer finish()
+	// The URL's custom headers, cookies, module selection and assertions apply to the whole run.
+	runCtx = analyzer.WithRequestOptions(runCtx, rec.RequestOptions())
+	runCtx = analyzer.WithModules(runCtx, rec.Modules)
+	runCtx = analyzer.WithAssertions(runCtx, rec.Assertions)
+
+	if rec.CrawlMode == model.CrawlModeSite {
+		return w.processSite(runCtx, id, rec, logf)
 	}
 
 	// Create a context with the worker's crawl timeout.
-	timeoutCtx, cancel := context.WithTimeout(w.ctx, w.crawlTimeout)
+	timeoutCtx, cancel := context.WithTimeout(runCtx, w.crawlTimeout)
 	defer cancel()
+	timeoutCtx = w.withLinkProgress(timeoutCtx, id, rec.OriginalURL, 0)
 
 	start := time.Now()
 
 	// Perform the analysis.
 	res, links, err := w.analyzer.Analyze(timeoutCtx, rec.URL())
+	if timedOut(runCtx, res, err) {
+		res.LinksIncomplete = true
+		logf("crawl timeout reached while checking links; saving what was checked")
+		err = nil
+	}
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			if w.interrupted(runCtx) {
+				logf("interrupted by shutdown")
+				return true
+			}
+			if sc, ok := stopCause(runCtx); ok && sc.KeepPartial && res != nil {
+				if err := w.repo.SaveResults(id, res, links); err != nil {
+					logf("save partial: %v", err)
+				}
+			}
 			_ = w.repo.UpdateStatus(id, model.StatusStopped)
+			w.notify(analysisEvent(id, rec, model.StatusStopped, nil))
 			logf("stopped by timeout or cancellation")
-			return
+			return false
+		}
+		// An untrusted certificate still leaves a snapshot with its TLS report behind.
+		if errors.Is(err, analyzer.ErrUntrustedCertificate) && res != nil {
+			if err := w.repo.SaveResults(id, res, nil); err != nil {
+				logf("save certificate report: %v", err)
+			}
 		}
 		setErr(w.repo, id, err)
+		w.notify(analysisEvent(id, rec, model.StatusError, err))
 		logf("analyze: %v", err)
-		return
+		return false
+	}
+
+	// A stop that raced with the end of the analysis still discards the results.
+	if sc, ok := stopCause(runCtx); ok && !sc.KeepPartial {
+		_ = w.repo.UpdateStatus(id, model.StatusStopped)
+		w.notify(analysisEvent(id, rec, model.StatusStopped, nil))
+		logf("stopped on request")
+		return false
 	}
 
 	// Persist results.
 	if err := w.repo.SaveResults(id, res, links); err != nil {
 		setErr(w.repo, id, err)
+		w.notify(analysisEvent(id, rec, model.StatusError, err))
 		logf("save: %v", err)
-		return
+		return false
 	}
 
 	updated, err := w.repo.FindByID(id)
 	if err != nil {
 		logf("lookup after analysis failed: %v", err)
-		return
+		return false
 	}
+	e := analysisEvent(id, rec, model.StatusStopped, nil)
 	if updated.Status != model.StatusStopped {
 		_ = w.repo.UpdateStatus(id, model.StatusDone)
+		e.Status = model.StatusDone
 	}
+	e.PagesCrawled, e.BrokenLinkCount, e.Health = 1, res.BrokenLinkCount, res.Health
+	w.notify(e)
 	logf("done in %s (links=%d)", time.Since(start).Truncate(time.Millisecond), len(links))
+	return false
+}
+
+// timedOut reports whether only the crawl timeout, not a stop or shutdown, cut short an
+// analysis that had already fetched its page, so the result is worth keeping.
+func timedOut(runCtx context.Context, res *model.AnalysisResult, err error) bool {
+	return res != nil && errors.Is(err, context.DeadlineExceeded) && runCtx.Err() == nil
+}
+
+// interrupted reports whether the analysis running under runCtx was cancelled by the pool
+// shutting down rather than by a stop request or its timeout.
+func (w *worker) interrupted(runCtx context.Context) bool {
+	_, stopped := stopCause(runCtx)
+	return !stopped && w.ctx.Err() != nil
+}
+
+// analysisEvent describes how the analysis of rec ended.
+func analysisEvent(id uint, rec *model.URL, status string, err error) model.AnalysisEvent {
+	e := model.AnalysisEvent{URLID: id, UserID: rec.UserID, OriginalURL: rec.OriginalURL, Status: status}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// notify tells the pool's notifier and progress reporter, if any, how the analysis ended.
+func (w *worker) notify(e model.AnalysisEvent) {
+	reportStatus(w.opts.Progress, e.URLID, e.Status, e.Error)
+	if w.opts.Notifier == nil {
+		return
+	}
+	e.FinishedAt = time.Now()
+	w.opts.Notifier.AnalysisFinished(e)
 }
 
 // setErr updates the URL status to "error" if the error is not a record not found.
@@ -0,0 +1,182 @@
+// Package export writes tables row by row as CSV, NDJSON or XLSX, so exports of any size
+// stream straight to the client.
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported formats.
+const (
+	CSV    = "csv"
+	NDJSON = "ndjson"
+	XLSX   = "xlsx"
+)
+
+// Formats lists the supported formats.
+var Formats = []string{CSV, NDJSON, XLSX}
+
+// ValidFormat reports whether f is a supported format.
+func ValidFormat(f string) bool {
+	for _, v := range Formats {
+		if v == f {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentType returns the media type of a format.
+func ContentType(format string) string {
+	switch format {
+	case NDJSON:
+		return "application/x-ndjson"
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Writer writes one table. Header is called once, before any Row; values in a row line up
+// with the header's columns and are strings, integers, bools, time.Time, *time.Time or nil.
+// Close completes the output; an export that failed half-way is not closed.
+type Writer interface {
+	Header(columns []string) error
+	Row(values []any) error
+	Close() error
+}
+
+// NewWriter returns a Writer producing format on w.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case CSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case NDJSON:
+		return &ndjsonWriter{w: bufio.NewWriter(w)}, nil
+	case XLSX:
+		return newXLSXWriter(w), nil
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// text formats a value for the text-only formats.
+func text(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Header(columns []string) error {
+	return c.w.Write(columns)
+}
+
+func (c *csvWriter) Row(values []any) error {
+	rec := make([]string, len(values))
+	for i, v := range values {
+		rec[i] = text(v)
+		if _, isString := v.(string); isString {
+			rec[i] = defuseFormula(rec[i])
+		}
+	}
+	return c.w.Write(rec)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// defuseFormula keeps spreadsheet programs from evaluating crawled text as a formula when a
+// CSV is opened, by prefixing a quote to values that start like one.
+func defuseFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+type ndjsonWriter struct {
+	w       *bufio.Writer
+	columns [][]byte
+	line    bytes.Buffer
+	enc     *json.Encoder
+}
+
+// Header records the keys; the columns' order is kept in every object.
+func (n *ndjsonWriter) Header(columns []string) error {
+	n.columns = make([][]byte, len(columns))
+	for i, c := range columns {
+		key, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		n.columns[i] = key
+	}
+	// Exported text is not embedded in HTML, so it is written without HTML escaping.
+	n.enc = json.NewEncoder(&n.line)
+	n.enc.SetEscapeHTML(false)
+	return nil
+}
+
+func (n *ndjsonWriter) Row(values []any) error {
+	n.line.Reset()
+	n.line.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			n.line.WriteByte(',')
+		}
+		n.line.Write(n.columns[i])
+		n.line.WriteByte(':')
+		switch t := v.(type) {
+		case time.Time:
+			v = t.UTC()
+		case *time.Time:
+			v = nil
+			if t != nil {
+				v = t.UTC()
+			}
+		}
+		if err := n.enc.Encode(v); err != nil {
+			return err
+		}
+		n.line.Truncate(n.line.Len() - 1) // Encode ends every value with a newline.
+	}
+	n.line.WriteString("}\n")
+	_, err := n.w.Write(n.line.Bytes())
+	return err
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.w.Flush()
+}
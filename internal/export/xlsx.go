@@ -0,0 +1,165 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	// xlsxMaxRows is the number of rows a worksheet holds, the header included.
+	xlsxMaxRows = 1 << 20
+	// xlsxMaxText is the number of characters a cell holds.
+	xlsxMaxText = 32767
+)
+
+// ErrTooManyRows is returned when a table outgrows an XLSX worksheet.
+var ErrTooManyRows = errors.New("too many rows for an XLSX worksheet; export as CSV or NDJSON instead")
+
+// The fixed parts of a one-sheet workbook. Cell style 1 is the bold header, 2 a date-time.
+var xlsxParts = []struct{ name, body string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Export" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`},
+	{"xl/styles.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts><fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts><fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/><xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs></styleSheet>`},
+}
+
+const (
+	sheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews><sheetData>`
+	sheetEnd = `</sheetData></worksheet>`
+)
+
+// xlsxWriter streams a single-sheet workbook. The fixed parts go first and the sheet is the
+// last entry of the archive, so rows are compressed and written as they come.
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	row   int
+}
+
+func newXLSXWriter(w io.Writer) *xlsxWriter {
+	return &xlsxWriter{zw: zip.NewWriter(w)}
+}
+
+func (x *xlsxWriter) Header(columns []string) error {
+	for _, p := range xlsxParts {
+		f, err := x.zw.Create(p.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, p.body); err != nil {
+			return err
+		}
+	}
+	f, err := x.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	x.sheet = bufio.NewWriter(f)
+	x.sheet.WriteString(sheetStart)
+
+	values := make([]any, len(columns))
+	for i, c := range columns {
+		values[i] = c
+	}
+	return x.writeRow(values, 1)
+}
+
+func (x *xlsxWriter) Row(values []any) error {
+	if x.row == xlsxMaxRows {
+		return ErrTooManyRows
+	}
+	return x.writeRow(values, 0)
+}
+
+// writeRow writes one row; style applies to string cells and is 0 for none.
+func (x *xlsxWriter) writeRow(values []any, style int) error {
+	x.row++
+	r := strconv.Itoa(x.row)
+	w := x.sheet
+	w.WriteString(`<row r="` + r + `">`)
+	for i, v := range values {
+		ref := column(i) + r
+		switch v := v.(type) {
+		case nil:
+		case string:
+			w.WriteString(`<c r="` + ref + `" t="inlineStr"`)
+			if style != 0 {
+				w.WriteString(` s="` + strconv.Itoa(style) + `"`)
+			}
+			w.WriteString(`><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(w, []byte(truncate(v))); err != nil {
+				return err
+			}
+			w.WriteString(`</t></is></c>`)
+		case bool:
+			b := "0"
+			if v {
+				b = "1"
+			}
+			w.WriteString(`<c r="` + ref + `" t="b"><v>` + b + `</v></c>`)
+		case int, int64, uint:
+			w.WriteString(`<c r="` + ref + `"><v>` + text(v) + `</v></c>`)
+		case time.Time:
+			w.WriteString(`<c r="` + ref + `" s="2"><v>` + serial(v) + `</v></c>`)
+		case *time.Time:
+			if v != nil {
+				w.WriteString(`<c r="` + ref + `" s="2"><v>` + serial(*v) + `</v></c>`)
+			}
+		default:
+			return errors.New("unsupported XLSX cell value")
+		}
+	}
+	_, err := w.WriteString(`</row>`)
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	if x.sheet == nil {
+		return errors.New("xlsx: Close before Header")
+	}
+	x.sheet.WriteString(sheetEnd)
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
+
+// column returns the letters of the 0-based column i: A, B, ..., Z, AA, AB, ...
+func column(i int) string {
+	var b []byte
+	for i++; i > 0; i = (i - 1) / 26 {
+		b = append([]byte{byte('A' + (i-1)%26)}, b...)
+	}
+	return string(b)
+}
+
+// serial converts t to a spreadsheet date: days since 1899-12-30, in UTC.
+func serial(t time.Time) string {
+	const unixEpoch = 25569 // 1970-01-01
+	days := float64(t.UnixMilli())/float64(24*time.Hour/time.Millisecond) + unixEpoch
+	return strconv.FormatFloat(days, 'f', -1, 64)
+}
+
+// truncate cuts s to the characters a cell holds.
+func truncate(s string) string {
+	n := 0
+	for i := range s {
+		if n == xlsxMaxText {
+			return s[:i]
+		}
+		n++
+	}
+	return s
+}
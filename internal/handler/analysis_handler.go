@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/service"
+)
+
+type AnalysisHandler struct {
+	analysisService service.AnalysisService
+}
+
+func NewAnalysisHandler(svc service.AnalysisService) *AnalysisHandler {
+	return &AnalysisHandler{analysisService: svc}
+}
+
+// parseUintQuery reads an optional unsigned query parameter; missing means zero.
+func parseUintQuery(c *gin.Context, name string) (uint, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, true
+	}
+	v, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return 0, false
+	}
+	return uint(v), true
+}
+
+// @Summary List analysis snapshots (paginated, newest first)
+// @Tags    urls
+// @Produce json
+// @Param   id        path  int true  "URL ID"
+// @Param   page      query int false "page" default(1) example(1)
+// @Param   page_size query int false "page_size" default(10) example(10)
+// @Success 200 {object} model.PaginatedResponse[model.AnalysisResultDTO] "Paginated snapshot list"
+// @Failure 400 {object} map[string]string "bad request"
+// @Failure 404 {object} map[string]string "not found"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /urls/{id}/snapshots [get]
+func (h *AnalysisHandler) History(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	result, err := h.analysisService.History(userID, id, paginationFromQuery(c))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Diff two analysis snapshots
+// @Description Compares title, HTML version, heading counts and links between two snapshots. Without from/to the root pages of the two latest runs are compared.
+// @Tags    urls
+// @Produce json
+// @Param   id   path  int true  "URL ID"
+// @Param   from query int false "older snapshot ID"
+// @Param   to   query int false "newer snapshot ID"
+// @Success 200 {object} model.SnapshotDiffDTO
+// @Failure 400 {object} map[string]string "bad request"
+// @Failure 404 {object} map[string]string "not found"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /urls/{id}/diff [get]
+func (h *AnalysisHandler) Diff(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	var q model.SnapshotDiffQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from or to"})
+		return
+	}
+
+	diff, err := h.analysisService.Diff(userID, id, q.From, q.To)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, diff)
+}
+
+// @Summary List certificates expiring soon
+// @Description Returns the current user's URLs whose latest analysis saw a TLS certificate expiring within the given number of days, including already expired ones.
+// @Tags    certificates
+// @Produce json
+// @Param   days query int false "window in days" default(30)
+// @Success 200 {array} model.ExpiringCertificateDTO
+// @Failure 400 {object} map[string]string "bad request"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /certificates/expiring [get]
+func (h *AnalysisHandler) ExpiringCertificates(c *gin.Context) {
+	uidAny, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid days"})
+		return
+	}
+
+	certs, err := h.analysisService.ExpiringCertificates(uidAny.(uint), days)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidDays) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, certs)
+}
+
+func (h *AnalysisHandler) RegisterProtectedRoutes(rg *gin.RouterGroup) {
+	rg.GET("/urls/:id/snapshots", h.History)
+	rg.GET("/urls/:id/diff", h.Diff)
+	rg.GET("/certificates/expiring", h.ExpiringCertificates)
+}
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/importer"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/service"
+)
+
+// maxImportBytes bounds the body of a bulk import.
+const maxImportBytes = 10 << 20
+
+type BulkHandler struct {
+	bulkService service.BulkService
+}
+
+func NewBulkHandler(svc service.BulkService) *BulkHandler {
+	return &BulkHandler{bulkService: svc}
+}
+
+// @Summary Bulk import URLs
+// @Description Creates many URLs at once, reporting each row's outcome. The body is one of: JSON (model.BulkImportRequestDTO) with a list of URLs and/or a sitemap_url to read; a CSV file (text/csv), using the "url" or "original_url" column if there is a header and the first column otherwise; a plain-text list (text/plain), one URL per line with # comments; a sitemap.xml (application/xml); or a multipart form with any of these as "file". For the non-JSON forms the shared options come from query or form fields. Entries are trimmed, given https:// when they have no scheme and normalized; duplicates within the import are reported. At most 1000 URLs per import.
+// @Tags    urls
+// @Accept  json,text/csv,text/plain,application/xml,mpfd
+// @Produce json
+// @Param   input      body     model.BulkImportRequestDTO false "URLs (JSON form)"
+// @Param   file       formData file   false "CSV, text list or sitemap (multipart form)"
+// @Param   crawl_mode query    string false "crawl mode for every URL (non-JSON forms)" Enums(page, site)
+// @Param   max_depth  query    int    false "max depth for every URL (non-JSON forms)"
+// @Param   max_pages  query    int    false "max pages for every URL (non-JSON forms)"
+// @Param   modules    query    []string false "analysis modules for every URL (non-JSON forms)" collectionFormat(multi)
+// @Param   start      query    bool   false "queue every created URL for analysis (non-JSON forms)"
+// @Success 200 {object} model.BulkImportResultDTO
+// @Failure 400 {object} map[string]string "error"
+// @Failure 413 {object} map[string]string "too large"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /urls/bulk [post]
+func (h *BulkHandler) Import(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+
+	var (
+		entries    []importer.Entry
+		sitemapURL string
+		opts       model.BulkImportOptions
+		err        error
+	)
+	mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+	switch mediaType {
+	case "application/json", "":
+		var req model.BulkImportRequestDTO
+		if err = c.ShouldBindJSON(&req); err != nil {
+			break
+		}
+		entries, sitemapURL, opts = importer.FromStrings(req.URLs), req.SitemapURL, req.BulkImportOptions
+	case "multipart/form-data":
+		if err = c.ShouldBind(&opts); err != nil {
+			break
+		}
+		var fh io.ReadCloser
+		file, ferr := c.FormFile("file")
+		if ferr != nil {
+			err = errors.New(`the "file" field is required`)
+			break
+		}
+		if fh, err = file.Open(); err != nil {
+			break
+		}
+		defer fh.Close()
+		entries, err = parseImport(fileKind(file.Header.Get("Content-Type"), file.Filename), fh)
+	default:
+		if err = c.ShouldBindQuery(&opts); err != nil {
+			break
+		}
+		entries, err = parseImport(mediaType, c.Request.Body)
+	}
+	if err != nil {
+		importError(c, err)
+		return
+	}
+
+	res, err := h.bulkService.Import(c.Request.Context(), uid, entries, sitemapURL, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// parseImport reads the entries of an uploaded list of the given media type.
+func parseImport(mediaType string, r io.Reader) ([]importer.Entry, error) {
+	switch mediaType {
+	case "text/csv":
+		return importer.ParseCSV(r, model.MaxBulkItems)
+	case "text/plain":
+		return importer.ParseText(r, model.MaxBulkItems)
+	case "application/xml", "text/xml", "application/gzip", "application/x-gzip":
+		pages, sitemaps, err := importer.ParseSitemap(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(sitemaps) > 0 {
+			return nil, errors.New("sitemap indexes cannot be uploaded; pass the index as sitemap_url instead")
+		}
+		if len(pages) > model.MaxBulkItems {
+			return nil, importer.ErrTooManyEntries
+		}
+		return importer.FromStrings(pages), nil
+	}
+	return nil, errors.New("unsupported content type " + mediaType)
+}
+
+// fileKind guesses the media type of an uploaded file, trusting a specific part header over
+// the file extension.
+func fileKind(contentType, filename string) string {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil && mt != "application/octet-stream" {
+		return mt
+	}
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return "text/csv"
+	case ".xml":
+		return "application/xml"
+	case ".gz":
+		return "application/gzip"
+	}
+	return "text/plain"
+}
+
+// importError maps an error reading the import body to a response.
+func importError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "import body too large"})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// bulkAction binds a bulk action request and runs it.
+func (h *BulkHandler) bulkAction(c *gin.Context, okStatus int, run func(uint, *model.BulkActionRequestDTO) (*model.BulkActionResultDTO, error)) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	var req model.BulkActionRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	res, err := run(uid, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(okStatus, res)
+}
+
+// @Summary Bulk start crawls
+// @Description Queues analysis for the URLs selected by ids or by filter (status, crawl_mode, q), at most 1000.
+// @Tags    urls
+// @Accept  json
+// @Produce json
+// @Param   input body model.BulkActionRequestDTO true "selection"
+// @Success 202 {object} model.BulkActionResultDTO
+// @Failure 400 {object} map[string]string "error"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /urls/bulk/start [post]
+func (h *BulkHandler) Start(c *gin.Context) {
+	h.bulkAction(c, http.StatusAccepted, h.bulkService.Start)
+}
+
+// @Summary Bulk stop crawls
+// @Description Stops the URLs selected by ids or by filter. Partial results are discarded unless keep_partial is true.
+// @Tags    urls
+// @Accept  json
+// @Produce json
+// @Param   input body model.BulkActionRequestDTO true "selection"
+// @Success 202 {object} model.BulkActionResultDTO
+// @Failure 400 {object} map[string]string "error"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /urls/bulk/stop [post]
+func (h *BulkHandler) Stop(c *gin.Context) {
+	h.bulkAction(c, http.StatusAccepted, h.bulkService.Stop)
+}
+
+// @Summary Bulk delete URLs
+// @Description Deletes the URLs selected by ids or by filter.
+// @Tags    urls
+// @Accept  json
+// @Produce json
+// @Param   input body model.BulkActionRequestDTO true "selection"
+// @Success 200 {object} model.BulkActionResultDTO
+// @Failure 400 {object} map[string]string "error"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /urls/bulk/delete [post]
+func (h *BulkHandler) Delete(c *gin.Context) {
+	h.bulkAction(c, http.StatusOK, h.bulkService.Delete)
+}
+
+func (h *BulkHandler) RegisterProtectedRoutes(rg *gin.RouterGroup) {
+	rg.POST("/urls/bulk", h.Import)
+	rg.POST("/urls/bulk/start", h.Start)
+	rg.POST("/urls/bulk/stop", h.Stop)
+	rg.POST("/urls/bulk/delete", h.Delete)
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/export"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/service"
+)
+
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+func NewExportHandler(svc service.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: svc}
+}
+
+// exportFormat reads the format query parameter, CSV when missing.
+func exportFormat(c *gin.Context) (string, bool) {
+	format := c.DefaultQuery("format", export.CSV)
+	if !export.ValidFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format"})
+		return "", false
+	}
+	return format, true
+}
+
+// download streams an export as an attachment named name. Errors raised before anything
+// was written get a JSON response; later ones can only cut the download short.
+func download(c *gin.Context, format, name string, run func(w export.Writer) error) {
+	w, err := export.NewWriter(format, c.Writer)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+
+	err = run(w)
+	if err == nil {
+		return
+	}
+	if c.Writer.Written() {
+		_ = c.Error(err)
+		return
+	}
+	c.Header("Content-Type", "")
+	c.Header("Content-Disposition", "")
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// @Summary Export URLs
+// @Description Downloads every URL of the user with the metrics of its latest snapshot (status code, health, title, link counts, timing, certificate expiry). The export is streamed.
+// @Tags    export
+// @Produce text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param   format query string false "file format" Enums(csv, ndjson, xlsx) default(csv)
+// @Success 200 {file} file "export"
+// @Failure 400 {object} map[string]string "bad request"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /urls/export [get]
+func (h *ExportHandler) URLs(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+	download(c, format, "urls", func(w export.Writer) error {
+		return h.exportService.URLs(uid, w)
+	})
+}
+
+// @Summary Export snapshots
+// @Description Downloads every analysis snapshot of a URL, oldest first, one row per page analyzed. The export is streamed.
+// @Tags    export
+// @Produce text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param   id     path  int    true  "URL ID"
+// @Param   format query string false "file format" Enums(csv, ndjson, xlsx) default(csv)
+// @Success 200 {file} file "export"
+// @Failure 400 {object} map[string]string "bad request"
+// @Failure 404 {object} map[string]string "not found"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /urls/{id}/export/snapshots [get]
+func (h *ExportHandler) Snapshots(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+	download(c, format, fmt.Sprintf("url-%d-snapshots", id), func(w export.Writer) error {
+		return h.exportService.Snapshots(uid, id, w)
+	})
+}
+
+// @Summary Export links
+// @Description Downloads the links recorded by one analysis run of a URL: the latest run, or the run containing snapshot. The export is streamed, so it suits link tables too large for the results endpoint.
+// @Tags    export
+// @Produce text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param   id         path  int    true  "URL ID"
+// @Param   format     query string false "file format" Enums(csv, ndjson, xlsx) default(csv)
+// @Param   snapshot   query int    false "snapshot ID"
+// @Param   link_error query string false "only links that failed with this error category, or any" Enums(any, dns, timeout, tls, connection_refused, connection_reset, unreachable, blocked, other)
+// @Success 200 {file} file "export"
+// @Failure 400 {object} map[string]string "bad request"
+// @Failure 404 {object} map[string]string "not found"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /urls/{id}/export/links [get]
+func (h *ExportHandler) Links(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+	snapshotID, ok := parseUintQuery(c, "snapshot")
+	if !ok {
+		return
+	}
+	linkError := c.Query("link_error")
+	if linkError != "" && !model.ValidLinkErrorFilter(linkError) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid link_error"})
+		return
+	}
+	download(c, format, fmt.Sprintf("url-%d-links", id), func(w export.Writer) error {
+		return h.exportService.Links(uid, id, snapshotID, linkError, w)
+	})
+}
+
+func (h *ExportHandler) RegisterProtectedRoutes(rg *gin.RouterGroup) {
+	rg.GET("/urls/export", h.URLs)
+	rg.GET("/urls/:id/export/snapshots", h.Snapshots)
+	rg.GET("/urls/:id/export/links", h.Links)
+}
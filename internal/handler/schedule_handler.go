@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/service"
+)
+
+type ScheduleHandler struct {
+	scheduleService service.ScheduleService
+}
+
+func NewScheduleHandler(svc service.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{scheduleService: svc}
+}
+
+// scheduleError maps a service error to a response; missing rows become 404.
+func scheduleError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// @Summary Set recurring schedule
+// @Description Creates or replaces the URL's schedule. Give either a standard 5-field cron expression or a fixed interval (>= 60s).
+// @Tags    schedules
+// @Accept  json
+// @Produce json
+// @Param   id path int true "URL ID"
+// @Param   input body model.ScheduleInputDTO true "schedule"
+// @Success 200 {object} model.ScheduleDTO
+// @Failure 400 {object} map[string]string "error"
+// @Failure 404 {object} map[string]string "not found"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /urls/{id}/schedule [put]
+func (h *ScheduleHandler) Set(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	var in model.ScheduleInputDTO
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	dto, err := h.scheduleService.Set(userID, id, &in)
+	if err != nil {
+		scheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto)
+}
+
+// @Summary Get schedule
+// @Tags    schedules
+// @Produce json
+// @Param   id path int true "URL ID"
+// @Success 200 {object} model.ScheduleDTO
+// @Failure 404 {object} map[string]string "not found"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /urls/{id}/schedule [get]
+func (h *ScheduleHandler) Get(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	dto, err := h.scheduleService.Get(userID, id)
+	if err != nil {
+		scheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto)
+}
+
+// @Summary Pause schedule
+// @Tags    schedules
+// @Produce json
+// @Param   id path int true "URL ID"
+// @Success 200 {object} model.ScheduleDTO
+// @Failure 404 {object} map[string]string "not found"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /urls/{id}/schedule/pause [patch]
+func (h *ScheduleHandler) Pause(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	dto, err := h.scheduleService.Pause(userID, id)
+	if err != nil {
+		scheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto)
+}
+
+// @Summary Resume schedule
+// @Tags    schedules
+// @Produce json
+// @Param   id path int true "URL ID"
+// @Success 200 {object} model.ScheduleDTO
+// @Failure 404 {object} map[string]string "not found"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /urls/{id}/schedule/resume [patch]
+func (h *ScheduleHandler) Resume(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	dto, err := h.scheduleService.Resume(userID, id)
+	if err != nil {
+		scheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto)
+}
+
+// @Summary Delete schedule
+// @Tags    schedules
+// @Produce json
+// @Param   id path int true "URL ID"
+// @Success 200 {object} map[string]string "deleted"
+// @Failure 404 {object} map[string]string "not found"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /urls/{id}/schedule [delete]
+func (h *ScheduleHandler) Delete(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.scheduleService.Delete(userID, id); err != nil {
+		scheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// @Summary List schedules with next-run times (paginated)
+// @Tags    schedules
+// @Produce json
+// @Param   page      query int false "page" default(1) example(1)
+// @Param   page_size query int false "page_size" default(10) example(10)
+// @Success 200 {object} model.PaginatedResponse[model.ScheduleDTO] "Paginated schedule list"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /schedules [get]
+func (h *ScheduleHandler) List(c *gin.Context) {
+	uidAny, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	result, err := h.scheduleService.List(uidAny.(uint), paginationFromQuery(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *ScheduleHandler) RegisterProtectedRoutes(rg *gin.RouterGroup) {
+	rg.GET("/schedules", h.List)
+	rg.PUT("/urls/:id/schedule", h.Set)
+	rg.GET("/urls/:id/schedule", h.Get)
+	rg.DELETE("/urls/:id/schedule", h.Delete)
+	rg.PATCH("/urls/:id/schedule/pause", h.Pause)
+	rg.PATCH("/urls/:id/schedule/resume", h.Resume)
+}
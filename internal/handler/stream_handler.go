@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/service"
+	"github.com/fuzumoe/urlinsight-backend/internal/stream"
+)
+
+type StreamHandler struct {
+	urlService service.URLService
+	broker     *stream.Broker
+	heartbeat  time.Duration
+}
+
+// NewStreamHandler creates a handler streaming URL progress from broker. Every heartbeat the
+// stream is kept alive and the URL's status re-read, which catches analyses run by another
+// instance.
+func NewStreamHandler(svc service.URLService, broker *stream.Broker, heartbeat time.Duration) *StreamHandler {
+	if heartbeat <= 0 {
+		heartbeat = 15 * time.Second
+	}
+	return &StreamHandler{urlService: svc, broker: broker, heartbeat: heartbeat}
+}
+
+// @Summary Stream URL status and progress
+// @Description Server-Sent Events stream for one URL. The first event carries the current status. "status" events follow every transition (queued, running, done, error, stopped); "progress" events report links checked so far on the page being analyzed and, in site mode, pages crawled. Data is a model.ProgressEvent as JSON. The stream stays open until the client disconnects.
+// @Tags    urls
+// @Produce text/event-stream
+// @Param   id path int true "URL ID"
+// @Success 200 {object} model.ProgressEvent "event stream"
+// @Failure 404 {object} map[string]string "not found"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /urls/{id}/events [get]
+func (h *StreamHandler) Events(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	dto, err := h.urlService.Get(id)
+	if err != nil || dto.UserID != uid {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+
+	// Subscribe before sending the current status so no transition falls in between.
+	events, unsubscribe := h.broker.Subscribe(id)
+	defer unsubscribe()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	status := dto.Status
+	h.send(c, model.ProgressEvent{URLID: id, Type: model.ProgressStatus, Status: status, At: time.Now()})
+
+	t := time.NewTicker(h.heartbeat)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if e.Type == model.ProgressStatus {
+				status = e.Status
+			}
+			h.send(c, e)
+		case <-t.C:
+			if st, err := h.urlService.Status(id); err == nil && st != status {
+				status = st
+				h.send(c, model.ProgressEvent{URLID: id, Type: model.ProgressStatus, Status: status, At: time.Now()})
+				continue
+			}
+			_, _ = c.Writer.WriteString(": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// send writes e as an SSE event named after its type.
+func (h *StreamHandler) send(c *gin.Context, e model.ProgressEvent) {
+	c.SSEvent(e.Type, e)
+	c.Writer.Flush()
+}
+
+func (h *StreamHandler) RegisterProtectedRoutes(rg *gin.RouterGroup) {
+	rg.GET("/urls/:id/events", h.Events)
+}
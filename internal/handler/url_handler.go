@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 
 	"github.com/fuzumoe/urlinsight-backend/internal/model"
 	"github.com/fuzumoe/urlinsight-backend/internal/repository"
@@ -58,8 +60,15 @@ func (h *URLHandler) Create(c *gin.Context) {
 
 	// Create the full input DTO with both UserID and OriginalURL
 	inputDTO := &model.CreateURLInputDTO{
-		UserID:      uidAny.(uint),
-		OriginalURL: requestDTO.OriginalURL,
+		UserID:         uidAny.(uint),
+		OriginalURL:    requestDTO.OriginalURL,
+		CrawlMode:      requestDTO.CrawlMode,
+		MaxDepth:       requestDTO.MaxDepth,
+		MaxPages:       requestDTO.MaxPages,
+		RequestHeaders: requestDTO.RequestHeaders,
+		RequestCookies: requestDTO.RequestCookies,
+		Modules:        requestDTO.Modules,
+		Assertions:     requestDTO.Assertions,
 	}
 
 	id, err := h.urlService.Create(inputDTO)
@@ -185,10 +194,13 @@ func (h *URLHandler) Start(c *gin.Context) {
 }
 
 // @Summary Stop crawl
+// @Description Cancels a running analysis immediately. Partial results are discarded unless keep_partial is true.
 // @Tags    urls
 // @Produce json
 // @Param   id path int true "URL ID"
+// @Param   keep_partial query bool false "Persist results collected before the stop"
 // @Success 202 {object} map[string]string "stopped"
+// @Failure 400 {object} map[string]string "bad request"
 // @Security JWTAuth
 // @Security BasicAuth
 // @Router  /urls/{id}/stop [patch]
@@ -197,17 +209,25 @@ func (h *URLHandler) Stop(c *gin.Context) {
 	if !ok {
 		return
 	}
-	if err := h.urlService.Stop(id); err != nil {
+	keepPartial, err := strconv.ParseBool(c.DefaultQuery("keep_partial", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid keep_partial"})
+		return
+	}
+	if err := h.urlService.Stop(id, keepPartial); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusAccepted, gin.H{"status": model.StatusStopped})
 }
 
-// @Summary Latest analysis snapshot + links
+// @Summary Latest analysis snapshot + links (per-page results and site summary in site mode)
+// @Description Returns one analysis run and the links it recorded. Defaults to the latest run; pass snapshot to get the run containing that snapshot.
 // @Tags    urls
 // @Produce json
-// @Param   id path int true "URL ID"
+// @Param   id         path  int    true  "URL ID"
+// @Param   snapshot   query int    false "snapshot ID"
+// @Param   link_error query string false "only links that failed with this error category, or any" Enums(any, dns, timeout, tls, connection_refused, connection_reset, unreachable, blocked, other)
 // @Success 200 {object} model.URLResultsDTO
 // @Failure 404 {object} map[string]string "not found"
 // @Failure 400 {object} map[string]string "bad request"
@@ -220,12 +240,26 @@ func (h *URLHandler) Results(c *gin.Context) {
 		return
 	}
 
+	snapshotID, ok := parseUintQuery(c, "snapshot")
+	if !ok {
+		return
+	}
+	linkError := c.Query("link_error")
+	if linkError != "" && !model.ValidLinkErrorFilter(linkError) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid link_error"})
+		return
+	}
+
 	// Use the existing ResultsWithDetails method
-	url, analysisResults, links, err := h.urlService.ResultsWithDetails(id)
+	url, analysisResults, links, err := h.urlService.ResultsWithDetails(id, snapshotID)
 	if err != nil {
 		// Check if it's a "not found" error
-		if err.Error() == "record not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "URL not found"})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			msg := "URL not found"
+			if snapshotID != 0 {
+				msg = "snapshot not found"
+			}
+			c.JSON(http.StatusNotFound, gin.H{"error": msg})
 			return
 		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -236,7 +270,13 @@ func (h *URLHandler) Results(c *gin.Context) {
 	dto := &model.URLResultsDTO{
 		URL:             url.ToDTO(),
 		AnalysisResults: analysisResults,
-		Links:           links,
+		Links:           model.FilterLinksByError(links, linkError),
+	}
+	if url.CrawlMode == model.CrawlModeSite {
+		// A site that has never finished a crawl simply has no summary yet.
+		if summary, err := h.urlService.SiteSummary(id, snapshotID); err == nil {
+			dto.SiteSummary = summary
+		}
 	}
 
 	c.JSON(http.StatusOK, dto)
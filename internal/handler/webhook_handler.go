@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/service"
+)
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+func NewWebhookHandler(svc service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: svc}
+}
+
+// webhookError maps a service error to a response; missing rows become 404.
+func webhookError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// currentUser returns the authenticated user's ID, answering 401 when there is none.
+func currentUser(c *gin.Context) (uint, bool) {
+	uidAny, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return 0, false
+	}
+	return uidAny.(uint), true
+}
+
+// @Summary Register webhook
+// @Description Registers an endpoint notified of analysis events (analysis.completed, analysis.failed, analysis.stopped, broken_links.detected). Each delivery is signed in the X-URLInsight-Signature header as "sha256=" + hex HMAC-SHA256 of "<X-URLInsight-Timestamp>.<body>"; the secret is returned only in this response.
+// @Tags    webhooks
+// @Accept  json
+// @Produce json
+// @Param   input body model.WebhookInputDTO true "webhook"
+// @Success 201 {object} model.WebhookDTO
+// @Failure 400 {object} map[string]string "error"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /webhooks [post]
+func (h *WebhookHandler) Create(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	var in model.WebhookInputDTO
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	dto, err := h.webhookService.Create(uid, &in)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, dto)
+}
+
+// @Summary List webhooks (paginated)
+// @Tags    webhooks
+// @Produce json
+// @Param   page      query int false "page" default(1) example(1)
+// @Param   page_size query int false "page_size" default(10) example(10)
+// @Success 200 {object} model.PaginatedResponse[model.WebhookDTO] "Paginated webhook list"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /webhooks [get]
+func (h *WebhookHandler) List(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	result, err := h.webhookService.List(uid, paginationFromQuery(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Get webhook
+// @Tags    webhooks
+// @Produce json
+// @Param   id path int true "Webhook ID"
+// @Success 200 {object} model.WebhookDTO
+// @Failure 404 {object} map[string]string "not found"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /webhooks/{id} [get]
+func (h *WebhookHandler) Get(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	dto, err := h.webhookService.Get(uid, id)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto)
+}
+
+// @Summary Update webhook
+// @Description Replaces the webhook's URL and events; active is left unchanged when omitted. The secret is kept.
+// @Tags    webhooks
+// @Accept  json
+// @Produce json
+// @Param   id path int true "Webhook ID"
+// @Param   input body model.WebhookInputDTO true "webhook"
+// @Success 200 {object} model.WebhookDTO
+// @Failure 400 {object} map[string]string "error"
+// @Failure 404 {object} map[string]string "not found"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /webhooks/{id} [put]
+func (h *WebhookHandler) Update(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	var in model.WebhookInputDTO
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	dto, err := h.webhookService.Update(uid, id, &in)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto)
+}
+
+// @Summary Delete webhook
+// @Tags    webhooks
+// @Produce json
+// @Param   id path int true "Webhook ID"
+// @Success 200 {object} map[string]string "deleted"
+// @Failure 404 {object} map[string]string "not found"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /webhooks/{id} [delete]
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.webhookService.Delete(uid, id); err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// @Summary List webhook deliveries (paginated, newest first)
+// @Tags    webhooks
+// @Produce json
+// @Param   id        path  int true  "Webhook ID"
+// @Param   page      query int false "page" default(1) example(1)
+// @Param   page_size query int false "page_size" default(10) example(10)
+// @Success 200 {object} model.PaginatedResponse[model.WebhookDelivery] "Paginated delivery log"
+// @Failure 404 {object} map[string]string "not found"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) Deliveries(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	result, err := h.webhookService.Deliveries(uid, id, paginationFromQuery(c))
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Redeliver webhook delivery
+// @Description Queues the delivery's payload again as a new delivery, sent right away.
+// @Tags    webhooks
+// @Produce json
+// @Param   id          path int true "Webhook ID"
+// @Param   delivery_id path int true "Delivery ID"
+// @Success 202 {object} model.WebhookDelivery
+// @Failure 404 {object} map[string]string "not found"
+// @Security JWTAuth
+// @Security BasicAuth
+// @Router  /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := parseUintParam(c, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseUintParam(c, "delivery_id")
+	if !ok {
+		return
+	}
+	d, err := h.webhookService.Redeliver(uid, id, deliveryID)
+	if err != nil {
+		webhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, d)
+}
+
+func (h *WebhookHandler) RegisterProtectedRoutes(rg *gin.RouterGroup) {
+	rg.POST("/webhooks", h.Create)
+	rg.GET("/webhooks", h.List)
+	rg.GET("/webhooks/:id", h.Get)
+	rg.PUT("/webhooks/:id", h.Update)
+	rg.DELETE("/webhooks/:id", h.Delete)
+	rg.GET("/webhooks/:id/deliveries", h.Deliveries)
+	rg.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", h.Redeliver)
+}
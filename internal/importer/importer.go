@@ -0,0 +1,142 @@
+// Package importer reads lists of URLs from CSV files, plain-text lists and sitemaps, and
+// normalizes the entries.
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ErrTooManyEntries is returned when an input holds more entries than allowed.
+var ErrTooManyEntries = errors.New("too many entries")
+
+// Entry is one raw value read from an input, with its 1-based row.
+type Entry struct {
+	Row   int
+	Value string
+}
+
+// FromStrings numbers a list of values as entries.
+func FromStrings(values []string) []Entry {
+	out := make([]Entry, len(values))
+	for i, v := range values {
+		out[i] = Entry{Row: i + 1, Value: v}
+	}
+	return out
+}
+
+// ParseText reads one URL per line. Blank lines and lines starting with # are skipped; rows
+// still count them, so they match the line numbers of the input.
+func ParseText(r io.Reader, max int) ([]Entry, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	var out []Entry
+	for row := 1; sc.Scan(); row++ {
+		v := strings.TrimSpace(sc.Text())
+		if row == 1 {
+			v = strings.TrimPrefix(v, "\ufeff")
+		}
+		if v == "" || strings.HasPrefix(v, "#") {
+			continue
+		}
+		if len(out) == max {
+			return nil, fmt.Errorf("%w: at most %d URLs", ErrTooManyEntries, max)
+		}
+		out = append(out, Entry{Row: row, Value: v})
+	}
+	return out, sc.Err()
+}
+
+// ParseCSV reads URLs from a CSV file. A header row naming a "url" or "original_url" column
+// selects that column; otherwise every row is data and the first column is used.
+func ParseCSV(r io.Reader, max int) ([]Entry, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	col := 0
+	var out []Entry
+	for row := 1; ; row++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		if row == 1 {
+			rec[0] = strings.TrimPrefix(rec[0], "\ufeff")
+			if i := headerColumn(rec); i >= 0 {
+				col = i
+				continue
+			}
+		}
+		if col >= len(rec) {
+			continue
+		}
+		v := strings.TrimSpace(rec[col])
+		if v == "" {
+			continue
+		}
+		if len(out) == max {
+			return nil, fmt.Errorf("%w: at most %d URLs", ErrTooManyEntries, max)
+		}
+		out = append(out, Entry{Row: row, Value: v})
+	}
+}
+
+// headerColumn returns the index of the URL column if rec is a header row, else -1.
+func headerColumn(rec []string) int {
+	for i, name := range rec {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "url", "original_url":
+			return i
+		}
+	}
+	return -1
+}
+
+// Normalize turns a user-supplied URL into the form stored: surrounding space trimmed,
+// https:// assumed when no scheme is given, scheme and host lower-cased, default ports and
+// the fragment dropped. Only http and https URLs with a host are accepted.
+func Normalize(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", errors.New("empty URL")
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", errors.New("invalid URL")
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if host == "" {
+		return "", errors.New("missing host")
+	}
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	switch {
+	case port != "":
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		u.Host = "[" + host + "]"
+	default:
+		u.Host = host
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String(), nil
+}
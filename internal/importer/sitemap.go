@@ -0,0 +1,140 @@
+package importer
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// maxSitemapBytes bounds one sitemap, decompressed; the protocol allows 50MB but an
+	// import takes far fewer URLs than that holds.
+	maxSitemapBytes = 10 << 20
+	// maxChildSitemaps bounds the sitemaps read from one sitemap index.
+	maxChildSitemaps = 50
+)
+
+// ErrNotSitemap is returned for XML that is neither a urlset nor a sitemapindex.
+var ErrNotSitemap = errors.New("not a sitemap")
+
+type sitemapDoc struct {
+	XMLName  xml.Name
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+// ParseSitemap reads a sitemap, plain or gzipped. It returns the page URLs of a urlset, or
+// the child sitemap URLs of a sitemap index.
+func ParseSitemap(r io.Reader) (pages, sitemaps []string, err error) {
+	br := bufio.NewReader(r)
+	var src io.Reader = br
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid sitemap: %w", err)
+		}
+		defer zr.Close()
+		src = zr
+	}
+	lr := &io.LimitedReader{R: src, N: maxSitemapBytes + 1}
+
+	var doc sitemapDoc
+	if err := xml.NewDecoder(lr).Decode(&doc); err != nil {
+		if lr.N <= 0 {
+			return nil, nil, fmt.Errorf("sitemap larger than %d bytes", maxSitemapBytes)
+		}
+		return nil, nil, fmt.Errorf("invalid sitemap: %w", err)
+	}
+	switch doc.XMLName.Local {
+	case "urlset":
+		return locs(doc.URLs), nil, nil
+	case "sitemapindex":
+		return nil, locs(doc.Sitemaps), nil
+	}
+	return nil, nil, ErrNotSitemap
+}
+
+func locs(in []sitemapLoc) []string {
+	out := make([]string, 0, len(in))
+	for _, l := range in {
+		if v := strings.TrimSpace(l.Loc); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// SitemapFetcher downloads sitemaps and collects the page URLs they list.
+type SitemapFetcher struct {
+	client    *http.Client
+	userAgent string
+}
+
+// NewSitemapFetcher creates a fetcher sending requests through transport (nil selects
+// http.DefaultTransport) as userAgent.
+func NewSitemapFetcher(transport http.RoundTripper, userAgent string) *SitemapFetcher {
+	return &SitemapFetcher{
+		client:    &http.Client{Timeout: 30 * time.Second, Transport: transport},
+		userAgent: userAgent,
+	}
+}
+
+// Fetch returns the page URLs listed by the sitemap at sitemapURL. A sitemap index is
+// followed one level down. More than max pages is an error.
+func (f *SitemapFetcher) Fetch(ctx context.Context, sitemapURL string, max int) ([]string, error) {
+	pages, children, err := f.get(ctx, sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+	if len(children) > maxChildSitemaps {
+		return nil, fmt.Errorf("sitemap index lists more than %d sitemaps", maxChildSitemaps)
+	}
+	for _, child := range children {
+		more, _, err := f.get(ctx, child)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, more...)
+		if len(pages) > max {
+			break
+		}
+	}
+	if len(pages) > max {
+		return nil, fmt.Errorf("%w: the sitemap lists more than %d URLs", ErrTooManyEntries, max)
+	}
+	return pages, nil
+}
+
+func (f *SitemapFetcher) get(ctx context.Context, u string) (pages, sitemaps []string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetch sitemap %s: %w", u, err)
+	}
+	if f.userAgent != "" {
+		req.Header.Set("User-Agent", f.userAgent)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetch sitemap %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("fetch sitemap %s: unexpected status %d", u, resp.StatusCode)
+	}
+	pages, sitemaps, err = ParseSitemap(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sitemap %s: %w", u, err)
+	}
+	return pages, sitemaps, nil
+}
@@ -0,0 +1,33 @@
+package model
+
+// Severities of accessibility findings.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// AccessibilityReport is the outcome of the accessibility pass over one page.
+type AccessibilityReport struct {
+	Errors   int                    `json:"errors"`
+	Warnings int                    `json:"warnings"`
+	Findings []AccessibilityFinding `json:"findings"`
+}
+
+// AccessibilityFinding is one failed check; Path is a CSS selector for the offending element.
+type AccessibilityFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+}
+
+// Add records a finding and updates the per-severity totals.
+func (r *AccessibilityReport) Add(f AccessibilityFinding) {
+	switch f.Severity {
+	case SeverityError:
+		r.Errors++
+	case SeverityWarning:
+		r.Warnings++
+	}
+	r.Findings = append(r.Findings, f)
+}
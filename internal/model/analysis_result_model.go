@@ -8,40 +8,83 @@ import (
 
 // AnalysisResult holds parsed metadata for a given URL.
 type AnalysisResult struct {
-	ID                uint           `gorm:"primaryKey;autoIncrement" json:"id"`
-	URLID             uint           `gorm:"not null;index" json:"url_id"`
-	HTMLVersion       string         `gorm:"size:50;not null" json:"html_version"`
-	Title             string         `gorm:"type:text" json:"title"`
-	H1Count           int            `json:"h1_count"`
-	H2Count           int            `json:"h2_count"`
-	H3Count           int            `json:"h3_count"`
-	H4Count           int            `json:"h4_count"`
-	H5Count           int            `json:"h5_count"`
-	H6Count           int            `json:"h6_count"`
-	HasLoginForm      bool           `json:"has_login_form"`
-	InternalLinkCount int            `json:"internal_link_count"`
-	ExternalLinkCount int            `json:"external_link_count"`
-	BrokenLinkCount   int            `json:"broken_link_count"`
-	CreatedAt         time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt         time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                uint                 `gorm:"primaryKey;autoIncrement" json:"id"`
+	URLID             uint                 `gorm:"not null;index" json:"url_id"`
+	PageURL           string               `gorm:"type:text" json:"page_url"`
+	Depth             int                  `gorm:"not null;default:0" json:"depth"`
+	StatusCode        int                  `json:"status_code"`
+	ContentType       string               `gorm:"size:191" json:"content_type"`
+	Charset           string               `gorm:"size:64" json:"charset"`
+	BodyTruncated     bool                 `json:"body_truncated"`
+	LinksIncomplete   bool                 `gorm:"not null;default:false" json:"links_incomplete"`
+	Modules           []string             `gorm:"type:json;serializer:json" json:"modules"`
+	Health            string               `gorm:"size:16;index" json:"health,omitempty"`
+	Assertions        []AssertionResult    `gorm:"type:json;serializer:json" json:"assertions,omitempty"`
+	HTMLVersion       string               `gorm:"size:50;not null" json:"html_version"`
+	Title             string               `gorm:"type:text" json:"title"`
+	H1Count           int                  `json:"h1_count"`
+	H2Count           int                  `json:"h2_count"`
+	H3Count           int                  `json:"h3_count"`
+	H4Count           int                  `json:"h4_count"`
+	H5Count           int                  `json:"h5_count"`
+	H6Count           int                  `json:"h6_count"`
+	HasLoginForm      bool                 `json:"has_login_form"`
+	InternalLinkCount int                  `json:"internal_link_count"`
+	ExternalLinkCount int                  `json:"external_link_count"`
+	BrokenLinkCount   int                  `json:"broken_link_count"`
+	ErrorLinkCount    int                  `json:"error_link_count"`
+	SEO               *SEOMetadata         `gorm:"type:json;serializer:json" json:"seo,omitempty"`
+	StructuredData    *StructuredData      `gorm:"type:json;serializer:json" json:"structured_data,omitempty"`
+	Accessibility     *AccessibilityReport `gorm:"type:json;serializer:json" json:"accessibility,omitempty"`
+	Security          *SecurityReport      `gorm:"type:json;serializer:json" json:"security,omitempty"`
+	TLS               *TLSReport           `gorm:"column:tls;type:json;serializer:json" json:"tls,omitempty"`
+	CertExpiresAt     *time.Time           `gorm:"index" json:"cert_expires_at,omitempty"`
+	Redirects         *RedirectChain       `gorm:"type:json;serializer:json" json:"redirects,omitempty"`
+	Robots            *RobotsReport        `gorm:"type:json;serializer:json" json:"robots,omitempty"`
+	Timing            PageTiming           `gorm:"embedded;embeddedPrefix:timing_" json:"timing"`
+	CreatedAt         time.Time            `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time            `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt         gorm.DeletedAt       `gorm:"index" json:"-"`
 }
 
 // AnalysisResultDTO is used for sending analysis results in responses.
 type AnalysisResultDTO struct {
-	ID           uint      `json:"id"`
-	URLID        uint      `json:"url_id"`
-	HTMLVersion  string    `json:"html_version"`
-	Title        string    `json:"title"`
-	H1Count      int       `json:"h1_count"`
-	H2Count      int       `json:"h2_count"`
-	H3Count      int       `json:"h3_count"`
-	H4Count      int       `json:"h4_count"`
-	H5Count      int       `json:"h5_count"`
-	H6Count      int       `json:"h6_count"`
-	HasLoginForm bool      `json:"has_login_form"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                uint                 `json:"id"`
+	URLID             uint                 `json:"url_id"`
+	PageURL           string               `json:"page_url"`
+	Depth             int                  `json:"depth"`
+	StatusCode        int                  `json:"status_code"`
+	ContentType       string               `json:"content_type"`
+	Charset           string               `json:"charset"`
+	BodyTruncated     bool                 `json:"body_truncated"`
+	LinksIncomplete   bool                 `json:"links_incomplete"`
+	Modules           []string             `json:"modules"`
+	Health            string               `json:"health,omitempty"`
+	Assertions        []AssertionResult    `json:"assertions,omitempty"`
+	HTMLVersion       string               `json:"html_version"`
+	Title             string               `json:"title"`
+	H1Count           int                  `json:"h1_count"`
+	H2Count           int                  `json:"h2_count"`
+	H3Count           int                  `json:"h3_count"`
+	H4Count           int                  `json:"h4_count"`
+	H5Count           int                  `json:"h5_count"`
+	H6Count           int                  `json:"h6_count"`
+	HasLoginForm      bool                 `json:"has_login_form"`
+	InternalLinkCount int                  `json:"internal_link_count"`
+	ExternalLinkCount int                  `json:"external_link_count"`
+	BrokenLinkCount   int                  `json:"broken_link_count"`
+	ErrorLinkCount    int                  `json:"error_link_count"`
+	SEO               *SEOMetadata         `json:"seo,omitempty"`
+	StructuredData    *StructuredData      `json:"structured_data,omitempty"`
+	Accessibility     *AccessibilityReport `json:"accessibility,omitempty"`
+	Security          *SecurityReport      `json:"security,omitempty"`
+	TLS               *TLSReport           `json:"tls,omitempty"`
+	CertExpiresAt     *time.Time           `json:"cert_expires_at,omitempty"`
+	Redirects         *RedirectChain       `json:"redirects,omitempty"`
+	Robots            *RobotsReport        `json:"robots,omitempty"`
+	Timing            PageTiming           `json:"timing"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
 }
 
 // TableName returns the name of the table for AnalysisResult.
@@ -66,19 +109,42 @@ type CreateAnalysisResultInput struct {
 // ToDTO converts an AnalysisResult model to AnalysisResultDTO.
 func (r *AnalysisResult) ToDTO() *AnalysisResultDTO {
 	return &AnalysisResultDTO{
-		ID:           r.ID,
-		URLID:        r.URLID,
-		HTMLVersion:  r.HTMLVersion,
-		Title:        r.Title,
-		H1Count:      r.H1Count,
-		H2Count:      r.H2Count,
-		H3Count:      r.H3Count,
-		H4Count:      r.H4Count,
-		H5Count:      r.H5Count,
-		H6Count:      r.H6Count,
-		HasLoginForm: r.HasLoginForm,
-		CreatedAt:    r.CreatedAt,
-		UpdatedAt:    r.UpdatedAt,
+		ID:                r.ID,
+		URLID:             r.URLID,
+		PageURL:           r.PageURL,
+		Depth:             r.Depth,
+		StatusCode:        r.StatusCode,
+		ContentType:       r.ContentType,
+		Charset:           r.Charset,
+		BodyTruncated:     r.BodyTruncated,
+		LinksIncomplete:   r.LinksIncomplete,
+		Modules:           r.Modules,
+		Health:            r.Health,
+		Assertions:        r.Assertions,
+		HTMLVersion:       r.HTMLVersion,
+		Title:             r.Title,
+		H1Count:           r.H1Count,
+		H2Count:           r.H2Count,
+		H3Count:           r.H3Count,
+		H4Count:           r.H4Count,
+		H5Count:           r.H5Count,
+		H6Count:           r.H6Count,
+		HasLoginForm:      r.HasLoginForm,
+		InternalLinkCount: r.InternalLinkCount,
+		ExternalLinkCount: r.ExternalLinkCount,
+		BrokenLinkCount:   r.BrokenLinkCount,
+		ErrorLinkCount:    r.ErrorLinkCount,
+		SEO:               r.SEO,
+		StructuredData:    r.StructuredData,
+		Accessibility:     r.Accessibility,
+		Security:          r.Security,
+		TLS:               r.TLS,
+		CertExpiresAt:     r.CertExpiresAt,
+		Redirects:         r.Redirects,
+		Robots:            r.Robots,
+		Timing:            r.Timing,
+		CreatedAt:         r.CreatedAt,
+		UpdatedAt:         r.UpdatedAt,
 	}
 }
 
@@ -0,0 +1,40 @@
+package model
+
+// Assertion rule types.
+const (
+	// AssertSelectorExists passes when Selector matches at least one element.
+	AssertSelectorExists = "selector_exists"
+	// AssertSelectorAbsent passes when Selector matches no element.
+	AssertSelectorAbsent = "selector_absent"
+	// AssertTitleMatches passes when the page title matches the regular expression Pattern.
+	AssertTitleMatches = "title_matches"
+	// AssertMetaRobotsExcludes passes when the meta robots tag lacks the directive Value.
+	AssertMetaRobotsExcludes = "meta_robots_excludes"
+	// AssertMaxBrokenLinks passes when at most Max links are broken.
+	AssertMaxBrokenLinks = "max_broken_links"
+	// AssertMaxBrokenInternalLinks passes when at most Max links to the page's own host are broken.
+	AssertMaxBrokenInternalLinks = "max_broken_internal_links"
+)
+
+// Health statuses of an analysis with assertion rules.
+const (
+	HealthPassing = "passing"
+	HealthFailing = "failing"
+)
+
+// AssertionRule is a user-defined check evaluated on every analysis of a URL, e.g. to catch
+// regressions in a release pipeline. Which fields apply depends on Type.
+type AssertionRule struct {
+	Type     string `json:"type" example:"selector_exists"`
+	Selector string `json:"selector,omitempty" example:"#checkout"`
+	Pattern  string `json:"pattern,omitempty"`
+	Value    string `json:"value,omitempty"`
+	Max      int    `json:"max,omitempty"`
+}
+
+// AssertionResult is the outcome of one rule. Message says why a rule failed.
+type AssertionResult struct {
+	Rule    AssertionRule `json:"rule"`
+	Passed  bool          `json:"passed"`
+	Message string        `json:"message,omitempty"`
+}
@@ -0,0 +1,76 @@
+package model
+
+// MaxBulkItems bounds the URLs one bulk import or bulk action may touch.
+const MaxBulkItems = 1000
+
+// URLFilter selects a user's URLs; empty fields match everything.
+type URLFilter struct {
+	IDs       []uint
+	Status    string
+	CrawlMode string
+	Query     string // substring of the original URL
+}
+
+// BulkImportOptions are the settings shared by every URL of an import.
+type BulkImportOptions struct {
+	CrawlMode string   `json:"crawl_mode" form:"crawl_mode" binding:"omitempty,oneof=page site" example:"page"`
+	MaxDepth  int      `json:"max_depth"  form:"max_depth"  binding:"gte=0"`
+	MaxPages  int      `json:"max_pages"  form:"max_pages"  binding:"gte=0"`
+	Modules   []string `json:"modules"    form:"modules"`
+	// Start queues every created URL for analysis right away.
+	Start bool `json:"start" form:"start"`
+}
+
+// BulkImportRequestDTO imports URLs from a JSON list, a sitemap, or both.
+type BulkImportRequestDTO struct {
+	URLs []string `json:"urls" example:"https://example.com,example.org/pricing"`
+	// SitemapURL points at a sitemap.xml (or sitemap index) whose pages are imported.
+	SitemapURL string `json:"sitemap_url" binding:"omitempty,url" example:"https://example.com/sitemap.xml"`
+	BulkImportOptions
+}
+
+// BulkRowResultDTO is the outcome of one input row.
+type BulkRowResultDTO struct {
+	Row   int    `json:"row"` // 1-based position in the input
+	Input string `json:"input"`
+	URL   string `json:"url,omitempty"` // normalized form
+	ID    uint   `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// BulkImportResultDTO reports what an import created, row by row.
+type BulkImportResultDTO struct {
+	Created int                `json:"created"`
+	Failed  int                `json:"failed"`
+	Started int                `json:"started"`
+	Rows    []BulkRowResultDTO `json:"rows"`
+}
+
+// URLFilterDTO selects URLs for a bulk action by their attributes.
+type URLFilterDTO struct {
+	Status    string `json:"status"     binding:"omitempty,oneof=queued running done error stopped" example:"error"`
+	CrawlMode string `json:"crawl_mode" binding:"omitempty,oneof=page site"`
+	Query     string `json:"q"          example:"example.com"`
+}
+
+// BulkActionRequestDTO selects URLs either by ID or by filter.
+type BulkActionRequestDTO struct {
+	IDs    []uint        `json:"ids" example:"1,2,3"`
+	Filter *URLFilterDTO `json:"filter"`
+	// KeepPartial keeps the results collected before a stop; only used when stopping.
+	KeepPartial bool `json:"keep_partial"`
+}
+
+// BulkItemResultDTO is the outcome of a bulk action on one URL.
+type BulkItemResultDTO struct {
+	ID    uint   `json:"id"`
+	Error string `json:"error,omitempty"`
+}
+
+// BulkActionResultDTO reports a bulk action URL by URL.
+type BulkActionResultDTO struct {
+	Matched   int                 `json:"matched"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Results   []BulkItemResultDTO `json:"results"`
+}
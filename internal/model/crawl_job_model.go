@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+const (
+	JobPending = "pending"
+	JobLeased  = "leased"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+// CrawlJob is a durable queue entry for one requested URL analysis.
+// A worker leases a job for a limited time; a lease that is not renewed
+// (e.g. because the process crashed) expires and the job becomes claimable again.
+type CrawlJob struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	URLID       uint       `gorm:"not null;index" json:"url_id"`
+	Status      string     `gorm:"type:enum('pending','leased','done','failed');default:'pending';not null;index" json:"status"`
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+	LeaseOwner  string     `gorm:"type:varchar(128)" json:"lease_owner"`
+	LeasedUntil *time.Time `gorm:"index" json:"leased_until"`
+	AvailableAt time.Time  `gorm:"not null;index" json:"available_at"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName returns the name of the table for CrawlJob.
+func (CrawlJob) TableName() string {
+	return "crawl_jobs"
+}
@@ -0,0 +1,61 @@
+package model
+
+// Categories of link-check failures where no HTTP response was received.
+const (
+	LinkErrDNS         = "dns"
+	LinkErrTimeout     = "timeout"
+	LinkErrTLS         = "tls"
+	LinkErrRefused     = "connection_refused"
+	LinkErrReset       = "connection_reset"
+	LinkErrUnreachable = "unreachable"
+	LinkErrBlocked     = "blocked"
+	LinkErrOther       = "other"
+)
+
+// LinkErrorCategories lists every category, in the order they are documented.
+var LinkErrorCategories = []string{
+	LinkErrDNS,
+	LinkErrTimeout,
+	LinkErrTLS,
+	LinkErrRefused,
+	LinkErrReset,
+	LinkErrUnreachable,
+	LinkErrBlocked,
+	LinkErrOther,
+}
+
+// LinkErrorAny matches every failed link when filtering.
+const LinkErrorAny = "any"
+
+// ValidLinkErrorFilter reports whether f is a category or LinkErrorAny.
+func ValidLinkErrorFilter(f string) bool {
+	if f == LinkErrorAny {
+		return true
+	}
+	for _, c := range LinkErrorCategories {
+		if c == f {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBroken reports whether the link failed, either with an error status or without a response.
+func (l *Link) IsBroken() bool {
+	return l.ErrorCategory != "" || IsBrokenStatus(l.StatusCode)
+}
+
+// FilterLinksByError keeps the links whose check failed with the given category,
+// or with any category for LinkErrorAny. An empty filter keeps everything.
+func FilterLinksByError(links []*Link, filter string) []*Link {
+	if filter == "" {
+		return links
+	}
+	out := []*Link{}
+	for _, l := range links {
+		if l.ErrorCategory != "" && (filter == LinkErrorAny || l.ErrorCategory == filter) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
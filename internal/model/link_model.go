@@ -7,26 +7,37 @@ import (
 )
 
 // Link represents a hyperlink found on a URL's page.
+// AnalysisResultID ties it to the snapshot that found it; rows saved before it existed are nil.
 type Link struct {
-	ID         uint           `gorm:"primaryKey;autoIncrement" json:"id"`
-	URLID      uint           `gorm:"not null;index" json:"url_id"`
-	Href       string         `gorm:"type:text;not null" json:"href"`
-	IsExternal bool           `json:"is_external"`
-	StatusCode int            `json:"status_code"`
-	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	ID               uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	URLID            uint           `gorm:"not null;index" json:"url_id"`
+	AnalysisResultID *uint          `gorm:"index" json:"analysis_result_id"`
+	Href             string         `gorm:"type:text;not null" json:"href"`
+	IsExternal       bool           `json:"is_external"`
+	StatusCode       int            `json:"status_code"`
+	ErrorCategory    string         `gorm:"size:32;index" json:"error_category,omitempty"`
+	ErrorMessage     string         `gorm:"type:text" json:"error_message,omitempty"`
+	Redirects        *RedirectChain `gorm:"type:json;serializer:json" json:"redirects,omitempty"`
+	TTFBMs           int64          `gorm:"column:ttfb_ms" json:"ttfb_ms"`
+	CreatedAt        time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // LinkDTO is a data transfer object for Link responses
 type LinkDTO struct {
-	ID         uint      `json:"id"`
-	URLID      uint      `json:"url_id"`
-	Href       string    `json:"href"`
-	IsExternal bool      `json:"is_external"`
-	StatusCode int       `json:"status_code"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID               uint           `json:"id"`
+	URLID            uint           `json:"url_id"`
+	AnalysisResultID *uint          `json:"analysis_result_id"`
+	Href             string         `json:"href"`
+	IsExternal       bool           `json:"is_external"`
+	StatusCode       int            `json:"status_code"`
+	ErrorCategory    string         `json:"error_category,omitempty"`
+	ErrorMessage     string         `json:"error_message,omitempty"`
+	Redirects        *RedirectChain `json:"redirects,omitempty"`
+	TTFBMs           int64          `json:"ttfb_ms"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
 }
 
 // TableName returns the name of the table for Link.
@@ -45,13 +56,18 @@ type CreateLinkInput struct {
 // ToDTO transforms a Link model into a LinkDTO for responses.
 func (l *Link) ToDTO() *LinkDTO {
 	return &LinkDTO{
-		ID:         l.ID,
-		URLID:      l.URLID,
-		Href:       l.Href,
-		IsExternal: l.IsExternal,
-		StatusCode: l.StatusCode,
-		CreatedAt:  l.CreatedAt,
-		UpdatedAt:  l.UpdatedAt,
+		ID:               l.ID,
+		URLID:            l.URLID,
+		AnalysisResultID: l.AnalysisResultID,
+		Href:             l.Href,
+		IsExternal:       l.IsExternal,
+		StatusCode:       l.StatusCode,
+		ErrorCategory:    l.ErrorCategory,
+		ErrorMessage:     l.ErrorMessage,
+		Redirects:        l.Redirects,
+		TTFBMs:           l.TTFBMs,
+		CreatedAt:        l.CreatedAt,
+		UpdatedAt:        l.UpdatedAt,
 	}
 }
 
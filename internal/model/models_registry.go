@@ -7,4 +7,9 @@ var AllModels = []interface{}{
 	&AnalysisResult{},
 	&Link{},
 	&BlacklistedToken{},
+	&SiteSummary{},
+	&CrawlJob{},
+	&Schedule{},
+	&Webhook{},
+	&WebhookDelivery{},
 }
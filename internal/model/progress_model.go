@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Progress event types.
+const (
+	ProgressStatus = "status"   // the URL's status changed
+	ProgressUpdate = "progress" // an analysis checked more links or crawled another page
+)
+
+// ProgressEvent is a live update on a URL's analysis, streamed to clients watching it.
+type ProgressEvent struct {
+	URLID        uint      `json:"url_id"`
+	Type         string    `json:"type"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	PageURL      string    `json:"page_url,omitempty"`      // page whose links are being checked
+	PagesCrawled int       `json:"pages_crawled,omitempty"` // pages finished so far in a site crawl
+	LinksChecked int       `json:"links_checked,omitempty"` // links of PageURL checked so far
+	LinksTotal   int       `json:"links_total,omitempty"`   // links found on PageURL
+	At           time.Time `json:"at"`
+}
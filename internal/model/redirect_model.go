@@ -0,0 +1,17 @@
+package model
+
+// RedirectChain records the hops a request went through before its final response.
+type RedirectChain struct {
+	Hops      []RedirectHop `json:"hops"`
+	FinalURL  string        `json:"final_url"`
+	Loop      bool          `json:"loop"`
+	Downgrade bool          `json:"downgrade"`
+	Excessive bool          `json:"excessive"`
+}
+
+// RedirectHop is one redirect response: the URL requested, its status and where it pointed.
+type RedirectHop struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	Location   string `json:"location"`
+}
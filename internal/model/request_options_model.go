@@ -0,0 +1,45 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// RequestOptions are the extra headers and cookies sent with every request to a URL's host,
+// e.g. to analyze a staging page behind an auth cookie.
+type RequestOptions struct {
+	Headers map[string]string
+	Cookies map[string]string
+}
+
+// reservedHeaders are managed by the HTTP client and cannot be overridden per URL.
+// Cookies have their own field so that they are scoped like headers.
+var reservedHeaders = map[string]bool{
+	"Host":              true,
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+	"Connection":        true,
+	"Cookie":            true,
+}
+
+// ValidateRequestOptions checks that custom headers and cookies can be sent as given.
+func ValidateRequestOptions(headers, cookies map[string]string) error {
+	for name, value := range headers {
+		if !httpguts.ValidHeaderFieldName(name) || !httpguts.ValidHeaderFieldValue(value) {
+			return fmt.Errorf("invalid request header %q", name)
+		}
+		if reservedHeaders[http.CanonicalHeaderKey(name)] {
+			return fmt.Errorf("request header %q cannot be set", name)
+		}
+	}
+	for name, value := range cookies {
+		c := &http.Cookie{Name: name, Value: value}
+		if c.Valid() != nil || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("invalid request cookie %q", name)
+		}
+	}
+	return nil
+}
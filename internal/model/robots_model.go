@@ -0,0 +1,19 @@
+package model
+
+// Outcomes of fetching a host's robots.txt, following RFC 9309.
+const (
+	RobotsOK          = "ok"          // 2xx: the rules were parsed
+	RobotsUnavailable = "unavailable" // 4xx: no restrictions apply
+	RobotsUnreachable = "unreachable" // 5xx: everything is disallowed
+	RobotsFailed      = "failed"      // no response: the rules are unknown and checks go ahead
+)
+
+// RobotsReport describes how the robots.txt of the analyzed host applies to the page and its links.
+type RobotsReport struct {
+	Status         string   `json:"status"`
+	StatusCode     int      `json:"status_code,omitempty"`
+	PageDisallowed bool     `json:"page_disallowed"`
+	CrawlDelay     float64  `json:"crawl_delay,omitempty"` // seconds
+	Sitemaps       []string `json:"sitemaps"`
+	SkippedLinks   []string `json:"skipped_links"`
+}
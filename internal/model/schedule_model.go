@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// Schedule re-analyzes a URL either on a cron expression or at a fixed interval.
+type Schedule struct {
+	ID              uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	URLID           uint       `gorm:"not null;uniqueIndex" json:"url_id"`
+	CronExpr        string     `gorm:"type:varchar(100)" json:"cron,omitempty"`
+	IntervalSeconds int        `gorm:"not null;default:0" json:"interval_seconds,omitempty"`
+	Paused          bool       `gorm:"not null;default:false" json:"paused"`
+	NextRunAt       *time.Time `gorm:"index" json:"next_run_at"`
+	LastRunAt       *time.Time `json:"last_run_at"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName returns the name of the table for Schedule.
+func (Schedule) TableName() string {
+	return "schedules"
+}
+
+// ScheduleInputDTO sets a URL's schedule; exactly one of Cron and IntervalSeconds must be given.
+type ScheduleInputDTO struct {
+	Cron            string `json:"cron"             binding:"omitempty,max=100" example:"0 6 * * *"`
+	IntervalSeconds int    `json:"interval_seconds" binding:"omitempty,gte=60" example:"86400"`
+	Paused          bool   `json:"paused"`
+}
+
+// ScheduleDTO is the data transfer object for Schedule.
+type ScheduleDTO struct {
+	URLID           uint       `json:"url_id"`
+	OriginalURL     string     `json:"original_url,omitempty"`
+	Cron            string     `json:"cron,omitempty"`
+	IntervalSeconds int        `json:"interval_seconds,omitempty"`
+	Paused          bool       `json:"paused"`
+	NextRunAt       *time.Time `json:"next_run_at"`
+	LastRunAt       *time.Time `json:"last_run_at"`
+}
+
+// ToDTO converts a Schedule to a ScheduleDTO.
+func (s *Schedule) ToDTO() *ScheduleDTO {
+	return &ScheduleDTO{
+		URLID:           s.URLID,
+		Cron:            s.CronExpr,
+		IntervalSeconds: s.IntervalSeconds,
+		Paused:          s.Paused,
+		NextRunAt:       s.NextRunAt,
+		LastRunAt:       s.LastRunAt,
+	}
+}
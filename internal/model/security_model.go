@@ -0,0 +1,49 @@
+package model
+
+// Outcomes of a single security check.
+const (
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// SecurityReport grades a page's response headers and cookies.
+type SecurityReport struct {
+	Grade   string        `json:"grade"`
+	Score   int           `json:"score"`
+	Headers []HeaderCheck `json:"headers"`
+	Cookies []CookieCheck `json:"cookies"`
+}
+
+// HeaderCheck is the verdict on one security header.
+type HeaderCheck struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// CookieCheck lists the flags set on one cookie and what is missing.
+type CookieCheck struct {
+	Name     string   `json:"name"`
+	Secure   bool     `json:"secure"`
+	HTTPOnly bool     `json:"http_only"`
+	SameSite string   `json:"same_site"`
+	Issues   []string `json:"issues"`
+}
+
+// SecurityGrade maps a 0–100 score onto a letter grade.
+func SecurityGrade(score int) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 75:
+		return "B"
+	case score >= 60:
+		return "C"
+	case score >= 40:
+		return "D"
+	default:
+		return "F"
+	}
+}
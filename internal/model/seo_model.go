@@ -0,0 +1,18 @@
+package model
+
+// SEOMetadata holds the search-engine related tags found in a page's head.
+type SEOMetadata struct {
+	MetaDescription string            `json:"meta_description"`
+	MetaRobots      string            `json:"meta_robots"`
+	Canonical       string            `json:"canonical"`
+	Viewport        string            `json:"viewport"`
+	Hreflang        []HreflangLink    `json:"hreflang"`
+	OpenGraph       map[string]string `json:"open_graph"`
+	TwitterCard     map[string]string `json:"twitter_card"`
+}
+
+// HreflangLink is one <link rel="alternate" hreflang="..."> entry.
+type HreflangLink struct {
+	Lang string `json:"lang"`
+	Href string `json:"href"`
+}
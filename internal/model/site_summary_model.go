@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SiteSummary aggregates the per-page results of one site crawl.
+type SiteSummary struct {
+	ID                uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	URLID             uint           `gorm:"not null;index" json:"url_id"`
+	PagesCrawled      int            `json:"pages_crawled"`
+	PagesFailed       int            `json:"pages_failed"`
+	MaxDepthReached   int            `json:"max_depth_reached"`
+	InternalLinkCount int            `json:"internal_link_count"`
+	ExternalLinkCount int            `json:"external_link_count"`
+	BrokenLinkCount   int            `json:"broken_link_count"`
+	ErrorLinkCount    int            `json:"error_link_count"`
+	LoginFormPages    int            `json:"login_form_pages"`
+	CreatedAt         time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName returns the name of the table for SiteSummary.
+func (SiteSummary) TableName() string {
+	return "site_summaries"
+}
+
+// PageResult pairs one crawled page's analysis with the links found on it.
+type PageResult struct {
+	Result *AnalysisResult
+	Links  []Link
+}
+
+// Add folds one page's analysis into the summary.
+func (s *SiteSummary) Add(res *AnalysisResult) {
+	s.PagesCrawled++
+	if res.Depth > s.MaxDepthReached {
+		s.MaxDepthReached = res.Depth
+	}
+	s.InternalLinkCount += res.InternalLinkCount
+	s.ExternalLinkCount += res.ExternalLinkCount
+	s.BrokenLinkCount += res.BrokenLinkCount
+	s.ErrorLinkCount += res.ErrorLinkCount
+	if res.HasLoginForm {
+		s.LoginFormPages++
+	}
+}
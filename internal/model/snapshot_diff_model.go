@@ -0,0 +1,116 @@
+package model
+
+import "sort"
+
+// FieldChangeDTO records a scalar value that differs between two snapshots.
+type FieldChangeDTO struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// HeadingDeltaDTO holds per-level heading count differences (to minus from).
+type HeadingDeltaDTO struct {
+	H1 int `json:"h1"`
+	H2 int `json:"h2"`
+	H3 int `json:"h3"`
+	H4 int `json:"h4"`
+	H5 int `json:"h5"`
+	H6 int `json:"h6"`
+}
+
+// LinkStatusChangeDTO describes a link present in both snapshots whose status changed.
+// ToError is set when the newer check got no response at all.
+type LinkStatusChangeDTO struct {
+	Href       string `json:"href"`
+	FromStatus int    `json:"from_status"`
+	ToStatus   int    `json:"to_status"`
+	ToError    string `json:"to_error,omitempty"`
+}
+
+// SnapshotDiffQuery selects the two snapshots to compare; with neither given the root pages
+// of the two latest runs are compared.
+type SnapshotDiffQuery struct {
+	From uint `form:"from"`
+	To   uint `form:"to"`
+}
+
+// SnapshotDiffDTO describes what changed between two analysis snapshots of a URL.
+type SnapshotDiffDTO struct {
+	URLID         uint                  `json:"url_id"`
+	From          *AnalysisResultDTO    `json:"from"`
+	To            *AnalysisResultDTO    `json:"to"`
+	Title         *FieldChangeDTO       `json:"title,omitempty"`
+	HTMLVersion   *FieldChangeDTO       `json:"html_version,omitempty"`
+	SecurityGrade *FieldChangeDTO       `json:"security_grade,omitempty"`
+	Headings      HeadingDeltaDTO       `json:"headings"`
+	LinksAdded    []LinkDTO             `json:"links_added"`
+	LinksRemoved  []LinkDTO             `json:"links_removed"`
+	NewlyBroken   []LinkStatusChangeDTO `json:"newly_broken"`
+}
+
+// IsBrokenStatus reports whether an HTTP status code counts as a broken link.
+func IsBrokenStatus(code int) bool {
+	return code >= 400 && code < 600
+}
+
+// DiffSnapshots compares two snapshots of the same URL and the links recorded with each.
+func DiffSnapshots(from, to *AnalysisResult, fromLinks, toLinks []Link) *SnapshotDiffDTO {
+	d := &SnapshotDiffDTO{
+		URLID: to.URLID,
+		From:  from.ToDTO(),
+		To:    to.ToDTO(),
+		Headings: HeadingDeltaDTO{
+			H1: to.H1Count - from.H1Count,
+			H2: to.H2Count - from.H2Count,
+			H3: to.H3Count - from.H3Count,
+			H4: to.H4Count - from.H4Count,
+			H5: to.H5Count - from.H5Count,
+			H6: to.H6Count - from.H6Count,
+		},
+		LinksAdded:   []LinkDTO{},
+		LinksRemoved: []LinkDTO{},
+		NewlyBroken:  []LinkStatusChangeDTO{},
+	}
+	if from.Title != to.Title {
+		d.Title = &FieldChangeDTO{From: from.Title, To: to.Title}
+	}
+	if from.HTMLVersion != to.HTMLVersion {
+		d.HTMLVersion = &FieldChangeDTO{From: from.HTMLVersion, To: to.HTMLVersion}
+	}
+	if from.Security != nil && to.Security != nil && from.Security.Grade != to.Security.Grade {
+		d.SecurityGrade = &FieldChangeDTO{From: from.Security.Grade, To: to.Security.Grade}
+	}
+
+	before := make(map[string]*Link, len(fromLinks))
+	for i := range fromLinks {
+		before[fromLinks[i].Href] = &fromLinks[i]
+	}
+	after := make(map[string]*Link, len(toLinks))
+	for i := range toLinks {
+		l := &toLinks[i]
+		after[l.Href] = l
+		old, ok := before[l.Href]
+		if !ok {
+			d.LinksAdded = append(d.LinksAdded, *l.ToDTO())
+			continue
+		}
+		if old.StatusCode > 0 && !old.IsBroken() && l.IsBroken() {
+			d.NewlyBroken = append(d.NewlyBroken, LinkStatusChangeDTO{
+				Href:       l.Href,
+				FromStatus: old.StatusCode,
+				ToStatus:   l.StatusCode,
+				ToError:    l.ErrorCategory,
+			})
+		}
+	}
+	for i := range fromLinks {
+		if _, ok := after[fromLinks[i].Href]; !ok {
+			d.LinksRemoved = append(d.LinksRemoved, *fromLinks[i].ToDTO())
+		}
+	}
+
+	sort.Slice(d.LinksAdded, func(i, j int) bool { return d.LinksAdded[i].Href < d.LinksAdded[j].Href })
+	sort.Slice(d.LinksRemoved, func(i, j int) bool { return d.LinksRemoved[i].Href < d.LinksRemoved[j].Href })
+	sort.Slice(d.NewlyBroken, func(i, j int) bool { return d.NewlyBroken[i].Href < d.NewlyBroken[j].Href })
+	return d
+}
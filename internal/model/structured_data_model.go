@@ -0,0 +1,28 @@
+package model
+
+// Structured data syntaxes recognised by the analyzer.
+const (
+	FormatJSONLD    = "json-ld"
+	FormatMicrodata = "microdata"
+	FormatRDFa      = "rdfa"
+)
+
+// StructuredData lists the schema.org entities found on a page and any markup that could not be read.
+type StructuredData struct {
+	Items  []StructuredDataItem  `json:"items"`
+	Errors []StructuredDataError `json:"errors"`
+}
+
+// StructuredDataItem is one top-level typed entity and the properties set on it.
+type StructuredDataItem struct {
+	Format     string   `json:"format"`
+	Type       string   `json:"type"`
+	Properties []string `json:"properties"`
+}
+
+// StructuredDataError flags a malformed block; Block is its position among blocks of the same format.
+type StructuredDataError struct {
+	Format  string `json:"format"`
+	Block   int    `json:"block"`
+	Message string `json:"message"`
+}
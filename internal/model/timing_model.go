@@ -0,0 +1,13 @@
+package model
+
+// PageTiming breaks down how long the page fetch took, in milliseconds.
+// Phases repeat for every connection a redirect chain opens and are summed.
+type PageTiming struct {
+	DNSMs         int64 `json:"dns_ms"`
+	ConnectMs     int64 `json:"connect_ms"`
+	TLSMs         int64 `json:"tls_ms"`
+	TTFBMs        int64 `gorm:"column:ttfb_ms" json:"ttfb_ms"`
+	DownloadMs    int64 `json:"download_ms"`
+	TotalMs       int64 `json:"total_ms"`
+	ResponseBytes int64 `json:"response_bytes"`
+}
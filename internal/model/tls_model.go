@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// TLSReport describes the TLS session and certificate chain a page was served with.
+type TLSReport struct {
+	Version     string            `json:"version"`
+	CipherSuite string            `json:"cipher_suite"`
+	Verified    bool              `json:"verified"`
+	VerifyError string            `json:"verify_error,omitempty"`
+	Chain       []CertificateInfo `json:"chain"`
+}
+
+// CertificateInfo summarises one certificate of the peer chain, leaf first.
+type CertificateInfo struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	DNSNames     []string  `json:"dns_names"`
+	SerialNumber string    `json:"serial_number"`
+	KeyType      string    `json:"key_type"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+}
+
+// ExpiringCertificateDTO is a URL whose latest snapshot was served with a soon-to-expire certificate.
+type ExpiringCertificateDTO struct {
+	URLID       uint      `json:"url_id"`
+	OriginalURL string    `json:"original_url"`
+	SnapshotID  uint      `json:"snapshot_id"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	DaysLeft    int       `json:"days_left"`
+}
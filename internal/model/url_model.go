@@ -15,17 +15,29 @@ const (
 	StatusStopped = "stopped"
 )
 
+const (
+	CrawlModePage = "page"
+	CrawlModeSite = "site"
+)
+
 // URL represents a URL to be analyzed and its processing status.
 type URL struct {
-	ID              uint             `gorm:"primaryKey;autoIncrement" json:"id"`
-	UserID          uint             `gorm:"not null;index" json:"user_id"`
-	OriginalURL     string           `gorm:"type:varchar(191);uniqueIndex;not null" json:"original_url"`
-	Status          string           `gorm:"type:enum('queued','running','done','error','stopped');default:'queued';not null" json:"status"`
-	AnalysisResults []AnalysisResult `gorm:"foreignKey:URLID"`
-	Links           []Link           `gorm:"foreignKey:URLID"`
-	CreatedAt       time.Time        `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt       time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt       gorm.DeletedAt   `gorm:"index" json:"-"`
+	ID              uint              `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID          uint              `gorm:"not null;index" json:"user_id"`
+	OriginalURL     string            `gorm:"type:varchar(191);uniqueIndex;not null" json:"original_url"`
+	Status          string            `gorm:"type:enum('queued','running','done','error','stopped');default:'queued';not null" json:"status"`
+	CrawlMode       string            `gorm:"type:enum('page','site');default:'page';not null" json:"crawl_mode"`
+	MaxDepth        int               `gorm:"not null;default:0" json:"max_depth"`
+	MaxPages        int               `gorm:"not null;default:0" json:"max_pages"`
+	RequestHeaders  map[string]string `gorm:"type:json;serializer:json" json:"request_headers,omitempty"`
+	RequestCookies  map[string]string `gorm:"type:json;serializer:json" json:"request_cookies,omitempty"`
+	Modules         []string          `gorm:"type:json;serializer:json" json:"modules,omitempty"`
+	Assertions      []AssertionRule   `gorm:"type:json;serializer:json" json:"assertions,omitempty"`
+	AnalysisResults []AnalysisResult  `gorm:"foreignKey:URLID"`
+	Links           []Link            `gorm:"foreignKey:URLID"`
+	CreatedAt       time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt       gorm.DeletedAt    `gorm:"index" json:"-"`
 }
 
 // TableName returns the name of the table for URL.
@@ -49,27 +61,51 @@ type PaginatedResponse[T any] struct {
 
 // URLDTO is the data transfer object for URL.
 type URLDTO struct {
-	ID          uint      `json:"id"`
-	UserID      uint      `json:"user_id"`
-	OriginalURL string    `json:"original_url"`
-	Status      string    `json:"status" binding:"omitempty,oneof=queued running done error"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID             uint              `json:"id"`
+	UserID         uint              `json:"user_id"`
+	OriginalURL    string            `json:"original_url"`
+	Status         string            `json:"status" binding:"omitempty,oneof=queued running done error"`
+	CrawlMode      string            `json:"crawl_mode"`
+	MaxDepth       int               `json:"max_depth"`
+	MaxPages       int               `json:"max_pages"`
+	RequestHeaders map[string]string `json:"request_headers,omitempty"`
+	RequestCookies map[string]string `json:"request_cookies,omitempty"`
+	Modules        []string          `json:"modules,omitempty"`
+	Assertions     []AssertionRule   `json:"assertions,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
 }
 
 // CreateURLInput defines required fields to create a URL.
 type CreateURLInputDTO struct {
-	UserID      uint   `json:"user_id" binding:"required"`
-	OriginalURL string `json:"original_url" binding:"required,url"`
+	UserID         uint              `json:"user_id" binding:"required"`
+	OriginalURL    string            `json:"original_url" binding:"required,url"`
+	CrawlMode      string            `json:"crawl_mode" binding:"omitempty,oneof=page site"`
+	MaxDepth       int               `json:"max_depth" binding:"gte=0"`
+	MaxPages       int               `json:"max_pages" binding:"gte=0"`
+	RequestHeaders map[string]string `json:"request_headers"`
+	RequestCookies map[string]string `json:"request_cookies"`
+	Modules        []string          `json:"modules"`
+	Assertions     []AssertionRule   `json:"assertions"`
 }
 type URLCreateRequestDTO struct {
-	OriginalURL string `json:"original_url" binding:"required,url" example:"https://example.com"`
+	OriginalURL    string            `json:"original_url" binding:"required,url" example:"https://example.com"`
+	CrawlMode      string            `json:"crawl_mode" binding:"omitempty,oneof=page site" example:"page"`
+	MaxDepth       int               `json:"max_depth" binding:"gte=0" example:"2"`
+	MaxPages       int               `json:"max_pages" binding:"gte=0" example:"50"`
+	RequestHeaders map[string]string `json:"request_headers"`
+	RequestCookies map[string]string `json:"request_cookies"`
+	// Modules selects the analysis modules to run; empty runs all of them.
+	Modules []string `json:"modules" example:"headings,seo"`
+	// Assertions are checked on every analysis and decide its health.
+	Assertions []AssertionRule `json:"assertions"`
 }
 
 type URLResultsDTO struct {
 	URL             *URLDTO           `json:"url"`
 	AnalysisResults []*AnalysisResult `json:"analysis_results"`
 	Links           []*Link           `json:"links"`
+	SiteSummary     *SiteSummary      `json:"site_summary,omitempty"`
 }
 
 // AnalysisResult represents a snapshot of the analysis
@@ -77,30 +113,63 @@ type URLResultsDTO struct {
 // ToDTO converts a URL model to a URLDTO.
 func (u *URL) ToDTO() *URLDTO {
 	return &URLDTO{
-		ID:          u.ID,
-		UserID:      u.UserID,
-		OriginalURL: u.OriginalURL,
-		Status:      u.Status,
-		CreatedAt:   u.CreatedAt,
-		UpdatedAt:   u.UpdatedAt,
+		ID:             u.ID,
+		UserID:         u.UserID,
+		OriginalURL:    u.OriginalURL,
+		Status:         u.Status,
+		CrawlMode:      u.CrawlMode,
+		MaxDepth:       u.MaxDepth,
+		MaxPages:       u.MaxPages,
+		RequestHeaders: u.RequestHeaders,
+		RequestCookies: u.RequestCookies,
+		Modules:        u.Modules,
+		Assertions:     u.Assertions,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
 	}
 }
 
 // FromCreateInput maps CreateURLInput to a URL model.
 func URLFromCreateInput(input *CreateURLInputDTO) *URL {
 	now := time.Now()
+	mode := input.CrawlMode
+	if mode == "" {
+		mode = CrawlModePage
+	}
 	return &URL{
-		UserID:      input.UserID,
-		OriginalURL: input.OriginalURL,
-		Status:      StatusQueued,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		UserID:         input.UserID,
+		OriginalURL:    input.OriginalURL,
+		Status:         StatusQueued,
+		CrawlMode:      mode,
+		MaxDepth:       input.MaxDepth,
+		MaxPages:       input.MaxPages,
+		RequestHeaders: input.RequestHeaders,
+		RequestCookies: input.RequestCookies,
+		Modules:        input.Modules,
+		Assertions:     input.Assertions,
+		CreatedAt:      now,
+		UpdatedAt:      now,
 	}
 }
 
 type UpdateURLInput struct {
 	OriginalURL string `json:"original_url" binding:"omitempty,url"`
 	Status      string `json:"status"        binding:"omitempty,oneof=queued running done error"`
+	CrawlMode   string `json:"crawl_mode"    binding:"omitempty,oneof=page site"`
+	MaxDepth    *int   `json:"max_depth"     binding:"omitempty,gte=0"`
+	MaxPages    *int   `json:"max_pages"     binding:"omitempty,gte=0"`
+	// Request headers and cookies replace the stored ones when present; an empty object clears them.
+	RequestHeaders map[string]string `json:"request_headers"`
+	RequestCookies map[string]string `json:"request_cookies"`
+	// Modules replace the stored selection when present; an empty list selects all modules.
+	Modules []string `json:"modules"`
+	// Assertions replace the stored rules when present; an empty list removes them.
+	Assertions []AssertionRule `json:"assertions"`
+}
+
+// RequestOptions returns the custom headers and cookies to send when analyzing the URL.
+func (u *URL) RequestOptions() RequestOptions {
+	return RequestOptions{Headers: u.RequestHeaders, Cookies: u.RequestCookies}
 }
 
 func (u *URL) URL() *url.URL {
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Webhook events.
+const (
+	EventAnalysisCompleted   = "analysis.completed"
+	EventAnalysisFailed      = "analysis.failed"
+	EventAnalysisStopped     = "analysis.stopped"
+	EventBrokenLinksDetected = "broken_links.detected"
+)
+
+// WebhookEvents lists every event a webhook can subscribe to.
+var WebhookEvents = []string{
+	EventAnalysisCompleted,
+	EventAnalysisFailed,
+	EventAnalysisStopped,
+	EventBrokenLinksDetected,
+}
+
+// Delivery statuses.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// Webhook is a user-registered endpoint notified of analysis events. Payloads are signed
+// with Secret so the receiver can verify where they came from.
+type Webhook struct {
+	ID        uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uint           `gorm:"not null;index" json:"user_id"`
+	TargetURL string         `gorm:"type:varchar(2048);not null" json:"url"`
+	Secret    string         `gorm:"type:varchar(64);not null" json:"-"`
+	Events    []string       `gorm:"type:json;serializer:json" json:"events"`
+	Active    bool           `gorm:"not null;default:true" json:"active"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName returns the name of the table for Webhook.
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// Subscribed reports whether the webhook wants event.
+func (w *Webhook) Subscribed(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidWebhookEvent reports whether event is one a webhook can subscribe to.
+func ValidWebhookEvent(event string) bool {
+	for _, e := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event sent, or to be sent, to a webhook, with the outcome of the
+// latest attempt. Failed attempts are retried with exponential backoff.
+type WebhookDelivery struct {
+	ID            uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	WebhookID     uint       `gorm:"not null;index" json:"webhook_id"`
+	Event         string     `gorm:"type:varchar(64);not null" json:"event"`
+	Payload       string     `gorm:"type:json" json:"payload"`
+	Status        string     `gorm:"type:enum('pending','succeeded','failed');default:'pending';not null;index" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	ResponseCode  int        `json:"response_code,omitempty"`
+	Error         string     `gorm:"type:text" json:"error,omitempty"`
+	NextAttemptAt *time.Time `gorm:"index" json:"next_attempt_at,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName returns the name of the table for WebhookDelivery.
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// AnalysisEvent describes a finished analysis; it is the data of every webhook payload.
+type AnalysisEvent struct {
+	URLID           uint      `json:"url_id"`
+	UserID          uint      `json:"-"`
+	OriginalURL     string    `json:"original_url"`
+	Status          string    `json:"status"`
+	Error           string    `json:"error,omitempty"`
+	PagesCrawled    int       `json:"pages_crawled,omitempty"`
+	BrokenLinkCount int       `json:"broken_link_count"`
+	Health          string    `json:"health,omitempty"`
+	FinishedAt      time.Time `json:"finished_at"`
+}
+
+// WebhookPayload is the JSON body posted to a webhook.
+type WebhookPayload struct {
+	Event     string        `json:"event"`
+	CreatedAt time.Time     `json:"created_at"`
+	Data      AnalysisEvent `json:"data"`
+}
+
+// WebhookInputDTO creates or replaces a webhook.
+type WebhookInputDTO struct {
+	URL    string   `json:"url"    binding:"required,url,max=2048" example:"https://ci.example.com/hooks/urlinsight"`
+	Events []string `json:"events" binding:"required,min=1" example:"analysis.completed,broken_links.detected"`
+	Active *bool    `json:"active"`
+}
+
+// WebhookDTO is the data transfer object for Webhook. The secret is only shown on creation.
+type WebhookDTO struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ToDTO converts a Webhook to a WebhookDTO without its secret.
+func (w *Webhook) ToDTO() *WebhookDTO {
+	return &WebhookDTO{
+		ID:        w.ID,
+		URL:       w.TargetURL,
+		Events:    w.Events,
+		Active:    w.Active,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 
 	"github.com/fuzumoe/urlinsight-backend/internal/model"
@@ -9,7 +11,19 @@ import (
 // AnalysisResultRepository defines DB ops for analysis results.
 type AnalysisResultRepository interface {
 	Create(res *model.AnalysisResult, links []model.Link) error
+	// FindURL returns the user's URL, without its results.
+	FindURL(userID, id uint) (*model.URL, error)
 	ListByURL(urlID uint, p Pagination) ([]model.AnalysisResult, error)
+	CountByURL(urlID uint) (int, error)
+	// FindByURL returns one snapshot, provided it belongs to the URL.
+	FindByURL(urlID, id uint) (*model.AnalysisResult, error)
+	// Latest returns the root-page snapshots of the URL's last n runs, newest first.
+	Latest(urlID uint, n int) ([]model.AnalysisResult, error)
+	// Links returns the links recorded together with a snapshot.
+	Links(res *model.AnalysisResult) ([]model.Link, error)
+	// ExpiringCertificates lists the user's URLs whose latest root-page snapshot
+	// carries a certificate expiring before the given time, soonest first.
+	ExpiringCertificates(userID uint, before time.Time) ([]model.ExpiringCertificateDTO, error)
 }
 
 type analysisResultRepo struct{ db *gorm.DB }
@@ -26,11 +40,20 @@ func (r *analysisResultRepo) Create(res *model.AnalysisResult, links []model.Lin
 		}
 		for i := range links {
 			links[i].URLID = res.URLID // foreign key
+			links[i].AnalysisResultID = &res.ID
 		}
 		return tx.CreateInBatches(&links, 500).Error
 	})
 }
 
+func (r *analysisResultRepo) FindURL(userID, id uint) (*model.URL, error) {
+	var u model.URL
+	if err := r.db.Where("user_id = ?", userID).First(&u, id).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
 // ListByURL returns paginated snapshots, newest first.
 func (r *analysisResultRepo) ListByURL(urlID uint, p Pagination) ([]model.AnalysisResult, error) {
 	var results []model.AnalysisResult
@@ -42,3 +65,82 @@ func (r *analysisResultRepo) ListByURL(urlID uint, p Pagination) ([]model.Analys
 		Find(&results).Error
 	return results, err
 }
+
+func (r *analysisResultRepo) CountByURL(urlID uint) (int, error) {
+	var count int64
+	err := r.db.Model(&model.AnalysisResult{}).Where("url_id = ?", urlID).Count(&count).Error
+	return int(count), err
+}
+
+func (r *analysisResultRepo) FindByURL(urlID, id uint) (*model.AnalysisResult, error) {
+	var res model.AnalysisResult
+	if err := r.db.Where("url_id = ?", urlID).First(&res, id).Error; err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (r *analysisResultRepo) Latest(urlID uint, n int) ([]model.AnalysisResult, error) {
+	var results []model.AnalysisResult
+	err := r.db.
+		Where("url_id = ? AND depth = 0", urlID).
+		Order("id DESC").
+		Limit(n).
+		Find(&results).Error
+	return results, err
+}
+
+// Links returns the links saved with a snapshot. Rows written before links carried
+// analysis_result_id are matched by creation time instead: the URL's untagged links
+// created between this snapshot and the next one (results and links share a TX).
+func (r *analysisResultRepo) Links(res *model.AnalysisResult) ([]model.Link, error) {
+	var links []model.Link
+	err := r.db.
+		Where("analysis_result_id = ?", res.ID).
+		Order("id").
+		Find(&links).Error
+	if err != nil || len(links) > 0 {
+		return links, err
+	}
+	return r.legacyLinks(res)
+}
+
+func (r *analysisResultRepo) legacyLinks(res *model.AnalysisResult) ([]model.Link, error) {
+	q := r.db.
+		Where("url_id = ? AND analysis_result_id IS NULL AND created_at >= ?", res.URLID, res.CreatedAt)
+
+	var next model.AnalysisResult
+	err := r.db.
+		Where("url_id = ? AND id > ?", res.URLID, res.ID).
+		Order("id").
+		Limit(1).
+		Find(&next).Error
+	if err != nil {
+		return nil, err
+	}
+	if next.ID != 0 {
+		q = q.Where("created_at < ?", next.CreatedAt)
+	}
+
+	var links []model.Link
+	err = q.Order("id").Find(&links).Error
+	return links, err
+}
+
+func (r *analysisResultRepo) ExpiringCertificates(userID uint, before time.Time) ([]model.ExpiringCertificateDTO, error) {
+	latest := r.db.
+		Model(&model.AnalysisResult{}).
+		Select("MAX(id)").
+		Where("depth = 0").
+		Group("url_id")
+
+	var out []model.ExpiringCertificateDTO
+	err := r.db.
+		Table("analysis_results AS ar").
+		Select("ar.url_id, urls.original_url, ar.id AS snapshot_id, ar.cert_expires_at AS expires_at").
+		Joins("JOIN urls ON urls.id = ar.url_id AND urls.deleted_at IS NULL").
+		Where("urls.user_id = ? AND ar.id IN (?) AND ar.cert_expires_at < ?", userID, latest, before).
+		Order("ar.cert_expires_at").
+		Scan(&out).Error
+	return out, err
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+// ErrLeaseLost is returned when a job's lease expired and was taken over by another worker.
+var ErrLeaseLost = errors.New("crawl job lease lost")
+
+// CrawlJobRepository defines DB ops for the durable crawl queue.
+type CrawlJobRepository interface {
+	// Enqueue adds a pending job for the URL unless one is already pending or leased.
+	// Concurrent calls for the same URL are serialized on the URL's row.
+	Enqueue(urlID uint) error
+	// Claim leases the oldest claimable job, or returns nil when the queue is empty. Jobs it
+	// comes across that have used up maxAttempts are failed along the way.
+	Claim(owner string, lease time.Duration, maxAttempts int) (*model.CrawlJob, error)
+	// Extend renews a lease held by owner.
+	Extend(jobID uint, owner string, lease time.Duration) error
+	// Complete marks a leased job as done.
+	Complete(jobID uint, owner string) error
+	// Release hands a leased job back to the queue and marks its URL as queued again. The
+	// interrupted claim does not count as an attempt.
+	Release(jobID uint, owner string) error
+	// Recover fails exhausted jobs and re-enqueues URLs left "running" without a live lease.
+	Recover(maxAttempts int) (int, error)
+}
+
+type crawlJobRepo struct {
+	db *gorm.DB
+}
+
+// NewCrawlJobRepo returns a CrawlJobRepository backed by GORM.
+func NewCrawlJobRepo(db *gorm.DB) CrawlJobRepository {
+	return &crawlJobRepo{db: db}
+}
+
+func (r *crawlJobRepo) Enqueue(urlID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the URL so a concurrent Enqueue waits for this one's insert before counting.
+		var url model.URL
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Take(&url, urlID).Error; err != nil {
+			return err
+		}
+
+		var open int64
+		if err := tx.Model(&model.CrawlJob{}).
+			Where("url_id = ? AND status IN ?", urlID, []string{model.JobPending, model.JobLeased}).
+			Count(&open).Error; err != nil {
+			return err
+		}
+		if open > 0 {
+			return nil
+		}
+		return tx.Create(&model.CrawlJob{
+			URLID:       urlID,
+			Status:      model.JobPending,
+			AvailableAt: time.Now(),
+		}).Error
+	})
+}
+
+// Claim uses SELECT ... FOR UPDATE SKIP LOCKED so concurrent workers never lease the same row.
+func (r *crawlJobRepo) Claim(owner string, lease time.Duration, maxAttempts int) (*model.CrawlJob, error) {
+	var claimed *model.CrawlJob
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		for {
+			var job model.CrawlJob
+			res := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("(status = ? AND available_at <= ?) OR (status = ? AND leased_until < ?)",
+					model.JobPending, now, model.JobLeased, now).
+				Order("id").
+				Limit(1).
+				Find(&job)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return nil
+			}
+
+			// A job out of attempts would otherwise sit in the queue, and its URL stay queued, forever.
+			if job.Attempts >= maxAttempts {
+				if err := failJobs(tx, []model.CrawlJob{job}); err != nil {
+					return err
+				}
+				continue
+			}
+
+			until := now.Add(lease)
+			if err := tx.Model(&model.CrawlJob{}).
+				Where("id = ?", job.ID).
+				Updates(map[string]any{
+					"status":       model.JobLeased,
+					"attempts":     gorm.Expr("attempts + 1"),
+					"lease_owner":  owner,
+					"leased_until": until,
+				}).Error; err != nil {
+				return err
+			}
+			job.Status = model.JobLeased
+			job.Attempts++
+			job.LeaseOwner = owner
+			job.LeasedUntil = &until
+			claimed = &job
+			return nil
+		}
+	})
+	return claimed, err
+}
+
+// failJobs marks jobs as failed and their URLs as errored.
+func failJobs(tx *gorm.DB, jobs []model.CrawlJob) error {
+	jobIDs := make([]uint, len(jobs))
+	urlIDs := make([]uint, len(jobs))
+	for i, j := range jobs {
+		jobIDs[i] = j.ID
+		urlIDs[i] = j.URLID
+	}
+	if err := tx.Model(&model.CrawlJob{}).
+		Where("id IN ?", jobIDs).
+		Updates(map[string]any{"status": model.JobFailed, "leased_until": nil}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&model.URL{}).
+		Where("id IN ?", urlIDs).
+		Update("status", model.StatusError).Error
+}
+
+func (r *crawlJobRepo) Extend(jobID uint, owner string, lease time.Duration) error {
+	res := r.db.Model(&model.CrawlJob{}).
+		Where("id = ? AND lease_owner = ? AND status = ?", jobID, owner, model.JobLeased).
+		Update("leased_until", time.Now().Add(lease))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (r *crawlJobRepo) Complete(jobID uint, owner string) error {
+	return r.db.Model(&model.CrawlJob{}).
+		Where("id = ? AND lease_owner = ?", jobID, owner).
+		Updates(map[string]any{
+			"status":       model.JobDone,
+			"leased_until": nil,
+		}).Error
+}
+
+func (r *crawlJobRepo) Release(jobID uint, owner string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var job model.CrawlJob
+		if err := tx.First(&job, jobID).Error; err != nil {
+			return err
+		}
+		if job.LeaseOwner != owner || job.Status != model.JobLeased {
+			return ErrLeaseLost
+		}
+		if err := tx.Model(&model.CrawlJob{}).
+			Where("id = ?", jobID).
+			Updates(map[string]any{
+				"status":       model.JobPending,
+				"attempts":     gorm.Expr("GREATEST(attempts - 1, 0)"),
+				"lease_owner":  "",
+				"leased_until": nil,
+				"available_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.URL{}).
+			Where("id = ?", job.URLID).
+			Update("status", model.StatusQueued).Error
+	})
+}
+
+func (r *crawlJobRepo) Recover(maxAttempts int) (int, error) {
+	var recovered int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// Jobs that used up their attempts will never be claimed again.
+		var exhausted []model.CrawlJob
+		if err := tx.Where("attempts >= ? AND (status = ? OR (status = ? AND leased_until < ?))",
+			maxAttempts, model.JobPending, model.JobLeased, now).
+			Find(&exhausted).Error; err != nil {
+			return err
+		}
+		if len(exhausted) > 0 {
+			if err := failJobs(tx, exhausted); err != nil {
+				return err
+			}
+		}
+
+		// URLs left "running" by a crashed process that have no open job get a fresh one.
+		res := tx.Exec(`INSERT INTO crawl_jobs (url_id, status, attempts, available_at, created_at, updated_at)
+SELECT u.id, ?, 0, ?, ?, ?
+FROM   urls u
+WHERE  u.status = ?
+AND    u.deleted_at IS NULL
+AND    NOT EXISTS (
+         SELECT 1 FROM crawl_jobs j
+         WHERE  j.url_id = u.id AND j.status IN ?
+       )`, model.JobPending, now, now, now, model.StatusRunning, []string{model.JobPending, model.JobLeased})
+		if res.Error != nil {
+			return res.Error
+		}
+		recovered = res.RowsAffected
+
+		// Anything still "running" without a live lease is waiting in the queue again.
+		return tx.Exec(`UPDATE urls u
+SET    u.status = ?
+WHERE  u.status = ?
+AND    NOT EXISTS (
+         SELECT 1 FROM crawl_jobs j
+         WHERE  j.url_id = u.id AND j.status = ? AND j.leased_until >= ?
+       )`, model.StatusQueued, model.StatusRunning, model.JobLeased, now).Error
+	})
+	return int(recovered), err
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+// exportBatchSize is the number of rows read per query while exporting.
+const exportBatchSize = 500
+
+// ExportRepository reads the tables behind exports in batches, so an export never holds
+// more than one batch in memory however large the table is.
+type ExportRepository interface {
+	// FindURL returns the user's URL, without its results.
+	FindURL(userID, id uint) (*model.URL, error)
+	// RunBounds returns the snapshot ID range [start, end) of the run containing snapshotID,
+	// or of the latest run when snapshotID is zero; end is zero for the latest run.
+	RunBounds(urlID, snapshotID uint) (start, end uint, err error)
+	// EachURL calls fn for each of the user's URLs in ID order, together with its latest
+	// root-page snapshot, or nil if it has never been analyzed.
+	EachURL(userID uint, fn func(u *model.URL, latest *model.AnalysisResult) error) error
+	// EachSnapshot calls fn for every snapshot of the URL, oldest first.
+	EachSnapshot(urlID uint, fn func(res *model.AnalysisResult) error) error
+	// EachLink calls fn for the links recorded by the snapshots in [start, end), and for
+	// legacy links without a snapshot created during that run, in ID order. A non-empty linkError keeps only links that failed with that category, or
+	// with any category for model.LinkErrorAny.
+	EachLink(urlID, start, end uint, linkError string, fn func(l *model.Link) error) error
+}
+
+type exportRepo struct {
+	db *gorm.DB
+}
+
+func NewExportRepo(db *gorm.DB) ExportRepository {
+	return &exportRepo{db: db}
+}
+
+func (r *exportRepo) FindURL(userID, id uint) (*model.URL, error) {
+	var u model.URL
+	if err := r.db.Where("user_id = ?", userID).First(&u, id).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *exportRepo) RunBounds(urlID, snapshotID uint) (start, end uint, err error) {
+	return runBounds(r.db, urlID, snapshotID)
+}
+
+func (r *exportRepo) EachURL(userID uint, fn func(u *model.URL, latest *model.AnalysisResult) error) error {
+	var batch []model.URL
+	return r.db.
+		Where("user_id = ?", userID).
+		FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+			ids := make([]uint, len(batch))
+			for i := range batch {
+				ids[i] = batch[i].ID
+			}
+			latestIDs := r.db.
+				Model(&model.AnalysisResult{}).
+				Select("MAX(id)").
+				Where("depth = 0 AND url_id IN ?", ids).
+				Group("url_id")
+			var snaps []model.AnalysisResult
+			if err := r.db.Where("id IN (?)", latestIDs).Find(&snaps).Error; err != nil {
+				return err
+			}
+			latest := make(map[uint]*model.AnalysisResult, len(snaps))
+			for i := range snaps {
+				latest[snaps[i].URLID] = &snaps[i]
+			}
+
+			for i := range batch {
+				if err := fn(&batch[i], latest[batch[i].ID]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+func (r *exportRepo) EachSnapshot(urlID uint, fn func(res *model.AnalysisResult) error) error {
+	var batch []model.AnalysisResult
+	return r.db.
+		Where("url_id = ?", urlID).
+		FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+			for i := range batch {
+				if err := fn(&batch[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+func (r *exportRepo) EachLink(urlID, start, end uint, linkError string, fn func(l *model.Link) error) error {
+	// Links saved before analysis_result_id existed are matched by the creation time of
+	// the run's snapshots, as in ResultsWithDetails.
+	q := r.db.Where("url_id = ?", urlID).Where(
+		"(analysis_result_id >= ? AND (? = 0 OR analysis_result_id < ?)) OR "+
+			"(analysis_result_id IS NULL "+
+			"AND created_at >= (SELECT created_at FROM analysis_results WHERE id = ?) "+
+			"AND (? = 0 OR created_at < (SELECT created_at FROM analysis_results WHERE id = ?)))",
+		start, end, end, start, end, end,
+	)
+	switch linkError {
+	case "":
+	case model.LinkErrorAny:
+		q = q.Where("error_category <> ''")
+	default:
+		q = q.Where("error_category = ?", linkError)
+	}
+
+	var batch []model.Link
+	return q.FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			if err := fn(&batch[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
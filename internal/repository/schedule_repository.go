@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+// ScheduleRepository defines DB ops for recurring URL analyses.
+type ScheduleRepository interface {
+	// Save creates or replaces the schedule of s.URLID.
+	Save(s *model.Schedule) error
+	// FindByURL returns the schedule of urlID, provided the URL belongs to userID.
+	FindByURL(userID, urlID uint) (*model.Schedule, error)
+	Delete(urlID uint) error
+	ListByUser(userID uint, p Pagination) ([]model.ScheduleDTO, error)
+	CountByUser(userID uint) (int, error)
+	// Due returns active schedules whose next run is at or before now and whose URL is idle:
+	// not running and without a pending or leased crawl job.
+	Due(now time.Time, limit int) ([]model.Schedule, error)
+	// Advance moves a schedule from prev to next, reporting false if another
+	// scheduler instance advanced it first.
+	Advance(id uint, prev, ranAt, next time.Time) (bool, error)
+}
+
+type scheduleRepo struct {
+	db *gorm.DB
+}
+
+// NewScheduleRepo returns a ScheduleRepository backed by GORM.
+func NewScheduleRepo(db *gorm.DB) ScheduleRepository {
+	return &scheduleRepo{db: db}
+}
+
+func (r *scheduleRepo) Save(s *model.Schedule) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "url_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"cron_expr", "interval_seconds", "paused", "next_run_at", "updated_at"}),
+	}).Create(s).Error
+}
+
+func (r *scheduleRepo) FindByURL(userID, urlID uint) (*model.Schedule, error) {
+	var s model.Schedule
+	if err := r.db.
+		Joins("JOIN urls u ON u.id = schedules.url_id AND u.deleted_at IS NULL").
+		Where("schedules.url_id = ? AND u.user_id = ?", urlID, userID).
+		First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *scheduleRepo) Delete(urlID uint) error {
+	return r.db.Where("url_id = ?", urlID).Delete(&model.Schedule{}).Error
+}
+
+func (r *scheduleRepo) ListByUser(userID uint, p Pagination) ([]model.ScheduleDTO, error) {
+	var out []model.ScheduleDTO
+	err := r.db.Table("schedules s").
+		Select("s.url_id, u.original_url, s.cron_expr AS cron, s.interval_seconds, s.paused, s.next_run_at, s.last_run_at").
+		Joins("JOIN urls u ON u.id = s.url_id AND u.deleted_at IS NULL").
+		Where("u.user_id = ?", userID).
+		Order("s.next_run_at IS NULL, s.next_run_at").
+		Limit(p.Limit()).
+		Offset(p.Offset()).
+		Scan(&out).Error
+	return out, err
+}
+
+func (r *scheduleRepo) CountByUser(userID uint) (int, error) {
+	var count int64
+	err := r.db.Table("schedules s").
+		Joins("JOIN urls u ON u.id = s.url_id AND u.deleted_at IS NULL").
+		Where("u.user_id = ?", userID).
+		Count(&count).Error
+	return int(count), err
+}
+
+func (r *scheduleRepo) Due(now time.Time, limit int) ([]model.Schedule, error) {
+	var out []model.Schedule
+	err := r.db.
+		Joins("JOIN urls u ON u.id = schedules.url_id AND u.deleted_at IS NULL").
+		Where("schedules.paused = ? AND schedules.next_run_at <= ?", false, now).
+		Where("u.status <> ?", model.StatusRunning).
+		Where("NOT EXISTS (SELECT 1 FROM crawl_jobs j WHERE j.url_id = schedules.url_id AND j.status IN ?)",
+			[]string{model.JobPending, model.JobLeased}).
+		Order("schedules.next_run_at").
+		Limit(limit).
+		Find(&out).Error
+	return out, err
+}
+
+func (r *scheduleRepo) Advance(id uint, prev, ranAt, next time.Time) (bool, error) {
+	res := r.db.Model(&model.Schedule{}).
+		Where("id = ? AND next_run_at = ?", id, prev).
+		Updates(map[string]any{
+			"last_run_at": ranAt,
+			"next_run_at": next,
+		})
+	return res.RowsAffected == 1, res.Error
+}
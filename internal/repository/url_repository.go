@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"gorm.io/gorm"
 
@@ -14,14 +15,22 @@ import (
 type URLRepository interface {
 	Create(u *model.URL) error
 	FindByID(id uint) (*model.URL, error)
+	// Status returns the URL's status without loading its results.
+	Status(id uint) (string, error)
 	CountByUser(userID uint) (int, error)
 	ListByUser(userID uint, p Pagination) ([]model.URL, error)
+	// IDsByUser returns the IDs of up to limit of the user's URLs matching f, in ID order.
+	IDsByUser(userID uint, f model.URLFilter, limit int) ([]uint, error)
 	Update(u *model.URL) error
 	Delete(id uint) error
 	UpdateStatus(id uint, status string) error
 	SaveResults(id uint, res *model.AnalysisResult, links []model.Link) error
+	SaveSiteResults(id uint, pages []model.PageResult, summary *model.SiteSummary) error
+	SiteSummary(id, snapshotID uint) (*model.SiteSummary, error)
 	Results(id uint) (*model.URL, error)
-	ResultsWithDetails(id uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error)
+	// ResultsWithDetails returns the results and links of one analysis run: the run
+	// containing snapshotID, or the latest run when snapshotID is zero.
+	ResultsWithDetails(id, snapshotID uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error)
 }
 
 type urlRepo struct {
@@ -53,6 +62,16 @@ func (r *urlRepo) FindByID(id uint) (*model.URL, error) {
 	return &u, nil
 }
 
+func (r *urlRepo) Status(id uint) (string, error) {
+	var status string
+	err := r.db.
+		Model(&model.URL{}).
+		Select("status").
+		Where("id = ?", id).
+		Take(&status).Error
+	return status, err
+}
+
 func (r *urlRepo) ListByUser(userID uint, p Pagination) ([]model.URL, error) {
 	var urls []model.URL
 	err := r.db.
@@ -63,6 +82,28 @@ func (r *urlRepo) ListByUser(userID uint, p Pagination) ([]model.URL, error) {
 	return urls, err
 }
 
+func (r *urlRepo) IDsByUser(userID uint, f model.URLFilter, limit int) ([]uint, error) {
+	q := r.db.Model(&model.URL{}).Where("user_id = ?", userID)
+	if len(f.IDs) > 0 {
+		q = q.Where("id IN ?", f.IDs)
+	}
+	if f.Status != "" {
+		q = q.Where("status = ?", f.Status)
+	}
+	if f.CrawlMode != "" {
+		q = q.Where("crawl_mode = ?", f.CrawlMode)
+	}
+	if f.Query != "" {
+		q = q.Where("original_url LIKE ?", "%"+likeEscaper.Replace(f.Query)+"%")
+	}
+	var ids []uint
+	err := q.Order("id").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// likeEscaper makes LIKE wildcards in user input match literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 func (r *urlRepo) Update(u *model.URL) error {
 	return r.db.Save(u).Error
 }
@@ -90,11 +131,59 @@ func (r *urlRepo) SaveResults(id uint, res *model.AnalysisResult, links []model.
 		}
 		for i := range links {
 			links[i].URLID = id
+			links[i].AnalysisResultID = &res.ID
 		}
 		return tx.CreateInBatches(&links, 500).Error
 	})
 }
 
+// SaveSiteResults stores every crawled page with its links plus the site summary in a single TX.
+func (r *urlRepo) SaveSiteResults(id uint, pages []model.PageResult, summary *model.SiteSummary) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, page := range pages {
+			page.Result.URLID = id
+			if err := tx.Create(page.Result).Error; err != nil {
+				return err
+			}
+			if len(page.Links) == 0 {
+				continue
+			}
+			for i := range page.Links {
+				page.Links[i].URLID = id
+				page.Links[i].AnalysisResultID = &page.Result.ID
+			}
+			if err := tx.CreateInBatches(&page.Links, 500).Error; err != nil {
+				return err
+			}
+		}
+		summary.URLID = id
+		return tx.Create(summary).Error
+	})
+}
+
+// SiteSummary returns the site-crawl summary of the run containing snapshotID, or the most
+// recent one when snapshotID is zero.
+func (r *urlRepo) SiteSummary(id, snapshotID uint) (*model.SiteSummary, error) {
+	q := r.db.Where("url_id = ?", id)
+	if snapshotID != 0 {
+		start, end, err := runBounds(r.db, id, snapshotID)
+		if err != nil {
+			return nil, err
+		}
+		// A run saves its summary after its pages, so it falls between the run's root
+		// snapshot and the next run's.
+		q = q.Where("created_at >= (?)", r.db.Model(&model.AnalysisResult{}).Select("created_at").Where("id = ?", start))
+		if end != 0 {
+			q = q.Where("created_at < (?)", r.db.Model(&model.AnalysisResult{}).Select("created_at").Where("id = ?", end))
+		}
+	}
+	var s model.SiteSummary
+	if err := q.Order("created_at DESC").First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
 // Already in your code - looks good
 func (r *urlRepo) Results(id uint) (*model.URL, error) {
 	var u model.URL
@@ -105,8 +194,40 @@ func (r *urlRepo) Results(id uint) (*model.URL, error) {
 	return &u, err
 }
 
-// ResultsWithDetails retrieves URL with analysis results and links in a single query
-func (r *urlRepo) ResultsWithDetails(id uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error) {
+// runBounds returns the snapshot ID range [start, end) of the run containing snapshotID,
+// or of the latest run when snapshotID is zero; end is zero for the latest run.
+// Every run saves its root page (depth 0) first, so runs begin at depth-0 snapshots.
+func runBounds(db *gorm.DB, id, snapshotID uint) (start, end uint, err error) {
+	upper := db.Model(&model.AnalysisResult{}).
+		Select("COALESCE(MAX(id), 0)").
+		Where("url_id = ? AND depth = 0", id)
+	if snapshotID != 0 {
+		var snap model.AnalysisResult
+		if err := db.Where("url_id = ?", id).First(&snap, snapshotID).Error; err != nil {
+			return 0, 0, err
+		}
+		upper = upper.Where("id <= ?", snapshotID)
+	}
+	if err := upper.Scan(&start).Error; err != nil {
+		return 0, 0, err
+	}
+	if snapshotID == 0 {
+		return start, 0, nil
+	}
+	err = db.Model(&model.AnalysisResult{}).
+		Select("COALESCE(MIN(id), 0)").
+		Where("url_id = ? AND depth = 0 AND id > ?", id, start).
+		Scan(&end).Error
+	return start, end, err
+}
+
+// ResultsWithDetails retrieves URL with one run's analysis results and links in a single query
+func (r *urlRepo) ResultsWithDetails(id, snapshotID uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error) {
+	start, end, err := runBounds(r.db, id, snapshotID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	// Use a single complex query to get everything at once
 	var resultJSON string
 	query := `SELECT
@@ -117,6 +238,9 @@ func (r *urlRepo) ResultsWithDetails(id uint) (*model.URL, []*model.AnalysisResu
         'user_id',      u.user_id,
         'original_url', u.original_url,
         'status',       u.status,
+        'crawl_mode',   u.crawl_mode,
+        'max_depth',    u.max_depth,
+        'max_pages',    u.max_pages,
         'created_at',   DATE_FORMAT(u.created_at, '%Y-%m-%dT%H:%i:%s.%fZ'),
         'updated_at',   DATE_FORMAT(u.updated_at, '%Y-%m-%dT%H:%i:%s.%fZ')
       ),
@@ -126,6 +250,15 @@ func (r *urlRepo) ResultsWithDetails(id uint) (*model.URL, []*model.AnalysisResu
                  JSON_OBJECT(
                    'id',                  ar.id,
                    'url_id',              ar.url_id,
+                   'page_url',            ar.page_url,
+                   'depth',               ar.depth,
+                   'status_code',         ar.status_code,
+                   'content_type',        ar.content_type,
+                   'charset',             ar.charset,
+                   'body_truncated',      IF(ar.body_truncated = 1, CAST('true' AS JSON), CAST('false' AS JSON)),
+                   'modules',             ar.modules,
+                   'health',              ar.health,
+                   'assertions',          ar.assertions,
                    'html_version',        ar.html_version,
                    'title',               ar.title,
                    'h1_count',            ar.h1_count,
@@ -138,33 +271,69 @@ func (r *urlRepo) ResultsWithDetails(id uint) (*model.URL, []*model.AnalysisResu
                    'internal_link_count', ar.internal_link_count,
                    'external_link_count', ar.external_link_count,
                    'broken_link_count',   ar.broken_link_count,
+                   'error_link_count',    ar.error_link_count,
+                   'links_incomplete',    IF(ar.links_incomplete = 1, CAST('true' AS JSON), CAST('false' AS JSON)),
+                   'seo',                 ar.seo,
+                   'structured_data',     ar.structured_data,
+                   'accessibility',       ar.accessibility,
+                   'security',            ar.security,
+                   'tls',                 ar.tls,
+                   'cert_expires_at',     DATE_FORMAT(ar.cert_expires_at, '%Y-%m-%dT%H:%i:%s.%fZ'),
+                   'redirects',           ar.redirects,
+                   'robots',              ar.robots,
+                   'timing',              JSON_OBJECT(
+                                            'dns_ms',         ar.timing_dns_ms,
+                                            'connect_ms',     ar.timing_connect_ms,
+                                            'tls_ms',         ar.timing_tls_ms,
+                                            'ttfb_ms',        ar.timing_ttfb_ms,
+                                            'download_ms',    ar.timing_download_ms,
+                                            'total_ms',       ar.timing_total_ms,
+                                            'response_bytes', ar.timing_response_bytes
+                                          ),
                    'created_at',          DATE_FORMAT(ar.created_at, '%Y-%m-%dT%H:%i:%s.%fZ'),
                    'updated_at',          DATE_FORMAT(ar.updated_at, '%Y-%m-%dT%H:%i:%s.%fZ')
                  )
                )
         FROM   analysis_results ar
         WHERE  ar.url_id = u.id
+          AND  ar.deleted_at IS NULL
+          AND  ar.id >= ? AND (? = 0 OR ar.id < ?)
         ORDER BY ar.created_at DESC
       ),
     'links',
       (
         SELECT JSON_ARRAYAGG(
                  JSON_OBJECT(
-                   'id',          l.id,
-                   'url_id',      l.url_id,
-                   'href',        l.href,
-                   'is_external', IF(l.is_external = 1, CAST('true' AS JSON), CAST('false' AS JSON)),
-                   'status_code', l.status_code
+                   'id',                 l.id,
+                   'url_id',             l.url_id,
+                   'analysis_result_id', l.analysis_result_id,
+                   'href',               l.href,
+                   'is_external',        IF(l.is_external = 1, CAST('true' AS JSON), CAST('false' AS JSON)),
+                   'status_code',        l.status_code,
+                   'error_category',     l.error_category,
+                   'error_message',      l.error_message,
+                   'redirects',          l.redirects,
+                   'ttfb_ms',            l.ttfb_ms
                  )
                )
         FROM   links l
         WHERE  l.url_id = u.id
+          AND  l.deleted_at IS NULL
+          AND  (
+                 (l.analysis_result_id >= ? AND (? = 0 OR l.analysis_result_id < ?))
+                 OR (
+                   l.analysis_result_id IS NULL
+                   AND l.created_at >= (SELECT created_at FROM analysis_results WHERE id = ?)
+                   AND (? = 0 OR l.created_at < (SELECT created_at FROM analysis_results WHERE id = ?))
+                 )
+               )
       )
   ) AS result_document
 FROM urls u
 WHERE u.id = ?`
-	// Execute the query with the URL ID
-	err := r.db.Raw(query, id).Scan(&resultJSON).Error
+	// Execute the query with the run bounds and the URL ID. Links saved before they were
+	// tied to a snapshot have no analysis_result_id and are matched to the run by time.
+	err = r.db.Raw(query, start, end, end, start, end, end, start, end, end, id).Scan(&resultJSON).Error
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to execute complex query: %w", err)
 	}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+// WebhookRepository defines DB ops for webhooks and their delivery log.
+type WebhookRepository interface {
+	Create(w *model.Webhook) error
+	FindByID(id uint) (*model.Webhook, error)
+	Update(w *model.Webhook) error
+	Delete(id uint) error
+	ListByUser(userID uint, p Pagination) ([]model.Webhook, error)
+	CountByUser(userID uint) (int, error)
+	// ActiveByUser returns the user's enabled webhooks.
+	ActiveByUser(userID uint) ([]model.Webhook, error)
+
+	CreateDelivery(d *model.WebhookDelivery) error
+	FindDelivery(id uint) (*model.WebhookDelivery, error)
+	UpdateDelivery(d *model.WebhookDelivery) error
+	ListDeliveries(webhookID uint, p Pagination) ([]model.WebhookDelivery, error)
+	CountDeliveries(webhookID uint) (int, error)
+	// DueDeliveries returns pending deliveries whose next attempt is at or before now.
+	DueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error)
+	// ClaimDelivery moves a pending delivery's next attempt from prev to until, reporting
+	// false if another dispatcher claimed it first.
+	ClaimDelivery(id uint, prev, until time.Time) (bool, error)
+}
+
+type webhookRepo struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepo returns a WebhookRepository backed by GORM.
+func NewWebhookRepo(db *gorm.DB) WebhookRepository {
+	return &webhookRepo{db: db}
+}
+
+func (r *webhookRepo) Create(w *model.Webhook) error {
+	return r.db.Create(w).Error
+}
+
+func (r *webhookRepo) FindByID(id uint) (*model.Webhook, error) {
+	var w model.Webhook
+	if err := r.db.First(&w, id).Error; err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *webhookRepo) Update(w *model.Webhook) error {
+	return r.db.Save(w).Error
+}
+
+func (r *webhookRepo) Delete(id uint) error {
+	return r.db.Delete(&model.Webhook{}, id).Error
+}
+
+func (r *webhookRepo) ListByUser(userID uint, p Pagination) ([]model.Webhook, error) {
+	var out []model.Webhook
+	err := r.db.Where("user_id = ?", userID).
+		Order("id").
+		Limit(p.Limit()).
+		Offset(p.Offset()).
+		Find(&out).Error
+	return out, err
+}
+
+func (r *webhookRepo) CountByUser(userID uint) (int, error) {
+	var count int64
+	err := r.db.Model(&model.Webhook{}).Where("user_id = ?", userID).Count(&count).Error
+	return int(count), err
+}
+
+func (r *webhookRepo) ActiveByUser(userID uint) ([]model.Webhook, error) {
+	var out []model.Webhook
+	err := r.db.Where("user_id = ? AND active = ?", userID, true).Find(&out).Error
+	return out, err
+}
+
+func (r *webhookRepo) CreateDelivery(d *model.WebhookDelivery) error {
+	return r.db.Create(d).Error
+}
+
+func (r *webhookRepo) FindDelivery(id uint) (*model.WebhookDelivery, error) {
+	var d model.WebhookDelivery
+	if err := r.db.First(&d, id).Error; err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *webhookRepo) UpdateDelivery(d *model.WebhookDelivery) error {
+	return r.db.Save(d).Error
+}
+
+func (r *webhookRepo) ListDeliveries(webhookID uint, p Pagination) ([]model.WebhookDelivery, error) {
+	var out []model.WebhookDelivery
+	err := r.db.Where("webhook_id = ?", webhookID).
+		Order("id DESC").
+		Limit(p.Limit()).
+		Offset(p.Offset()).
+		Find(&out).Error
+	return out, err
+}
+
+func (r *webhookRepo) CountDeliveries(webhookID uint) (int, error) {
+	var count int64
+	err := r.db.Model(&model.WebhookDelivery{}).Where("webhook_id = ?", webhookID).Count(&count).Error
+	return int(count), err
+}
+
+func (r *webhookRepo) DueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	var out []model.WebhookDelivery
+	err := r.db.Where("status = ? AND next_attempt_at <= ?", model.DeliveryPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&out).Error
+	return out, err
+}
+
+func (r *webhookRepo) ClaimDelivery(id uint, prev, until time.Time) (bool, error) {
+	res := r.db.Model(&model.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", id, model.DeliveryPending, prev).
+		Update("next_attempt_at", until)
+	return res.RowsAffected == 1, res.Error
+}
@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+)
+
+// MinInterval is the shortest fixed interval a schedule may use.
+const MinInterval = time.Minute
+
+// ErrInvalidSchedule is returned for schedules that set neither or both of cron and interval.
+var ErrInvalidSchedule = errors.New("schedule needs exactly one of cron or interval_seconds")
+
+// Starter queues a URL for analysis; service.URLService satisfies it.
+type Starter interface {
+	Start(id uint) error
+}
+
+// Validate checks that s describes a usable schedule.
+func Validate(s *model.Schedule) error {
+	switch {
+	case s.CronExpr != "" && s.IntervalSeconds != 0, s.CronExpr == "" && s.IntervalSeconds == 0:
+		return ErrInvalidSchedule
+	case s.CronExpr != "":
+		if _, err := cron.ParseStandard(s.CronExpr); err != nil {
+			return fmt.Errorf("invalid cron expression: %w", err)
+		}
+	case time.Duration(s.IntervalSeconds)*time.Second < MinInterval:
+		return fmt.Errorf("interval_seconds must be at least %d", int(MinInterval.Seconds()))
+	}
+	return nil
+}
+
+// NextRun returns the first run time of s strictly after from.
+func NextRun(s *model.Schedule, from time.Time) (time.Time, error) {
+	if err := Validate(s); err != nil {
+		return time.Time{}, err
+	}
+	if s.CronExpr != "" {
+		sched, _ := cron.ParseStandard(s.CronExpr)
+		return sched.Next(from), nil
+	}
+	return from.Add(time.Duration(s.IntervalSeconds) * time.Second), nil
+}
+
+// Scheduler periodically enqueues URLs whose schedule is due.
+type Scheduler struct {
+	repo    repository.ScheduleRepository
+	starter Starter
+	tick    time.Duration
+	batch   int
+}
+
+// New creates a scheduler that checks for due schedules every tick.
+func New(repo repository.ScheduleRepository, starter Starter, tick time.Duration) *Scheduler {
+	if tick <= 0 {
+		tick = 30 * time.Second
+	}
+	return &Scheduler{
+		repo:    repo,
+		starter: starter,
+		tick:    tick,
+		batch:   100,
+	}
+}
+
+// Start runs the scheduler loop until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	t := time.NewTicker(s.tick)
+	defer t.Stop()
+	for {
+		s.RunDue()
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// RunDue enqueues every schedule that is due now and advances it to its next run.
+// It returns the number of URLs queued.
+func (s *Scheduler) RunDue() int {
+	now := time.Now()
+	due, err := s.repo.Due(now, s.batch)
+	if err != nil {
+		log.Printf("[scheduler] load due schedules: %v", err)
+		return 0
+	}
+
+	queued := 0
+	for i := range due {
+		sch := &due[i]
+		next, err := NextRun(sch, now)
+		if err != nil {
+			log.Printf("[scheduler] url=%d – %v", sch.URLID, err)
+			continue
+		}
+		// Advancing first makes the run ours; another instance that loaded the same row loses the race.
+		ok, err := s.repo.Advance(sch.ID, *sch.NextRunAt, now, next)
+		if err != nil {
+			log.Printf("[scheduler] url=%d – advance: %v", sch.URLID, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if err := s.starter.Start(sch.URLID); err != nil {
+			log.Printf("[scheduler] url=%d – start: %v", sch.URLID, err)
+			continue
+		}
+		queued++
+	}
+	return queued
+}
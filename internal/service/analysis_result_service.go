@@ -1,6 +1,11 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
 	"github.com/fuzumoe/urlinsight-backend/internal/model"
 	"github.com/fuzumoe/urlinsight-backend/internal/repository"
 )
@@ -12,8 +17,27 @@ type AnalysisService interface {
 
 	// List returns paginated snapshots for a URL, newest first.
 	List(urlID uint, p repository.Pagination) ([]*model.AnalysisResultDTO, error)
+
+	// History returns a page of a user's URL's snapshots, newest first, with pagination metadata.
+	History(userID, urlID uint, p repository.Pagination) (*model.PaginatedResponse[model.AnalysisResultDTO], error)
+
+	// Diff compares two snapshots of a user's URL; with both IDs zero it compares the root
+	// pages of the two latest runs.
+	Diff(userID, urlID, fromID, toID uint) (*model.SnapshotDiffDTO, error)
+
+	// ExpiringCertificates lists the user's URLs whose certificate expires within days (or already has).
+	ExpiringCertificates(userID uint, days int) ([]model.ExpiringCertificateDTO, error)
 }
 
+var (
+	// ErrNotEnoughSnapshots is returned when a default diff is requested for a URL with fewer than two snapshots.
+	ErrNotEnoughSnapshots = errors.New("at least two snapshots are needed for a diff")
+	// ErrSnapshotPair is returned when only one of the two snapshot IDs is given.
+	ErrSnapshotPair = errors.New("give both from and to, or neither")
+	// ErrInvalidDays is returned for a negative expiry window.
+	ErrInvalidDays = errors.New("days must not be negative")
+)
+
 type analysisService struct {
 	repo repository.AnalysisResultRepository
 }
@@ -40,3 +64,95 @@ func (s *analysisService) List(urlID uint, p repository.Pagination) ([]*model.An
 	}
 	return dtos, nil
 }
+
+func (s *analysisService) History(userID, urlID uint, p repository.Pagination) (*model.PaginatedResponse[model.AnalysisResultDTO], error) {
+	if _, err := s.repo.FindURL(userID, urlID); err != nil {
+		return nil, err
+	}
+	results, err := s.repo.ListByURL(urlID, p)
+	if err != nil {
+		return nil, err
+	}
+
+	totalCount, err := s.repo.CountByURL(urlID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]model.AnalysisResultDTO, len(results))
+	for i, r := range results {
+		dtos[i] = *r.ToDTO()
+	}
+
+	return &model.PaginatedResponse[model.AnalysisResultDTO]{
+		Data:       dtos,
+		Pagination: paginationMeta(p, totalCount),
+	}, nil
+}
+
+func (s *analysisService) Diff(userID, urlID, fromID, toID uint) (*model.SnapshotDiffDTO, error) {
+	if _, err := s.repo.FindURL(userID, urlID); err != nil {
+		return nil, err
+	}
+	from, to, err := s.snapshotPair(urlID, fromID, toID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromLinks, err := s.repo.Links(from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot links: %w", err)
+	}
+	toLinks, err := s.repo.Links(to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot links: %w", err)
+	}
+	return model.DiffSnapshots(from, to, fromLinks, toLinks), nil
+}
+
+// snapshotPair resolves the two snapshots to compare, falling back to the root pages of
+// the latest two runs.
+func (s *analysisService) snapshotPair(urlID, fromID, toID uint) (*model.AnalysisResult, *model.AnalysisResult, error) {
+	switch {
+	case fromID == 0 && toID == 0:
+		latest, err := s.repo.Latest(urlID, 2)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(latest) < 2 {
+			return nil, nil, ErrNotEnoughSnapshots
+		}
+		fromID, toID = latest[1].ID, latest[0].ID
+	case fromID == 0 || toID == 0:
+		return nil, nil, ErrSnapshotPair
+	}
+
+	from, err := s.repo.FindByURL(urlID, fromID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("snapshot %d: %w", fromID, err)
+	}
+	to, err := s.repo.FindByURL(urlID, toID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("snapshot %d: %w", toID, err)
+	}
+	return from, to, nil
+}
+
+func (s *analysisService) ExpiringCertificates(userID uint, days int) ([]model.ExpiringCertificateDTO, error) {
+	if days < 0 {
+		return nil, ErrInvalidDays
+	}
+	now := time.Now()
+	certs, err := s.repo.ExpiringCertificates(userID, now.AddDate(0, 0, days))
+	if err != nil {
+		return nil, err
+	}
+	if certs == nil {
+		certs = []model.ExpiringCertificateDTO{}
+	}
+	for i := range certs {
+		// Round towards negative infinity so an expired certificate never reads as 0 days left.
+		certs[i].DaysLeft = int(math.Floor(certs[i].ExpiresAt.Sub(now).Hours() / 24))
+	}
+	return certs, nil
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/importer"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+)
+
+// SitemapFetcher lists the page URLs of a sitemap; importer.SitemapFetcher satisfies it.
+type SitemapFetcher interface {
+	Fetch(ctx context.Context, sitemapURL string, max int) ([]string, error)
+}
+
+// BulkService imports many URLs at once and applies start, stop and delete to many URLs.
+// Only the user's own URLs are touched.
+type BulkService interface {
+	// Import creates a URL for every entry and every page of the sitemap, if one is given,
+	// reporting each row's outcome. A row that fails does not stop the others.
+	Import(ctx context.Context, userID uint, entries []importer.Entry, sitemapURL string, opts model.BulkImportOptions) (*model.BulkImportResultDTO, error)
+	Start(userID uint, req *model.BulkActionRequestDTO) (*model.BulkActionResultDTO, error)
+	Stop(userID uint, req *model.BulkActionRequestDTO) (*model.BulkActionResultDTO, error)
+	Delete(userID uint, req *model.BulkActionRequestDTO) (*model.BulkActionResultDTO, error)
+}
+
+type bulkService struct {
+	repo     repository.URLRepository
+	urls     URLService
+	sitemaps SitemapFetcher
+}
+
+// NewBulkService constructs a BulkService. Rows are created and acted on through urls, so
+// they get the same validation and crawler handling as single requests.
+func NewBulkService(repo repository.URLRepository, urls URLService, sitemaps SitemapFetcher) BulkService {
+	return &bulkService{repo: repo, urls: urls, sitemaps: sitemaps}
+}
+
+func (s *bulkService) Import(ctx context.Context, userID uint, entries []importer.Entry, sitemapURL string, opts model.BulkImportOptions) (*model.BulkImportResultDTO, error) {
+	mode := opts.CrawlMode
+	if mode == "" {
+		mode = model.CrawlModePage
+	}
+	if err := validateModules(mode, opts.Modules); err != nil {
+		return nil, err
+	}
+	if len(entries) > model.MaxBulkItems {
+		return nil, fmt.Errorf("at most %d URLs can be imported at once", model.MaxBulkItems)
+	}
+	if sitemapURL != "" {
+		pages, err := s.sitemaps.Fetch(ctx, sitemapURL, model.MaxBulkItems-len(entries))
+		if err != nil {
+			return nil, err
+		}
+		next := 1
+		if len(entries) > 0 {
+			next = entries[len(entries)-1].Row + 1
+		}
+		for i, p := range pages {
+			entries = append(entries, importer.Entry{Row: next + i, Value: p})
+		}
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("no URLs to import")
+	}
+
+	res := &model.BulkImportResultDTO{Rows: make([]model.BulkRowResultDTO, 0, len(entries))}
+	seen := make(map[string]int, len(entries))
+	for _, e := range entries {
+		row := model.BulkRowResultDTO{Row: e.Row, Input: e.Value}
+		s.importRow(userID, &row, seen, opts)
+		if row.ID == 0 {
+			res.Failed++
+		} else {
+			res.Created++
+			if row.Error == "" && opts.Start {
+				res.Started++
+			}
+		}
+		res.Rows = append(res.Rows, row)
+	}
+	return res, nil
+}
+
+// importRow normalizes, creates and optionally starts one row, recording the outcome in row.
+// seen maps the normalized URLs created so far to their rows.
+func (s *bulkService) importRow(userID uint, row *model.BulkRowResultDTO, seen map[string]int, opts model.BulkImportOptions) {
+	u, err := importer.Normalize(row.Input)
+	if err != nil {
+		row.Error = err.Error()
+		return
+	}
+	row.URL = u
+	if first, ok := seen[u]; ok {
+		row.Error = fmt.Sprintf("duplicate of row %d", first)
+		return
+	}
+	id, err := s.urls.Create(&model.CreateURLInputDTO{
+		UserID:      userID,
+		OriginalURL: u,
+		CrawlMode:   opts.CrawlMode,
+		MaxDepth:    opts.MaxDepth,
+		MaxPages:    opts.MaxPages,
+		Modules:     opts.Modules,
+	})
+	if err != nil {
+		row.Error = err.Error()
+		return
+	}
+	seen[u] = row.Row
+	row.ID = id
+	if opts.Start {
+		if err := s.urls.Start(id); err != nil {
+			row.Error = fmt.Sprintf("created but not started: %v", err)
+		}
+	}
+}
+
+func (s *bulkService) Start(userID uint, req *model.BulkActionRequestDTO) (*model.BulkActionResultDTO, error) {
+	return s.apply(userID, req, s.urls.Start)
+}
+
+func (s *bulkService) Stop(userID uint, req *model.BulkActionRequestDTO) (*model.BulkActionResultDTO, error) {
+	return s.apply(userID, req, func(id uint) error { return s.urls.Stop(id, req.KeepPartial) })
+}
+
+func (s *bulkService) Delete(userID uint, req *model.BulkActionRequestDTO) (*model.BulkActionResultDTO, error) {
+	return s.apply(userID, req, s.urls.Delete)
+}
+
+// apply runs fn on every URL the request selects. Requested IDs that are not the user's
+// are reported as not found.
+func (s *bulkService) apply(userID uint, req *model.BulkActionRequestDTO, fn func(id uint) error) (*model.BulkActionResultDTO, error) {
+	f, err := bulkFilter(req)
+	if err != nil {
+		return nil, err
+	}
+	matched, err := s.repo.IDsByUser(userID, f, model.MaxBulkItems+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(matched) > model.MaxBulkItems {
+		return nil, fmt.Errorf("the filter matches more than %d URLs; narrow it down", model.MaxBulkItems)
+	}
+
+	targets := matched
+	if len(f.IDs) > 0 {
+		// Report in the order asked for, including the IDs that matched nothing.
+		targets = f.IDs
+	}
+	owned := make(map[uint]bool, len(matched))
+	for _, id := range matched {
+		owned[id] = true
+	}
+
+	res := &model.BulkActionResultDTO{Matched: len(matched), Results: make([]model.BulkItemResultDTO, 0, len(targets))}
+	for _, id := range targets {
+		item := model.BulkItemResultDTO{ID: id}
+		if !owned[id] {
+			item.Error = "not found"
+		} else if err := fn(id); err != nil {
+			item.Error = err.Error()
+		}
+		if item.Error == "" {
+			res.Succeeded++
+		} else {
+			res.Failed++
+		}
+		res.Results = append(res.Results, item)
+	}
+	return res, nil
+}
+
+// bulkFilter turns a bulk action request into a URL filter. Exactly one of IDs and filter
+// must be given, and a filter needs at least one criterion so nothing is selected by accident.
+func bulkFilter(req *model.BulkActionRequestDTO) (model.URLFilter, error) {
+	switch {
+	case len(req.IDs) > 0 && req.Filter != nil:
+		return model.URLFilter{}, errors.New("give either ids or filter, not both")
+	case len(req.IDs) > 0:
+		if len(req.IDs) > model.MaxBulkItems {
+			return model.URLFilter{}, fmt.Errorf("at most %d ids are allowed", model.MaxBulkItems)
+		}
+		ids := make([]uint, 0, len(req.IDs))
+		seen := make(map[uint]bool, len(req.IDs))
+		for _, id := range req.IDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		return model.URLFilter{IDs: ids}, nil
+	case req.Filter != nil:
+		f := model.URLFilter{Status: req.Filter.Status, CrawlMode: req.Filter.CrawlMode, Query: req.Filter.Query}
+		if f.Status == "" && f.CrawlMode == "" && f.Query == "" {
+			return model.URLFilter{}, errors.New("filter needs at least one of status, crawl_mode or q")
+		}
+		return f, nil
+	}
+	return model.URLFilter{}, errors.New("ids or filter is required")
+}
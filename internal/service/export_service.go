@@ -0,0 +1,147 @@
+package service
+
+import (
+	"github.com/fuzumoe/urlinsight-backend/internal/export"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+)
+
+// ExportService writes URLs, snapshots and links as tables, row by row. Each export checks
+// what it needs before writing anything, so a not-found error leaves w untouched; w is
+// closed when the export succeeds.
+type ExportService interface {
+	// URLs exports the user's URLs with the metrics of their latest snapshot.
+	URLs(userID uint, w export.Writer) error
+	// Snapshots exports every snapshot of one of the user's URLs, oldest first.
+	Snapshots(userID, urlID uint, w export.Writer) error
+	// Links exports the links of one run of one of the user's URLs: the run containing
+	// snapshotID, or the latest run when snapshotID is zero. linkError filters as in
+	// the results endpoint.
+	Links(userID, urlID, snapshotID uint, linkError string, w export.Writer) error
+}
+
+type exportService struct {
+	repo repository.ExportRepository
+}
+
+func NewExportService(repo repository.ExportRepository) ExportService {
+	return &exportService{repo: repo}
+}
+
+var urlExportColumns = []string{
+	"id", "original_url", "status", "crawl_mode", "created_at",
+	"snapshot_id", "analyzed_at", "status_code", "health", "title", "html_version",
+	"internal_link_count", "external_link_count", "broken_link_count", "error_link_count",
+	"has_login_form", "ttfb_ms", "total_ms", "cert_expires_at",
+}
+
+var snapshotExportColumns = []string{
+	"id", "url_id", "page_url", "depth", "status_code", "content_type", "charset", "body_truncated",
+	"health", "html_version", "title",
+	"h1_count", "h2_count", "h3_count", "h4_count", "h5_count", "h6_count", "has_login_form",
+	"internal_link_count", "external_link_count", "broken_link_count", "error_link_count", "links_incomplete",
+	"final_url", "redirect_hops", "cert_expires_at",
+	"dns_ms", "connect_ms", "tls_ms", "ttfb_ms", "download_ms", "total_ms", "response_bytes",
+	"created_at",
+}
+
+var linkExportColumns = []string{
+	"id", "snapshot_id", "href", "is_external", "status_code", "error_category", "error_message",
+	"final_url", "redirect_hops", "ttfb_ms", "created_at",
+}
+
+func (s *exportService) URLs(userID uint, w export.Writer) error {
+	if err := w.Header(urlExportColumns); err != nil {
+		return err
+	}
+	err := s.repo.EachURL(userID, func(u *model.URL, latest *model.AnalysisResult) error {
+		return w.Row(urlExportRow(u, latest))
+	})
+	if err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func (s *exportService) Snapshots(userID, urlID uint, w export.Writer) error {
+	if _, err := s.repo.FindURL(userID, urlID); err != nil {
+		return err
+	}
+	if err := w.Header(snapshotExportColumns); err != nil {
+		return err
+	}
+	err := s.repo.EachSnapshot(urlID, func(res *model.AnalysisResult) error {
+		return w.Row(snapshotExportRow(res))
+	})
+	if err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func (s *exportService) Links(userID, urlID, snapshotID uint, linkError string, w export.Writer) error {
+	if _, err := s.repo.FindURL(userID, urlID); err != nil {
+		return err
+	}
+	start, end, err := s.repo.RunBounds(urlID, snapshotID)
+	if err != nil {
+		return err
+	}
+	if err := w.Header(linkExportColumns); err != nil {
+		return err
+	}
+	err = s.repo.EachLink(urlID, start, end, linkError, func(l *model.Link) error {
+		return w.Row(linkExportRow(l))
+	})
+	if err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// urlExportRow lines a URL and its latest snapshot up with urlExportColumns; the snapshot
+// columns stay empty for a URL never analyzed.
+func urlExportRow(u *model.URL, s *model.AnalysisResult) []any {
+	row := []any{u.ID, u.OriginalURL, u.Status, u.CrawlMode, u.CreatedAt}
+	if s == nil {
+		return append(row, make([]any, len(urlExportColumns)-len(row))...)
+	}
+	return append(row,
+		s.ID, s.CreatedAt, s.StatusCode, s.Health, s.Title, s.HTMLVersion,
+		s.InternalLinkCount, s.ExternalLinkCount, s.BrokenLinkCount, s.ErrorLinkCount,
+		s.HasLoginForm, s.Timing.TTFBMs, s.Timing.TotalMs, s.CertExpiresAt,
+	)
+}
+
+func snapshotExportRow(s *model.AnalysisResult) []any {
+	finalURL, hops := redirectSummary(s.Redirects)
+	return []any{
+		s.ID, s.URLID, s.PageURL, s.Depth, s.StatusCode, s.ContentType, s.Charset, s.BodyTruncated,
+		s.Health, s.HTMLVersion, s.Title,
+		s.H1Count, s.H2Count, s.H3Count, s.H4Count, s.H5Count, s.H6Count, s.HasLoginForm,
+		s.InternalLinkCount, s.ExternalLinkCount, s.BrokenLinkCount, s.ErrorLinkCount, s.LinksIncomplete,
+		finalURL, hops, s.CertExpiresAt,
+		s.Timing.DNSMs, s.Timing.ConnectMs, s.Timing.TLSMs, s.Timing.TTFBMs, s.Timing.DownloadMs, s.Timing.TotalMs, s.Timing.ResponseBytes,
+		s.CreatedAt,
+	}
+}
+
+func linkExportRow(l *model.Link) []any {
+	var snapshotID any
+	if l.AnalysisResultID != nil {
+		snapshotID = *l.AnalysisResultID
+	}
+	finalURL, hops := redirectSummary(l.Redirects)
+	return []any{
+		l.ID, snapshotID, l.Href, l.IsExternal, l.StatusCode, l.ErrorCategory, l.ErrorMessage,
+		finalURL, hops, l.TTFBMs, l.CreatedAt,
+	}
+}
+
+// redirectSummary flattens a redirect chain to where it ended and how many hops it took.
+func redirectSummary(c *model.RedirectChain) (finalURL string, hops int) {
+	if c == nil {
+		return "", 0
+	}
+	return c.FinalURL, len(c.Hops)
+}
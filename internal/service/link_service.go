@@ -35,12 +35,6 @@ func (s *linkService) ListByURL(urlID uint, p repository.Pagination) (*model.Pag
 		return nil, err
 	}
 
-	// Calculate total pages
-	totalPages := totalCount / p.PageSize
-	if totalCount%p.PageSize > 0 {
-		totalPages++
-	}
-
 	// Convert models to DTOs
 	dtos := make([]model.LinkDTO, len(links))
 	for i, link := range links {
@@ -48,13 +42,8 @@ func (s *linkService) ListByURL(urlID uint, p repository.Pagination) (*model.Pag
 	}
 
 	return &model.PaginatedResponse[model.LinkDTO]{
-		Data: dtos,
-		Pagination: model.PaginationMetaDTO{
-			Page:       p.Page,
-			PageSize:   p.PageSize,
-			TotalItems: totalCount,
-			TotalPages: totalPages,
-		},
+		Data:       dtos,
+		Pagination: paginationMeta(p, totalCount),
 	}, nil
 }
 
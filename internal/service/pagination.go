@@ -0,0 +1,21 @@
+package service
+
+import (
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+)
+
+// paginationMeta describes page p of totalCount items.
+func paginationMeta(p repository.Pagination, totalCount int) model.PaginationMetaDTO {
+	pageSize := p.Limit()
+	totalPages := totalCount / pageSize
+	if totalCount%pageSize > 0 {
+		totalPages++
+	}
+	return model.PaginationMetaDTO{
+		Page:       p.Page,
+		PageSize:   p.PageSize,
+		TotalItems: totalCount,
+		TotalPages: totalPages,
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+	"github.com/fuzumoe/urlinsight-backend/internal/scheduler"
+)
+
+// ScheduleService manages recurring re-analysis of a user's URLs. URLs and schedules of
+// other users are reported as not found.
+type ScheduleService interface {
+	Set(userID, urlID uint, in *model.ScheduleInputDTO) (*model.ScheduleDTO, error)
+	Get(userID, urlID uint) (*model.ScheduleDTO, error)
+	Pause(userID, urlID uint) (*model.ScheduleDTO, error)
+	Resume(userID, urlID uint) (*model.ScheduleDTO, error)
+	Delete(userID, urlID uint) error
+	List(userID uint, p repository.Pagination) (*model.PaginatedResponse[model.ScheduleDTO], error)
+}
+
+type scheduleService struct {
+	repo    repository.ScheduleRepository
+	urlRepo repository.URLRepository
+}
+
+// NewScheduleService constructs a ScheduleService.
+func NewScheduleService(repo repository.ScheduleRepository, urlRepo repository.URLRepository) ScheduleService {
+	return &scheduleService{repo: repo, urlRepo: urlRepo}
+}
+
+// Set creates or replaces a URL's schedule and computes its next run.
+func (s *scheduleService) Set(userID, urlID uint, in *model.ScheduleInputDTO) (*model.ScheduleDTO, error) {
+	u, err := s.urlRepo.FindByID(urlID)
+	if err == nil && u.UserID != userID {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot schedule url: %w", err)
+	}
+
+	sch := &model.Schedule{
+		URLID:           urlID,
+		CronExpr:        in.Cron,
+		IntervalSeconds: in.IntervalSeconds,
+		Paused:          in.Paused,
+	}
+	if err := s.plan(sch); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(sch); err != nil {
+		return nil, err
+	}
+	return sch.ToDTO(), nil
+}
+
+func (s *scheduleService) Get(userID, urlID uint) (*model.ScheduleDTO, error) {
+	sch, err := s.repo.FindByURL(userID, urlID)
+	if err != nil {
+		return nil, err
+	}
+	return sch.ToDTO(), nil
+}
+
+// Pause keeps the schedule but stops it from firing.
+func (s *scheduleService) Pause(userID, urlID uint) (*model.ScheduleDTO, error) {
+	return s.setPaused(userID, urlID, true)
+}
+
+// Resume re-activates a paused schedule, counting the next run from now.
+func (s *scheduleService) Resume(userID, urlID uint) (*model.ScheduleDTO, error) {
+	return s.setPaused(userID, urlID, false)
+}
+
+func (s *scheduleService) setPaused(userID, urlID uint, paused bool) (*model.ScheduleDTO, error) {
+	sch, err := s.repo.FindByURL(userID, urlID)
+	if err != nil {
+		return nil, err
+	}
+	sch.Paused = paused
+	if err := s.plan(sch); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(sch); err != nil {
+		return nil, err
+	}
+	return sch.ToDTO(), nil
+}
+
+func (s *scheduleService) Delete(userID, urlID uint) error {
+	if _, err := s.repo.FindByURL(userID, urlID); err != nil {
+		return err
+	}
+	return s.repo.Delete(urlID)
+}
+
+func (s *scheduleService) List(userID uint, p repository.Pagination) (*model.PaginatedResponse[model.ScheduleDTO], error) {
+	items, err := s.repo.ListByUser(userID, p)
+	if err != nil {
+		return nil, err
+	}
+
+	totalCount, err := s.repo.CountByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if items == nil {
+		items = []model.ScheduleDTO{}
+	}
+	return &model.PaginatedResponse[model.ScheduleDTO]{
+		Data:       items,
+		Pagination: paginationMeta(p, totalCount),
+	}, nil
+}
+
+// plan validates the schedule and sets its next run; paused schedules have none.
+func (s *scheduleService) plan(sch *model.Schedule) error {
+	if err := scheduler.Validate(sch); err != nil {
+		return err
+	}
+	if sch.Paused {
+		sch.NextRunAt = nil
+		return nil
+	}
+	next, err := scheduler.NextRun(sch, time.Now())
+	if err != nil {
+		return err
+	}
+	sch.NextRunAt = &next
+	return nil
+}
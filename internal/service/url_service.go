@@ -3,7 +3,9 @@ package service
 import (
 	"errors"
 	"fmt"
+	"slices"
 
+	"github.com/fuzumoe/urlinsight-backend/internal/analyzer"
 	"github.com/fuzumoe/urlinsight-backend/internal/crawler"
 	"github.com/fuzumoe/urlinsight-backend/internal/model"
 	"github.com/fuzumoe/urlinsight-backend/internal/repository"
@@ -13,13 +15,16 @@ import (
 type URLService interface {
 	Create(input *model.CreateURLInputDTO) (uint, error)
 	Get(id uint) (*model.URLDTO, error)
+	// Status returns the URL's status alone, for callers polling it.
+	Status(id uint) (string, error)
 	List(userID uint, p repository.Pagination) (*model.PaginatedResponse[model.URLDTO], error)
 	Update(id uint, input *model.UpdateURLInput) error
 	Delete(id uint) error
 	Start(id uint) error
-	Stop(id uint) error
+	Stop(id uint, keepPartial bool) error
 	Results(id uint) (*model.URLDTO, error)
-	ResultsWithDetails(id uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error)
+	ResultsWithDetails(id, snapshotID uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error)
+	SiteSummary(id, snapshotID uint) (*model.SiteSummary, error)
 }
 
 type urlService struct {
@@ -45,6 +50,36 @@ func (s *urlService) Update(id uint, in *model.UpdateURLInput) error {
 			return errors.New("invalid status value")
 		}
 	}
+	if in.CrawlMode != "" {
+		u.CrawlMode = in.CrawlMode
+	}
+	if in.MaxDepth != nil {
+		u.MaxDepth = *in.MaxDepth
+	}
+	if in.MaxPages != nil {
+		u.MaxPages = *in.MaxPages
+	}
+	if err := model.ValidateRequestOptions(in.RequestHeaders, in.RequestCookies); err != nil {
+		return err
+	}
+	if in.RequestHeaders != nil {
+		u.RequestHeaders = in.RequestHeaders
+	}
+	if in.RequestCookies != nil {
+		u.RequestCookies = in.RequestCookies
+	}
+	if in.Modules != nil {
+		u.Modules = in.Modules
+	}
+	if in.Assertions != nil {
+		if err := analyzer.ValidateAssertions(in.Assertions); err != nil {
+			return err
+		}
+		u.Assertions = in.Assertions
+	}
+	if err := validateModules(u.CrawlMode, u.Modules); err != nil {
+		return err
+	}
 	return s.repo.Update(u)
 }
 
@@ -64,19 +99,27 @@ func (s *urlService) Start(id uint) error {
 	if err := s.repo.UpdateStatus(id, model.StatusQueued); err != nil {
 		return err
 	}
-	s.crawlers.Enqueue(id)
+	if err := s.crawlers.Enqueue(id); err != nil {
+		return fmt.Errorf("cannot queue crawling: %w", err)
+	}
 	return nil
 }
 
-// Stop: flips to "error" status since "stopped" is not in the database schema
-func (s *urlService) Stop(id uint) error {
+// Stop: visible to PATCH /urls/:id/stop
+// Persists "stopped" and cancels the running analysis; partial results are
+// discarded unless keepPartial is set.
+func (s *urlService) Stop(id uint, keepPartial bool) error {
 	// First check if the URL exists
 	_, err := s.repo.FindByID(id)
 	if err != nil {
 		return fmt.Errorf("cannot stop crawling: %w", err)
 	}
 
-	return s.repo.UpdateStatus(id, model.StatusError)
+	if err := s.repo.UpdateStatus(id, model.StatusStopped); err != nil {
+		return err
+	}
+	s.crawlers.Cancel(id, keepPartial)
+	return nil
 }
 
 // Results loads URL with analysis + links eager-loaded via simple preload
@@ -88,9 +131,10 @@ func (s *urlService) Results(id uint) (*model.URLDTO, error) {
 	return url.ToDTO(), nil
 }
 
-// ResultsWithDetails provides detailed URL analysis data using the optimized query
-func (s *urlService) ResultsWithDetails(id uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error) {
-	url, analysisResults, links, err := s.repo.ResultsWithDetails(id)
+// ResultsWithDetails provides one analysis run of a URL (the latest when snapshotID is zero)
+// using the optimized query
+func (s *urlService) ResultsWithDetails(id, snapshotID uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error) {
+	url, analysisResults, links, err := s.repo.ResultsWithDetails(id, snapshotID)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to get detailed URL results: %w", err)
 	}
@@ -98,14 +142,45 @@ func (s *urlService) ResultsWithDetails(id uint) (*model.URL, []*model.AnalysisR
 	return url, analysisResults, links, nil
 }
 
+// SiteSummary returns the aggregated summary of one site crawl (the latest when snapshotID
+// is zero).
+func (s *urlService) SiteSummary(id, snapshotID uint) (*model.SiteSummary, error) {
+	summary, err := s.repo.SiteSummary(id, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get site summary: %w", err)
+	}
+	return summary, nil
+}
+
 func (s *urlService) Create(input *model.CreateURLInputDTO) (uint, error) {
+	if err := model.ValidateRequestOptions(input.RequestHeaders, input.RequestCookies); err != nil {
+		return 0, err
+	}
 	u := model.URLFromCreateInput(input)
+	if err := validateModules(u.CrawlMode, u.Modules); err != nil {
+		return 0, err
+	}
+	if err := analyzer.ValidateAssertions(u.Assertions); err != nil {
+		return 0, err
+	}
 	if err := s.repo.Create(u); err != nil {
 		return 0, err
 	}
 	return u.ID, nil
 }
 
+// validateModules checks a URL's module selection. Site crawls discover pages through the
+// links module, so a selection for one must include it.
+func validateModules(crawlMode string, modules []string) error {
+	if err := analyzer.ValidateModules(modules); err != nil {
+		return err
+	}
+	if crawlMode == model.CrawlModeSite && len(modules) > 0 && !slices.Contains(modules, analyzer.ModuleLinks) {
+		return fmt.Errorf("site crawls need the %q module", analyzer.ModuleLinks)
+	}
+	return nil
+}
+
 func (s *urlService) Get(id uint) (*model.URLDTO, error) {
 	u, err := s.repo.FindByID(id)
 	if err != nil {
@@ -113,6 +188,11 @@ func (s *urlService) Get(id uint) (*model.URLDTO, error) {
 	}
 	return u.ToDTO(), nil
 }
+
+func (s *urlService) Status(id uint) (string, error) {
+	return s.repo.Status(id)
+}
+
 func mapURLToDTO(url *model.URL) *model.URLDTO {
 	return url.ToDTO()
 }
@@ -129,12 +209,6 @@ func (s *urlService) List(userID uint, p repository.Pagination) (*model.Paginate
 		return nil, err
 	}
 
-	// Calculate total pages
-	totalPages := totalCount / p.PageSize
-	if totalCount%p.PageSize > 0 {
-		totalPages++
-	}
-
 	// Convert models to DTOs
 	dtos := make([]model.URLDTO, len(urls))
 	for i, url := range urls {
@@ -142,13 +216,8 @@ func (s *urlService) List(userID uint, p repository.Pagination) (*model.Paginate
 	}
 
 	return &model.PaginatedResponse[model.URLDTO]{
-		Data: dtos,
-		Pagination: model.PaginationMetaDTO{
-			Page:       p.Page,
-			PageSize:   p.PageSize,
-			TotalItems: totalCount,
-			TotalPages: totalPages,
-		},
+		Data:       dtos,
+		Pagination: paginationMeta(p, totalCount),
 	}, nil
 }
 
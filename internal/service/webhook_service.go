@@ -0,0 +1,189 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+)
+
+// Redeliverer queues a past webhook delivery again; webhook.Dispatcher satisfies it.
+type Redeliverer interface {
+	Redeliver(d *model.WebhookDelivery) (*model.WebhookDelivery, error)
+}
+
+// WebhookService manages a user's webhooks and their delivery log. Webhooks of other users
+// are reported as not found.
+type WebhookService interface {
+	Create(userID uint, in *model.WebhookInputDTO) (*model.WebhookDTO, error)
+	Get(userID, id uint) (*model.WebhookDTO, error)
+	Update(userID, id uint, in *model.WebhookInputDTO) (*model.WebhookDTO, error)
+	Delete(userID, id uint) error
+	List(userID uint, p repository.Pagination) (*model.PaginatedResponse[model.WebhookDTO], error)
+	Deliveries(userID, id uint, p repository.Pagination) (*model.PaginatedResponse[model.WebhookDelivery], error)
+	Redeliver(userID, id, deliveryID uint) (*model.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	repo       repository.WebhookRepository
+	dispatcher Redeliverer
+}
+
+// NewWebhookService constructs a WebhookService.
+func NewWebhookService(repo repository.WebhookRepository, d Redeliverer) WebhookService {
+	return &webhookService{repo: repo, dispatcher: d}
+}
+
+// Create registers a webhook with a fresh signing secret, which is returned only here.
+func (s *webhookService) Create(userID uint, in *model.WebhookInputDTO) (*model.WebhookDTO, error) {
+	if err := validateWebhookEvents(in.Events); err != nil {
+		return nil, err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	w := &model.Webhook{
+		UserID:    userID,
+		TargetURL: in.URL,
+		Secret:    secret,
+		Events:    in.Events,
+		Active:    in.Active == nil || *in.Active,
+	}
+	if err := s.repo.Create(w); err != nil {
+		return nil, err
+	}
+	dto := w.ToDTO()
+	dto.Secret = secret
+	return dto, nil
+}
+
+func (s *webhookService) Get(userID, id uint) (*model.WebhookDTO, error) {
+	w, err := s.find(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return w.ToDTO(), nil
+}
+
+// Update replaces the webhook's URL and events; Active is kept unless given.
+func (s *webhookService) Update(userID, id uint, in *model.WebhookInputDTO) (*model.WebhookDTO, error) {
+	w, err := s.find(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWebhookEvents(in.Events); err != nil {
+		return nil, err
+	}
+	w.TargetURL = in.URL
+	w.Events = in.Events
+	if in.Active != nil {
+		w.Active = *in.Active
+	}
+	if err := s.repo.Update(w); err != nil {
+		return nil, err
+	}
+	return w.ToDTO(), nil
+}
+
+func (s *webhookService) Delete(userID, id uint) error {
+	if _, err := s.find(userID, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+func (s *webhookService) List(userID uint, p repository.Pagination) (*model.PaginatedResponse[model.WebhookDTO], error) {
+	hooks, err := s.repo.ListByUser(userID, p)
+	if err != nil {
+		return nil, err
+	}
+	totalCount, err := s.repo.CountByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]model.WebhookDTO, len(hooks))
+	for i := range hooks {
+		dtos[i] = *hooks[i].ToDTO()
+	}
+	return &model.PaginatedResponse[model.WebhookDTO]{
+		Data:       dtos,
+		Pagination: paginationMeta(p, totalCount),
+	}, nil
+}
+
+// Deliveries returns the webhook's delivery log, newest first.
+func (s *webhookService) Deliveries(userID, id uint, p repository.Pagination) (*model.PaginatedResponse[model.WebhookDelivery], error) {
+	if _, err := s.find(userID, id); err != nil {
+		return nil, err
+	}
+	items, err := s.repo.ListDeliveries(id, p)
+	if err != nil {
+		return nil, err
+	}
+	totalCount, err := s.repo.CountDeliveries(id)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []model.WebhookDelivery{}
+	}
+	return &model.PaginatedResponse[model.WebhookDelivery]{
+		Data:       items,
+		Pagination: paginationMeta(p, totalCount),
+	}, nil
+}
+
+// Redeliver sends a past delivery of the webhook again, as a new delivery.
+func (s *webhookService) Redeliver(userID, id, deliveryID uint) (*model.WebhookDelivery, error) {
+	if _, err := s.find(userID, id); err != nil {
+		return nil, err
+	}
+	d, err := s.repo.FindDelivery(deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if d.WebhookID != id {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return s.dispatcher.Redeliver(d)
+}
+
+// find loads a webhook of the user.
+func (s *webhookService) find(userID, id uint) (*model.Webhook, error) {
+	w, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if w.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return w, nil
+}
+
+func validateWebhookEvents(events []string) error {
+	if len(events) == 0 {
+		return errors.New("at least one event is required")
+	}
+	for _, e := range events {
+		if !model.ValidWebhookEvent(e) {
+			return fmt.Errorf("unknown webhook event %q", e)
+		}
+	}
+	return nil
+}
+
+// newWebhookSecret returns a random 256-bit signing secret, hex encoded.
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
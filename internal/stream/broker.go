@@ -0,0 +1,67 @@
+package stream
+
+import (
+	"sync"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+// bufferSize is how many events a slow subscriber may fall behind before events are dropped.
+const bufferSize = 64
+
+// Broker fans analysis progress out to the clients watching each URL. It only sees the
+// analyses run by this process's crawler pool.
+type Broker struct {
+	mu   sync.Mutex
+	subs map[uint]map[chan model.ProgressEvent]struct{}
+}
+
+// NewBroker creates an empty broker.
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[uint]map[chan model.ProgressEvent]struct{})}
+}
+
+// Report passes e to every subscriber of its URL without blocking; a subscriber whose
+// buffer is full misses the event. It implements crawler.ProgressReporter.
+func (b *Broker) Report(e model.ProgressEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[e.URLID] {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Subscribe returns the events reported for the URL from now on, and a function that ends
+// the subscription and closes the channel.
+func (b *Broker) Subscribe(urlID uint) (<-chan model.ProgressEvent, func()) {
+	ch := make(chan model.ProgressEvent, bufferSize)
+	b.mu.Lock()
+	if b.subs[urlID] == nil {
+		b.subs[urlID] = make(map[chan model.ProgressEvent]struct{})
+	}
+	b.subs[urlID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs[urlID], ch)
+			if len(b.subs[urlID]) == 0 {
+				delete(b.subs, urlID)
+			}
+			close(ch)
+		})
+	}
+}
+
+// Subscribers returns how many clients are watching the URL.
+func (b *Broker) Subscribers(urlID uint) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs[urlID])
+}
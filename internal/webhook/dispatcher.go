@@ -0,0 +1,325 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+)
+
+// Headers sent with every delivery. The signature covers the timestamp and the body, so a
+// receiver can reject both forged and replayed payloads.
+const (
+	HeaderEvent     = "X-URLInsight-Event"
+	HeaderDelivery  = "X-URLInsight-Delivery"
+	HeaderTimestamp = "X-URLInsight-Timestamp"
+	HeaderSignature = "X-URLInsight-Signature"
+)
+
+const (
+	userAgent = "URLInsight-Webhook/1.0"
+	// maxBackoff caps the delay between two attempts.
+	maxBackoff = time.Hour
+	// maxErrorLen bounds the error text kept in the delivery log.
+	maxErrorLen = 1024
+	// parallelism is how many deliveries are attempted at once.
+	parallelism = 4
+)
+
+// Options tunes delivery; zero values select the defaults.
+type Options struct {
+	MaxAttempts  int               // attempts before a delivery fails for good; default 5
+	Timeout      time.Duration     // per attempt; default 10s
+	BaseDelay    time.Duration     // delay before the first retry, doubling after each; default 30s
+	PollInterval time.Duration     // how often due retries are looked for; default 5s
+	Transport    http.RoundTripper // nil selects http.DefaultTransport
+}
+
+// Dispatcher records analysis events as deliveries to the owner's webhooks and sends them,
+// retrying failures with exponential backoff. Deliveries live in the database, so pending
+// ones survive a restart and several instances can share the work.
+type Dispatcher struct {
+	repo   repository.WebhookRepository
+	client *http.Client
+	opts   Options
+	wake   chan struct{}
+}
+
+// New creates a dispatcher; call Start to begin sending.
+func New(repo repository.WebhookRepository, opts Options) *Dispatcher {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = 30 * time.Second
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 5 * time.Second
+	}
+	return &Dispatcher{
+		repo: repo,
+		client: &http.Client{
+			Timeout:   opts.Timeout,
+			Transport: opts.Transport,
+			// A redirect is answered like any other non-2xx status: the delivery failed.
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		opts: opts,
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// Sign returns the signature of a delivery: "sha256=" followed by the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the webhook's secret.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Events returns the webhook events an analysis outcome raises.
+func Events(e model.AnalysisEvent) []string {
+	switch e.Status {
+	case model.StatusDone:
+		if e.BrokenLinkCount > 0 {
+			return []string{model.EventAnalysisCompleted, model.EventBrokenLinksDetected}
+		}
+		return []string{model.EventAnalysisCompleted}
+	case model.StatusError:
+		return []string{model.EventAnalysisFailed}
+	case model.StatusStopped:
+		return []string{model.EventAnalysisStopped}
+	}
+	return nil
+}
+
+// AnalysisFinished queues a delivery for every active webhook of the URL's owner that is
+// subscribed to an event the outcome raises. It implements crawler.Notifier.
+func (d *Dispatcher) AnalysisFinished(e model.AnalysisEvent) {
+	events := Events(e)
+	if len(events) == 0 {
+		return
+	}
+	hooks, err := d.repo.ActiveByUser(e.UserID)
+	if err != nil {
+		log.Printf("[webhook] url=%d – load webhooks: %v", e.URLID, err)
+		return
+	}
+
+	queued := false
+	for _, event := range events {
+		body, err := json.Marshal(model.WebhookPayload{Event: event, CreatedAt: e.FinishedAt, Data: e})
+		if err != nil {
+			log.Printf("[webhook] url=%d – encode %s: %v", e.URLID, event, err)
+			continue
+		}
+		for i := range hooks {
+			if !hooks[i].Subscribed(event) {
+				continue
+			}
+			now := time.Now()
+			del := &model.WebhookDelivery{
+				WebhookID:     hooks[i].ID,
+				Event:         event,
+				Payload:       string(body),
+				Status:        model.DeliveryPending,
+				NextAttemptAt: &now,
+			}
+			if err := d.repo.CreateDelivery(del); err != nil {
+				log.Printf("[webhook] hook=%d – queue %s: %v", hooks[i].ID, event, err)
+				continue
+			}
+			queued = true
+		}
+	}
+	if queued {
+		d.kick()
+	}
+}
+
+// Redeliver queues a copy of a past delivery, to be sent right away.
+func (d *Dispatcher) Redeliver(orig *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	now := time.Now()
+	del := &model.WebhookDelivery{
+		WebhookID:     orig.WebhookID,
+		Event:         orig.Event,
+		Payload:       orig.Payload,
+		Status:        model.DeliveryPending,
+		NextAttemptAt: &now,
+	}
+	if err := d.repo.CreateDelivery(del); err != nil {
+		return nil, err
+	}
+	d.kick()
+	return del, nil
+}
+
+// kick wakes the delivery loop without waiting for the next poll.
+func (d *Dispatcher) kick() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start sends due deliveries until ctx is cancelled.
+func (d *Dispatcher) Start(ctx context.Context) {
+	t := time.NewTicker(d.opts.PollInterval)
+	defer t.Stop()
+	for {
+		d.RunDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// RunDue attempts every delivery that is due now and returns the number attempted.
+func (d *Dispatcher) RunDue(ctx context.Context) int {
+	now := time.Now()
+	due, err := d.repo.DueDeliveries(now, 100)
+	if err != nil {
+		log.Printf("[webhook] load due deliveries: %v", err)
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallelism)
+	attempted := 0
+	for i := range due {
+		del := &due[i]
+		// Claiming pushes the next attempt past this one, so another instance that loaded the
+		// same row skips it, and a crash mid-attempt only delays the retry.
+		ok, err := d.repo.ClaimDelivery(del.ID, *del.NextAttemptAt, now.Add(2*d.opts.Timeout))
+		if err != nil {
+			log.Printf("[webhook] delivery=%d – claim: %v", del.ID, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		attempted++
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			d.attempt(ctx, del)
+		}()
+	}
+	wg.Wait()
+	return attempted
+}
+
+// attempt sends one delivery and records the outcome, scheduling a retry on failure.
+func (d *Dispatcher) attempt(ctx context.Context, del *model.WebhookDelivery) {
+	hook, err := d.repo.FindByID(del.WebhookID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		d.finish(del, model.DeliveryFailed, "webhook was deleted")
+		return
+	case err != nil:
+		log.Printf("[webhook] delivery=%d – load webhook: %v", del.ID, err)
+		return
+	case !hook.Active:
+		d.finish(del, model.DeliveryFailed, "webhook is disabled")
+		return
+	}
+
+	code, err := d.send(ctx, hook, del)
+	del.Attempts++
+	del.ResponseCode = code
+	if err == nil {
+		d.finish(del, model.DeliverySucceeded, "")
+		return
+	}
+	if del.Attempts >= d.opts.MaxAttempts {
+		d.finish(del, model.DeliveryFailed, err.Error())
+		return
+	}
+	next := time.Now().Add(d.backoff(del.Attempts))
+	del.NextAttemptAt = &next
+	del.Error = truncate(err.Error())
+	if err := d.repo.UpdateDelivery(del); err != nil {
+		log.Printf("[webhook] delivery=%d – save: %v", del.ID, err)
+	}
+}
+
+// send posts the payload, signed, and returns the response status.
+func (d *Dispatcher) send(ctx context.Context, hook *model.Webhook, del *model.WebhookDelivery) (int, error) {
+	body := []byte(del.Payload)
+	ts := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.TargetURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, del.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(del.ID), 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, ts, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// finish records a delivery's final outcome.
+func (d *Dispatcher) finish(del *model.WebhookDelivery, status, msg string) {
+	del.Status = status
+	del.Error = truncate(msg)
+	del.NextAttemptAt = nil
+	if status == model.DeliverySucceeded {
+		now := time.Now()
+		del.DeliveredAt = &now
+	}
+	if err := d.repo.UpdateDelivery(del); err != nil {
+		log.Printf("[webhook] delivery=%d – save: %v", del.ID, err)
+	}
+}
+
+// backoff returns the delay after the given number of failed attempts.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.opts.BaseDelay
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+func truncate(s string) string {
+	if len(s) > maxErrorLen {
+		return s[:maxErrorLen]
+	}
+	return s
+}
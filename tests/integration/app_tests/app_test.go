@@ -49,12 +49,15 @@ func (d *dummyCrawlerPool) Start(ctx context.Context) {
 	}
 }
 
-func (d *dummyCrawlerPool) Enqueue(id uint) {
+func (d *dummyCrawlerPool) Enqueue(id uint) error {
 	if d.EnqueueFunc != nil {
 		d.EnqueueFunc(id)
 	}
+	return nil
 }
 
+func (d *dummyCrawlerPool) Cancel(id uint, keepPartial bool) bool { return false }
+
 func (d *dummyCrawlerPool) Shutdown() {
 	if d.ShutdownFunc != nil {
 		d.ShutdownFunc()
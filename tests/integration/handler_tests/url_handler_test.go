@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 
 	"github.com/fuzumoe/urlinsight-backend/internal/handler"
 	"github.com/fuzumoe/urlinsight-backend/internal/model"
@@ -36,6 +37,11 @@ func (m *MockURLService) Get(id uint) (*model.URLDTO, error) {
 	return args.Get(0).(*model.URLDTO), args.Error(1)
 }
 
+func (m *MockURLService) Status(id uint) (string, error) {
+	args := m.Called(id)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockURLService) List(userID uint, p repository.Pagination) (*model.PaginatedResponse[model.URLDTO], error) {
 	args := m.Called(userID, p)
 	return args.Get(0).(*model.PaginatedResponse[model.URLDTO]), args.Error(1)
@@ -56,8 +62,8 @@ func (m *MockURLService) Start(id uint) error {
 	return args.Error(0)
 }
 
-func (m *MockURLService) Stop(id uint) error {
-	args := m.Called(id)
+func (m *MockURLService) Stop(id uint, keepPartial bool) error {
+	args := m.Called(id, keepPartial)
 	return args.Error(0)
 }
 
@@ -70,8 +76,8 @@ func (m *MockURLService) Results(id uint) (*model.URLDTO, error) {
 }
 
 // Implementation of the new ResultsWithDetails method that returns pointers to models
-func (m *MockURLService) ResultsWithDetails(id uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error) {
-	args := m.Called(id)
+func (m *MockURLService) ResultsWithDetails(id, snapshotID uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error) {
+	args := m.Called(id, snapshotID)
 	var url *model.URL
 	var analysisResults []*model.AnalysisResult
 	var links []*model.Link
@@ -89,6 +95,14 @@ func (m *MockURLService) ResultsWithDetails(id uint) (*model.URL, []*model.Analy
 	return url, analysisResults, links, args.Error(3)
 }
 
+func (m *MockURLService) SiteSummary(id, snapshotID uint) (*model.SiteSummary, error) {
+	args := m.Called(id, snapshotID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SiteSummary), args.Error(1)
+}
+
 // Setup creates a test router with auth middleware mocked
 func setupTestRouter(urlService *MockURLService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
@@ -283,7 +297,7 @@ func TestURLHandler_Integration(t *testing.T) {
 
 	t.Run("Stop", func(t *testing.T) {
 		// Setup service mock
-		mockService.On("Stop", uint(42)).Return(nil).Once()
+		mockService.On("Stop", uint(42), false).Return(nil).Once()
 
 		// Prepare and execute request
 		req, _ := http.NewRequest("PATCH", "/api/urls/42/stop", nil)
@@ -332,7 +346,7 @@ func TestURLHandler_Integration(t *testing.T) {
 		}
 
 		// Setup service mock to return these pointers
-		mockService.On("ResultsWithDetails", uint(42)).Return(url, analysisResults, links, nil).Once()
+		mockService.On("ResultsWithDetails", uint(42), uint(0)).Return(url, analysisResults, links, nil).Once()
 
 		// Prepare and execute request
 		req, _ := http.NewRequest("GET", "/api/urls/42/results", nil)
@@ -392,7 +406,7 @@ func TestURLHandler_Integration(t *testing.T) {
 
 	t.Run("Results_NotFound", func(t *testing.T) {
 		// Setup service mock to return a not found error
-		mockService.On("ResultsWithDetails", uint(999)).Return(nil, nil, nil, fmt.Errorf("record not found")).Once()
+		mockService.On("ResultsWithDetails", uint(999), uint(0)).Return(nil, nil, nil, fmt.Errorf("failed to get detailed URL results: %w", gorm.ErrRecordNotFound)).Once()
 
 		// Prepare and execute request
 		req, _ := http.NewRequest("GET", "/api/urls/999/results", nil)
@@ -441,7 +455,7 @@ func TestURLHandler_ResultsWithNilValues(t *testing.T) {
 		Status:      "queued", // Not analyzed yet
 	}
 
-	mockService.On("ResultsWithDetails", uint(42)).Return(url, nil, nil, nil).Once()
+	mockService.On("ResultsWithDetails", uint(42), uint(0)).Return(url, nil, nil, nil).Once()
 
 	// Prepare and execute request
 	req, _ := http.NewRequest("GET", "/api/urls/42/results", nil)
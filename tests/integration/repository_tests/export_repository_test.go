@@ -0,0 +1,73 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+	"github.com/fuzumoe/urlinsight-backend/tests/utils"
+)
+
+func TestExportRepo_Integration(t *testing.T) {
+	// Get a clean database state.
+	db := utils.SetupTest(t)
+
+	exportRepo := repository.NewExportRepo(db)
+	analysisRepo := repository.NewAnalysisResultRepo(db)
+	linkRepo := repository.NewLinkRepo(db)
+	urlRepo := repository.NewURLRepo(db)
+	userRepo := repository.NewUserRepo(db)
+
+	testUser := &model.User{
+		Username: "exportowner",
+		Email:    "exportowner@example.com",
+		Password: "password123",
+	}
+	require.NoError(t, userRepo.Create(testUser))
+
+	testURL := &model.URL{
+		UserID:      testUser.ID,
+		OriginalURL: "https://example.com",
+		Status:      "done",
+	}
+	require.NoError(t, urlRepo.Create(testURL))
+
+	// Two runs an hour apart, each with one link saved before links carried
+	// their snapshot ID.
+	first := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	second := first.Add(time.Hour)
+
+	firstRun := &model.AnalysisResult{URLID: testURL.ID, Title: "First", CreatedAt: first}
+	require.NoError(t, analysisRepo.Create(firstRun, []model.Link{{URLID: testURL.ID, Href: "https://first.example"}}))
+	secondRun := &model.AnalysisResult{URLID: testURL.ID, Title: "Second", CreatedAt: second}
+	require.NoError(t, analysisRepo.Create(secondRun, nil))
+
+	require.NoError(t, linkRepo.Create(&model.Link{URLID: testURL.ID, Href: "https://legacy-first.example", CreatedAt: first.Add(time.Minute)}))
+	require.NoError(t, linkRepo.Create(&model.Link{URLID: testURL.ID, Href: "https://legacy-second.example", CreatedAt: second.Add(time.Minute)}))
+
+	hrefs := func(start, end uint) []string {
+		var got []string
+		err := exportRepo.EachLink(testURL.ID, start, end, "", func(l *model.Link) error {
+			got = append(got, l.Href)
+			return nil
+		})
+		require.NoError(t, err)
+		return got
+	}
+
+	t.Run("EachLink Earlier Run Includes Legacy Links", func(t *testing.T) {
+		start, end, err := exportRepo.RunBounds(testURL.ID, firstRun.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://first.example", "https://legacy-first.example"}, hrefs(start, end))
+	})
+
+	t.Run("EachLink Latest Run Includes Legacy Links", func(t *testing.T) {
+		start, end, err := exportRepo.RunBounds(testURL.ID, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://legacy-second.example"}, hrefs(start, end))
+	})
+}
@@ -0,0 +1,57 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+	"github.com/fuzumoe/urlinsight-backend/tests/utils"
+)
+
+func TestScheduleRepo_Integration(t *testing.T) {
+	// Get a clean database state.
+	db := utils.SetupTest(t)
+
+	scheduleRepo := repository.NewScheduleRepo(db)
+	jobRepo := repository.NewCrawlJobRepo(db)
+	urlRepo := repository.NewURLRepo(db)
+	userRepo := repository.NewUserRepo(db)
+
+	testUser := &model.User{
+		Username: "scheduleowner",
+		Email:    "scheduleowner@example.com",
+		Password: "password123",
+	}
+	require.NoError(t, userRepo.Create(testUser))
+
+	// A freshly created URL is queued but has never been started.
+	testURL := model.URLFromCreateInput(&model.CreateURLInputDTO{
+		UserID:      testUser.ID,
+		OriginalURL: "https://example.com",
+	})
+	require.NoError(t, urlRepo.Create(testURL))
+	require.Equal(t, model.StatusQueued, testURL.Status)
+
+	now := time.Now()
+	due := now.Add(-time.Minute)
+	require.NoError(t, scheduleRepo.Save(&model.Schedule{URLID: testURL.ID, IntervalSeconds: 3600, NextRunAt: &due}))
+
+	t.Run("Due For Never Started URL", func(t *testing.T) {
+		schedules, err := scheduleRepo.Due(now, 10)
+		require.NoError(t, err)
+		require.Len(t, schedules, 1, "a queued URL without a crawl job is idle")
+		assert.Equal(t, testURL.ID, schedules[0].URLID)
+	})
+
+	t.Run("Not Due While A Job Is Pending", func(t *testing.T) {
+		require.NoError(t, jobRepo.Enqueue(testURL.ID))
+
+		schedules, err := scheduleRepo.Due(now, 10)
+		require.NoError(t, err)
+		assert.Empty(t, schedules)
+	})
+}
@@ -289,6 +289,8 @@ func TestURLRepo_Integration(t *testing.T) {
 		err = db.Create(analysisRes).Error
 		require.NoError(t, err, "Should create analysis result for ResultsWithDetails test")
 
+		// Links saved before they were tied to a snapshot have no analysis_result_id and
+		// still belong to the run they were found in.
 		links := []model.Link{
 			{URLID: detailsURL.ID, Href: "https://details-internal.com", IsExternal: false, StatusCode: 200},
 			{URLID: detailsURL.ID, Href: "https://details-external.com", IsExternal: true, StatusCode: 200},
@@ -298,7 +300,7 @@ func TestURLRepo_Integration(t *testing.T) {
 		require.NoError(t, err, "Should create links for ResultsWithDetails test")
 
 		// Test the ResultsWithDetails method - note return type of links is []*model.Link
-		url, analysisResults, linkPointers, err := urlRepo.ResultsWithDetails(detailsURL.ID)
+		url, analysisResults, linkPointers, err := urlRepo.ResultsWithDetails(detailsURL.ID, 0)
 		require.NoError(t, err, "Should get detailed results without error")
 
 		// Verify URL
@@ -342,7 +344,7 @@ func TestURLRepo_Integration(t *testing.T) {
 		assert.Equal(t, 404, brokenLink.StatusCode, "Broken link should have correct status code")
 
 		// Test not found case
-		_, _, _, err = urlRepo.ResultsWithDetails(9999)
+		_, _, _, err = urlRepo.ResultsWithDetails(9999, 0)
 		assert.Error(t, err, "Should return error for non-existent URL")
 	})
 
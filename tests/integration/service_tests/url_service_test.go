@@ -210,16 +210,15 @@ func TestURLService_Integration(t *testing.T) {
 		require.NoError(t, err, "Should update URL status to running without error.")
 
 		// Now stop crawling the URL
-		err = urlService.Stop(createdID)
+		err = urlService.Stop(createdID, false)
 		require.NoError(t, err, "Should stop crawling without error.")
 
-		// Verify the URL status is updated to error
+		// Verify the URL status is updated to stopped
 		urlDTO, err := urlService.Get(createdID)
 		require.NoError(t, err, "Should get URL without error.")
 
-		// URLService.Stop now sets status to 'error' as per the implementation
-		assert.Equal(t, model.StatusError, urlDTO.Status,
-			"Status should be set to 'error' when stopping a URL via the service.")
+		assert.Equal(t, model.StatusStopped, urlDTO.Status,
+			"Status should be set to 'stopped' when stopping a URL via the service.")
 	})
 
 	t.Run("Results", func(t *testing.T) {
@@ -249,7 +248,7 @@ func TestURLService_Integration(t *testing.T) {
 		require.NoError(t, err, "Should create URL without error.")
 
 		// Call ResultsWithDetails method
-		url, analysisResults, links, err := urlService.ResultsWithDetails(createdID)
+		url, analysisResults, links, err := urlService.ResultsWithDetails(createdID, 0)
 		require.NoError(t, err, "Should get detailed results without error")
 
 		// Verify URL object
@@ -298,7 +297,7 @@ func TestURLService_Integration(t *testing.T) {
 				"Error message should indicate the start operation failed")
 
 			// Try to stop a URL that doesn't exist
-			err = urlService.Stop(9999)
+			err = urlService.Stop(9999, false)
 			assert.Error(t, err, "Stopping a non-existent URL should return an error.")
 			assert.Contains(t, err.Error(), "cannot stop crawling",
 				"Error message should indicate the stop operation failed")
@@ -308,7 +307,7 @@ func TestURLService_Integration(t *testing.T) {
 			assert.Error(t, err, "Getting results for a non-existent URL should return an error")
 
 			// Try to get detailed results for a URL that doesn't exist
-			_, _, _, err = urlService.ResultsWithDetails(9999)
+			_, _, _, err = urlService.ResultsWithDetails(9999, 0)
 			assert.Error(t, err, "Getting detailed results for a non-existent URL should return an error")
 			assert.Contains(t, err.Error(), "failed to get detailed URL results",
 				"Error message should indicate the operation failed")
@@ -0,0 +1,122 @@
+package analyzer_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/analyzer"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+func TestHTMLAnalyzer_Assertions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>Shop - Home</title>
+				<meta name="Robots" content="index, NoFollow"></head>
+				<body><div id="checkout"></div>
+				<a href="/ok">ok</a><a href="/gone">gone</a><a href="/also-gone">also gone</a></body></html>`))
+		case "/robots.txt", "/gone", "/also-gone":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	a := analyzer.NewHTMLAnalyzerWithConfig(analyzer.Config{})
+
+	t.Run("Passing", func(t *testing.T) {
+		ctx := analyzer.WithAssertions(context.Background(), []model.AssertionRule{
+			{Type: model.AssertSelectorExists, Selector: "#checkout"},
+			{Type: model.AssertSelectorAbsent, Selector: ".error-banner"},
+			{Type: model.AssertTitleMatches, Pattern: `^Shop\b`},
+			{Type: model.AssertMetaRobotsExcludes, Value: "noindex"},
+			{Type: model.AssertMaxBrokenInternalLinks, Max: 2},
+		})
+		res, _, err := a.Analyze(ctx, u)
+		require.NoError(t, err)
+		require.Equal(t, model.HealthPassing, res.Health)
+		require.Len(t, res.Assertions, 5)
+		for _, ar := range res.Assertions {
+			require.True(t, ar.Passed, "%s: %s", ar.Rule.Type, ar.Message)
+			require.Empty(t, ar.Message)
+		}
+	})
+
+	t.Run("Failing", func(t *testing.T) {
+		ctx := analyzer.WithAssertions(context.Background(), []model.AssertionRule{
+			{Type: model.AssertSelectorExists, Selector: "#cart"},
+			{Type: model.AssertSelectorAbsent, Selector: "div"},
+			{Type: model.AssertTitleMatches, Pattern: `^Checkout`},
+			{Type: model.AssertMetaRobotsExcludes, Value: "nofollow"},
+			{Type: model.AssertMaxBrokenLinks},
+			{Type: model.AssertMaxBrokenInternalLinks, Max: 1},
+		})
+		res, _, err := a.Analyze(ctx, u)
+		require.NoError(t, err)
+		require.Equal(t, model.HealthFailing, res.Health)
+		require.Len(t, res.Assertions, 6)
+		messages := make([]string, len(res.Assertions))
+		for i, ar := range res.Assertions {
+			require.False(t, ar.Passed, ar.Rule.Type)
+			messages[i] = ar.Message
+		}
+		require.Equal(t, []string{
+			`no element matches "#cart"`,
+			`1 elements match "div"`,
+			`title "Shop - Home" does not match "^Checkout"`,
+			`meta robots "index, NoFollow" contains "nofollow"`,
+			`2 broken links, at most 0 allowed`,
+			`2 broken internal links, at most 1 allowed`,
+		}, messages)
+	})
+
+	t.Run("Links Module Not Run", func(t *testing.T) {
+		ctx := analyzer.WithModules(context.Background(), []string{analyzer.ModuleSEO})
+		ctx = analyzer.WithAssertions(ctx, []model.AssertionRule{{Type: model.AssertMaxBrokenLinks, Max: 5}})
+		res, _, err := a.Analyze(ctx, u)
+		require.NoError(t, err)
+		require.Equal(t, model.HealthFailing, res.Health)
+		require.Contains(t, res.Assertions[0].Message, `the "links" module did not run`)
+	})
+
+	t.Run("No Rules", func(t *testing.T) {
+		res, _, err := a.Analyze(context.Background(), u)
+		require.NoError(t, err)
+		require.Empty(t, res.Health)
+		require.Nil(t, res.Assertions)
+	})
+}
+
+func TestValidateAssertions(t *testing.T) {
+	require.NoError(t, analyzer.ValidateAssertions(nil))
+	require.NoError(t, analyzer.ValidateAssertions([]model.AssertionRule{
+		{Type: model.AssertSelectorExists, Selector: "form#login input[type=password]"},
+		{Type: model.AssertMaxBrokenLinks},
+	}))
+
+	for _, tc := range []struct {
+		name string
+		rule model.AssertionRule
+		err  string
+	}{
+		{"Unknown Type", model.AssertionRule{Type: "spellcheck"}, `assertion 0: unknown type "spellcheck"`},
+		{"Bad Selector", model.AssertionRule{Type: model.AssertSelectorExists, Selector: "div["}, `assertion 0: invalid selector "div["`},
+		{"Empty Selector", model.AssertionRule{Type: model.AssertSelectorAbsent}, `assertion 0: invalid selector ""`},
+		{"Bad Pattern", model.AssertionRule{Type: model.AssertTitleMatches, Pattern: "("}, "assertion 0: invalid pattern"},
+		{"Missing Value", model.AssertionRule{Type: model.AssertMetaRobotsExcludes}, "assertion 0: value is required"},
+		{"Negative Max", model.AssertionRule{Type: model.AssertMaxBrokenLinks, Max: -1}, "assertion 0: max must not be negative"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := analyzer.ValidateAssertions([]model.AssertionRule{tc.rule})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package analyzer_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/analyzer"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+func TestHTMLAnalyzer_Content(t *testing.T) {
+	analyze := func(t *testing.T, cfg analyzer.Config, h http.HandlerFunc) (*model.AnalysisResult, error) {
+		t.Helper()
+		ts := httptest.NewServer(h)
+		t.Cleanup(ts.Close)
+		u, err := url.Parse(ts.URL + "/")
+		require.NoError(t, err)
+		res, _, err := analyzer.NewHTMLAnalyzerWithConfig(cfg).Analyze(context.Background(), u)
+		return res, err
+	}
+
+	t.Run("Charset From Header", func(t *testing.T) {
+		// "Café" in ISO-8859-1.
+		res, err := analyze(t, analyzer.Config{}, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=ISO-8859-1")
+			_, _ = w.Write([]byte("<html><head><title>Caf\xe9</title></head></html>"))
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Café", res.Title)
+		require.Equal(t, "windows-1252", res.Charset, "ISO-8859-1 is decoded as windows-1252, as browsers do")
+	})
+
+	t.Run("Charset From Meta", func(t *testing.T) {
+		// "Привет" in windows-1251.
+		res, err := analyze(t, analyzer.Config{}, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html><head><meta charset="windows-1251"><title>` +
+				"\xcf\xf0\xe8\xe2\xe5\xf2</title></head></html>"))
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Привет", res.Title)
+		require.Equal(t, "windows-1251", res.Charset)
+	})
+
+	t.Run("Body Truncated", func(t *testing.T) {
+		res, err := analyze(t, analyzer.Config{MaxBodyBytes: 64}, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte("<html><head><title>Big</title></head><body>" +
+				strings.Repeat("<p>filler</p>", 1000) + "</body></html>"))
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Big", res.Title, "the part within the cap should still be analyzed")
+		require.Equal(t, "utf-8", res.Charset)
+		require.True(t, res.BodyTruncated)
+		require.Equal(t, int64(64), res.Timing.ResponseBytes)
+	})
+
+	t.Run("Error Status Recorded", func(t *testing.T) {
+		res, err := analyze(t, analyzer.Config{}, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("<html><head><title>Not Found</title></head></html>"))
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Not Found", res.Title)
+		require.Equal(t, "text/html", res.ContentType)
+		require.False(t, res.BodyTruncated)
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("Non HTML Refused", func(t *testing.T) {
+		_, err := analyze(t, analyzer.Config{}, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write([]byte("%PDF-1.7"))
+		})
+		require.ErrorIs(t, err, analyzer.ErrUnsupportedContentType)
+		require.Contains(t, err.Error(), "application/pdf")
+	})
+
+	t.Run("Missing Content Type Sniffed", func(t *testing.T) {
+		_, err := analyze(t, analyzer.Config{}, func(w http.ResponseWriter, r *http.Request) {
+			w.Header()["Content-Type"] = nil // keep net/http from sniffing on our behalf
+			_, _ = w.Write([]byte("\x89PNG\r\n\x1a\n"))
+		})
+		require.ErrorIs(t, err, analyzer.ErrUnsupportedContentType)
+		require.Contains(t, err.Error(), "image/png")
+	})
+}
@@ -2,10 +2,13 @@ package analyzer_test
 
 import (
 	"context"
+	"crypto/x509"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -13,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/fuzumoe/urlinsight-backend/internal/analyzer"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
 )
 
 func TestHTMLAnalyzer_Analyze(t *testing.T) {
@@ -88,3 +92,461 @@ func TestHTMLAnalyzer_Analyze(t *testing.T) {
 		assert.True(t, externalFound, "External link should be present")
 	})
 }
+
+func TestHTMLAnalyzer_SEOMetadata(t *testing.T) {
+	htmlContent := `<!DOCTYPE html>
+	<html>
+	  <head>
+		<title>SEO Page</title>
+		<meta name="description" content=" A page about testing. ">
+		<meta name="description" content="Duplicate description">
+		<meta name="ROBOTS" content="noindex, follow">
+		<meta name="viewport" content="width=device-width, initial-scale=1">
+		<link rel="canonical" href="/canonical">
+		<link rel="alternate" hreflang="de" href="/de/">
+		<link rel="alternate" hreflang="x-default" href="https://example.com/">
+		<link rel="alternate" type="application/rss+xml" href="/feed">
+		<meta property="og:title" content="OG Title">
+		<meta property="og:image" content="https://example.com/a.png">
+		<meta property="og:image" content="https://example.com/b.png">
+		<meta name="twitter:card" content="summary_large_image">
+		<meta property="twitter:site" content="@example">
+	  </head>
+	  <body></body>
+	</html>`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(htmlContent))
+	}))
+	defer ts.Close()
+
+	baseURL, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	result, _, err := analyzer.NewHTMLAnalyzer().Analyze(context.Background(), baseURL)
+	require.NoError(t, err)
+	require.NotNil(t, result.SEO)
+	seo := result.SEO
+
+	t.Run("Meta Tags", func(t *testing.T) {
+		assert.Equal(t, "A page about testing.", seo.MetaDescription, "First description should win")
+		assert.Equal(t, "noindex, follow", seo.MetaRobots)
+		assert.Equal(t, "width=device-width, initial-scale=1", seo.Viewport)
+	})
+
+	t.Run("Canonical And Hreflang", func(t *testing.T) {
+		assert.Equal(t, ts.URL+"/canonical", seo.Canonical, "Canonical should be absolute")
+		require.Len(t, seo.Hreflang, 2, "Alternates without hreflang are not language variants")
+		assert.Equal(t, "de", seo.Hreflang[0].Lang)
+		assert.Equal(t, ts.URL+"/de/", seo.Hreflang[0].Href)
+		assert.Equal(t, "x-default", seo.Hreflang[1].Lang)
+	})
+
+	t.Run("Social Tags", func(t *testing.T) {
+		assert.Equal(t, "OG Title", seo.OpenGraph["og:title"])
+		assert.Equal(t, "https://example.com/a.png", seo.OpenGraph["og:image"])
+		assert.Equal(t, "summary_large_image", seo.TwitterCard["twitter:card"])
+		assert.Equal(t, "@example", seo.TwitterCard["twitter:site"])
+	})
+}
+
+func TestHTMLAnalyzer_StructuredData(t *testing.T) {
+	htmlContent := `<!DOCTYPE html>
+	<html>
+	  <head>
+		<script type="application/ld+json">
+		{"@context": "https://schema.org", "@type": "Product", "name": "Widget", "offers": {"@type": "Offer", "price": "9.99"}}
+		</script>
+		<script type="application/ld+json">
+		{"@context": "https://schema.org", "@graph": [{"@type": "Organization", "name": "ACME"}, {"@type": ["WebSite", "WebPage"], "url": "/"}]}
+		</script>
+		<script type="application/ld+json">{"@type": "Product", "name": </script>
+		<script type="application/ld+json">{"@context": "https://schema.org", "name": "untyped"}</script>
+	  </head>
+	  <body>
+		<div itemscope itemtype="https://schema.org/Product">
+		  <span itemprop="name">Widget</span>
+		  <div itemprop="offers" itemscope itemtype="https://schema.org/Offer">
+			<span itemprop="price">9.99</span>
+		  </div>
+		</div>
+		<div vocab="https://schema.org/" typeof="Person">
+		  <span property="name">Jane</span>
+		  <span property="schema:jobTitle">Engineer</span>
+		</div>
+	  </body>
+	</html>`
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(htmlContent))
+	}))
+	defer ts.Close()
+
+	baseURL, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	result, _, err := analyzer.NewHTMLAnalyzer().Analyze(context.Background(), baseURL)
+	require.NoError(t, err)
+	require.NotNil(t, result.StructuredData)
+	sd := result.StructuredData
+
+	byFormat := func(format string) []model.StructuredDataItem {
+		var out []model.StructuredDataItem
+		for _, it := range sd.Items {
+			if it.Format == format {
+				out = append(out, it)
+			}
+		}
+		return out
+	}
+
+	t.Run("JSON-LD", func(t *testing.T) {
+		items := byFormat(model.FormatJSONLD)
+		require.Len(t, items, 4)
+		assert.Equal(t, "Product", items[0].Type)
+		assert.Equal(t, []string{"name", "offers"}, items[0].Properties)
+		assert.Equal(t, "Organization", items[1].Type)
+		assert.Equal(t, "WebSite", items[2].Type)
+		assert.Equal(t, "WebPage", items[3].Type)
+	})
+
+	t.Run("Malformed JSON-LD", func(t *testing.T) {
+		require.Len(t, sd.Errors, 2)
+		assert.Equal(t, model.FormatJSONLD, sd.Errors[0].Format)
+		assert.Equal(t, 2, sd.Errors[0].Block)
+		assert.Equal(t, 3, sd.Errors[1].Block)
+		assert.Equal(t, "no entity with @type", sd.Errors[1].Message)
+	})
+
+	t.Run("Microdata", func(t *testing.T) {
+		items := byFormat(model.FormatMicrodata)
+		require.Len(t, items, 1, "Nested itemprop scopes are not top-level items")
+		assert.Equal(t, "Product", items[0].Type)
+		assert.Equal(t, []string{"name", "offers"}, items[0].Properties)
+	})
+
+	t.Run("RDFa", func(t *testing.T) {
+		items := byFormat(model.FormatRDFa)
+		require.Len(t, items, 1)
+		assert.Equal(t, "Person", items[0].Type)
+		assert.Equal(t, []string{"jobTitle", "name"}, items[0].Properties)
+	})
+}
+
+func TestHTMLAnalyzer_Accessibility(t *testing.T) {
+	analyze := func(t *testing.T, doc string) *model.AccessibilityReport {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(doc))
+		}))
+		defer ts.Close()
+		baseURL, err := url.Parse(ts.URL)
+		require.NoError(t, err)
+		result, _, err := analyzer.NewHTMLAnalyzer().Analyze(context.Background(), baseURL)
+		require.NoError(t, err)
+		require.NotNil(t, result.Accessibility)
+		return result.Accessibility
+	}
+	rules := func(r *model.AccessibilityReport) map[string][]string {
+		out := map[string][]string{}
+		for _, f := range r.Findings {
+			out[f.Rule] = append(out[f.Rule], f.Path)
+		}
+		return out
+	}
+
+	t.Run("Clean Page", func(t *testing.T) {
+		r := analyze(t, `<!DOCTYPE html><html lang="en"><body>
+			<main>
+			  <h1>Title</h1><h2>Section</h2>
+			  <img src="/logo.png" alt="">
+			  <label for="q">Search</label><input id="q" type="text">
+			  <label>Name <input type="text"></label>
+			  <a href="/x"><img src="/i.png" alt="Home"></a>
+			  <button aria-label="Close"></button>
+			  <input type="submit">
+			</main>
+		</body></html>`)
+		assert.Empty(t, r.Findings)
+		assert.Equal(t, 0, r.Errors)
+	})
+
+	t.Run("Findings", func(t *testing.T) {
+		r := analyze(t, `<!DOCTYPE html><html><body>
+			<div id="a"><h1>Title</h1><h3>Skipped</h3></div>
+			<div id="a">
+			  <img src="/x.png">
+			  <input type="email">
+			  <a href="/empty"></a>
+			  <button></button>
+			</div>
+		</body></html>`)
+		found := rules(r)
+		assert.Equal(t, []string{"html"}, found["html-lang"])
+		assert.Equal(t, []string{"div#a > img"}, found["image-alt"])
+		assert.Equal(t, []string{"div#a > input"}, found["label"])
+		assert.Equal(t, []string{"div#a > h3"}, found["heading-order"])
+		assert.Equal(t, []string{"div#a > a"}, found["link-name"])
+		assert.Equal(t, []string{"div#a > button"}, found["button-name"])
+		assert.Equal(t, []string{"div#a"}, found["duplicate-id"])
+		assert.Equal(t, []string{"html > body"}, found["landmarks"])
+		assert.Equal(t, 6, r.Errors)
+		assert.Equal(t, 2, r.Warnings)
+	})
+
+	t.Run("CSS Path", func(t *testing.T) {
+		r := analyze(t, `<!DOCTYPE html><html lang="en"><body><main>
+			<p><img src="/1.png" alt="one"></p>
+			<p><img src="/2.png" alt="two"><img src="/3.png"></p>
+		</main></body></html>`)
+		require.Len(t, r.Findings, 1)
+		assert.Equal(t, "html > body > main > p:nth-of-type(2) > img:nth-of-type(2)", r.Findings[0].Path)
+		assert.Equal(t, model.SeverityError, r.Findings[0].Severity)
+	})
+}
+
+func TestHTMLAnalyzer_Security(t *testing.T) {
+	analyze := func(t *testing.T, set func(h http.Header)) *model.SecurityReport {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			set(w.Header())
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<!DOCTYPE html><html><body></body></html>`))
+		}))
+		defer ts.Close()
+		baseURL, err := url.Parse(ts.URL)
+		require.NoError(t, err)
+		result, _, err := analyzer.NewHTMLAnalyzer().Analyze(context.Background(), baseURL)
+		require.NoError(t, err)
+		require.NotNil(t, result.Security)
+		return result.Security
+	}
+	statusOf := func(r *model.SecurityReport) map[string]string {
+		out := map[string]string{}
+		for _, h := range r.Headers {
+			out[h.Name] = h.Status
+		}
+		return out
+	}
+
+	t.Run("Hardened Headers", func(t *testing.T) {
+		r := analyze(t, func(h http.Header) {
+			h.Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+			h.Set("X-Content-Type-Options", "nosniff")
+			h.Set("Referrer-Policy", "unsafe-url, strict-origin-when-cross-origin")
+			h.Set("Permissions-Policy", "camera=()")
+			h.Add("Set-Cookie", "session=abc; HttpOnly; SameSite=Strict")
+		})
+		status := statusOf(r)
+		assert.Equal(t, model.CheckPass, status["Content-Security-Policy"])
+		assert.Equal(t, model.CheckFail, status["Strict-Transport-Security"], "HSTS needs HTTPS")
+		assert.Equal(t, model.CheckPass, status["X-Frame-Options"], "frame-ancestors covers framing")
+		assert.Equal(t, model.CheckPass, status["Referrer-Policy"], "Last policy wins")
+		require.Len(t, r.Cookies, 1)
+		assert.Empty(t, r.Cookies[0].Issues, "Secure is not required over plain HTTP")
+		assert.Equal(t, 75, r.Score)
+		assert.Equal(t, "B", r.Grade)
+	})
+
+	t.Run("Weak Headers", func(t *testing.T) {
+		r := analyze(t, func(h http.Header) {
+			h.Set("Content-Security-Policy", "script-src 'self' 'UNSAFE-INLINE'")
+			h.Set("X-Frame-Options", "ALLOW-FROM https://example.com")
+			h.Set("X-Content-Type-Options", "sniff")
+			h.Add("Set-Cookie", "a=1")
+			h.Add("Set-Cookie", "b=2; SameSite=None")
+		})
+		status := statusOf(r)
+		assert.Equal(t, model.CheckWarn, status["Content-Security-Policy"])
+		assert.Equal(t, model.CheckWarn, status["X-Frame-Options"])
+		assert.Equal(t, model.CheckFail, status["X-Content-Type-Options"])
+		assert.Equal(t, model.CheckFail, status["Referrer-Policy"])
+		require.Len(t, r.Cookies, 2)
+		assert.Equal(t, []string{"missing HttpOnly", "missing SameSite"}, r.Cookies[0].Issues)
+		assert.Contains(t, r.Cookies[1].Issues, "SameSite=None requires Secure")
+		assert.Equal(t, 12+7-10, r.Score)
+		assert.Equal(t, "F", r.Grade)
+	})
+}
+
+// trustingAnalyzer returns an analyzer that trusts the test server's certificate.
+func trustingAnalyzer(ts *httptest.Server) analyzer.Analyzer {
+	cfg := analyzer.DefaultConfig()
+	cfg.RootCAs = x509.NewCertPool()
+	cfg.RootCAs.AddCert(ts.Certificate())
+	return analyzer.NewHTMLAnalyzerWithConfig(cfg)
+}
+
+func TestHTMLAnalyzer_TLS(t *testing.T) {
+	newServer := func(hits *atomic.Int32) *httptest.Server {
+		return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>TLS</title></head></html>`))
+		}))
+	}
+
+	t.Run("Untrusted Chain Is Reported Without Fetching", func(t *testing.T) {
+		var hits atomic.Int32
+		ts := newServer(&hits)
+		defer ts.Close()
+		baseURL, err := url.Parse(ts.URL)
+		require.NoError(t, err)
+
+		result, links, err := analyzer.NewHTMLAnalyzer().Analyze(context.Background(), baseURL)
+		require.ErrorIs(t, err, analyzer.ErrUntrustedCertificate)
+		assert.Zero(t, hits.Load(), "Nothing may be requested over an unverified connection")
+		assert.Empty(t, links)
+
+		require.NotNil(t, result)
+		assert.Empty(t, result.Title)
+		require.NotNil(t, result.TLS)
+		assert.False(t, result.TLS.Verified)
+		assert.NotEmpty(t, result.TLS.VerifyError)
+		assert.Equal(t, "TLS 1.3", result.TLS.Version)
+		assert.NotEmpty(t, result.TLS.CipherSuite)
+
+		leaf := ts.Certificate()
+		require.NotEmpty(t, result.TLS.Chain)
+		assert.Equal(t, leaf.Subject.String(), result.TLS.Chain[0].Subject)
+		assert.Equal(t, leaf.DNSNames, result.TLS.Chain[0].DNSNames)
+		assert.Equal(t, "RSA-2048", result.TLS.Chain[0].KeyType)
+		require.NotNil(t, result.CertExpiresAt)
+		assert.True(t, leaf.NotAfter.Equal(*result.CertExpiresAt))
+	})
+
+	t.Run("Trusted Chain", func(t *testing.T) {
+		var hits atomic.Int32
+		ts := newServer(&hits)
+		defer ts.Close()
+		baseURL, err := url.Parse(ts.URL)
+		require.NoError(t, err)
+
+		result, _, err := trustingAnalyzer(ts).Analyze(context.Background(), baseURL)
+		require.NoError(t, err)
+		assert.Equal(t, "TLS", result.Title)
+		require.NotNil(t, result.TLS)
+		assert.True(t, result.TLS.Verified)
+		assert.Empty(t, result.TLS.VerifyError)
+		assert.Equal(t, ts.Certificate().Subject.String(), result.TLS.Chain[0].Subject)
+	})
+
+	t.Run("Plain HTTP Has No Report", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`<html></html>`))
+		}))
+		defer ts.Close()
+		baseURL, err := url.Parse(ts.URL)
+		require.NoError(t, err)
+
+		result, _, err := analyzer.NewHTMLAnalyzer().Analyze(context.Background(), baseURL)
+		require.NoError(t, err)
+		assert.Nil(t, result.TLS)
+		assert.Nil(t, result.CertExpiresAt)
+	})
+}
+
+func TestHTMLAnalyzer_Redirects(t *testing.T) {
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><head><title>Landing</title></head></html>`))
+	}))
+	defer plain.Close()
+	secure := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, plain.URL+"/landing", http.StatusMovedPermanently)
+	}))
+	defer secure.Close()
+
+	baseURL, err := url.Parse(secure.URL + "/start")
+	require.NoError(t, err)
+	result, _, err := trustingAnalyzer(secure).Analyze(context.Background(), baseURL)
+	require.NoError(t, err)
+	assert.Equal(t, "Landing", result.Title)
+
+	require.NotNil(t, result.Redirects)
+	require.Len(t, result.Redirects.Hops, 1)
+	assert.Equal(t, secure.URL+"/start", result.Redirects.Hops[0].URL)
+	assert.Equal(t, plain.URL+"/landing", result.Redirects.Hops[0].Location)
+	assert.Equal(t, plain.URL+"/landing", result.Redirects.FinalURL)
+	assert.True(t, result.Redirects.Downgrade, "HTTPS to HTTP is a downgrade")
+}
+
+func TestHTMLAnalyzer_Timing(t *testing.T) {
+	page := `<html><head><title>Slow</title></head><body>` + strings.Repeat("x", 4096) + `</body></html>`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(50 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		time.Sleep(30 * time.Millisecond)
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(page + `<a href="/slow">slow</a>`))
+	}))
+	defer ts.Close()
+
+	baseURL, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	result, links, err := analyzer.NewHTMLAnalyzer().Analyze(context.Background(), baseURL)
+	require.NoError(t, err)
+
+	timing := result.Timing
+	assert.Equal(t, int64(len(page)+len(`<a href="/slow">slow</a>`)), timing.ResponseBytes)
+	assert.GreaterOrEqual(t, timing.TTFBMs, int64(30), "TTFB includes server think time")
+	assert.GreaterOrEqual(t, timing.TotalMs, timing.TTFBMs)
+	assert.GreaterOrEqual(t, timing.TotalMs, timing.ConnectMs)
+	assert.Zero(t, timing.TLSMs, "Plain HTTP has no handshake")
+
+	require.Len(t, links, 1)
+	assert.GreaterOrEqual(t, links[0].TTFBMs, int64(50), "Link TTFB should be recorded")
+}
+
+// TestHTMLAnalyzer_TimingPhases reaches the DNS, connect and TLS hooks; run it with -race.
+func TestHTMLAnalyzer_TimingPhases(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><head><title>Phases</title></head></html>`))
+	})
+	check := func(t *testing.T, a analyzer.Analyzer, raw string) model.PageTiming {
+		t.Helper()
+		u, err := url.Parse(raw)
+		require.NoError(t, err)
+		result, _, err := a.Analyze(context.Background(), u)
+		require.NoError(t, err)
+		timing := result.Timing
+		for _, phase := range []int64{timing.DNSMs, timing.ConnectMs, timing.TLSMs, timing.TTFBMs} {
+			assert.GreaterOrEqual(t, phase, int64(0))
+			assert.LessOrEqual(t, phase, timing.TotalMs)
+		}
+		return timing
+	}
+
+	t.Run("Hostname", func(t *testing.T) {
+		ts := httptest.NewServer(handler)
+		defer ts.Close()
+		u, err := url.Parse(ts.URL)
+		require.NoError(t, err)
+
+		cfg := analyzer.DefaultConfig()
+		cfg.HostConcurrency, cfg.HostRPS = 0, 0
+		a := analyzer.NewHTMLAnalyzerWithConfig(cfg)
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				check(t, a, "http://localhost:"+u.Port()+"/")
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("TLS", func(t *testing.T) {
+		ts := httptest.NewTLSServer(handler)
+		defer ts.Close()
+
+		timing := check(t, trustingAnalyzer(ts), ts.URL)
+		assert.LessOrEqual(t, timing.ConnectMs+timing.TLSMs, timing.TTFBMs)
+	})
+}
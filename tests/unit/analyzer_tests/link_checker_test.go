@@ -6,10 +6,13 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 	"unsafe"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/fuzumoe/urlinsight-backend/internal/analyzer"
@@ -75,3 +78,126 @@ func TestLinkChecker_Run(t *testing.T) {
 		})
 	})
 }
+
+func TestLinkChecker_Redirects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusMovedPermanently)
+		case "/b":
+			http.Redirect(w, r, "/final", http.StatusFound)
+		case "/final", "/direct":
+			w.WriteHeader(http.StatusOK)
+		case "/loop1":
+			http.Redirect(w, r, "/loop2", http.StatusFound)
+		case "/loop2":
+			http.Redirect(w, r, "/loop1", http.StatusFound)
+		case "/long1", "/long2", "/long3", "/long4":
+			next := map[string]string{"/long1": "/long2", "/long2": "/long3", "/long3": "/long4", "/long4": "/final"}
+			http.Redirect(w, r, next[r.URL.Path], http.StatusTemporaryRedirect)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	links := []model.Link{
+		{Href: ts.URL + "/a"},
+		{Href: ts.URL + "/direct"},
+		{Href: ts.URL + "/loop1"},
+		{Href: ts.URL + "/long1"},
+	}
+	links = analyzer.NewLinkChecker(2, 2*time.Second).Run(context.Background(), links)
+
+	t.Run("Chain", func(t *testing.T) {
+		r := links[0].Redirects
+		require.NotNil(t, r)
+		require.Len(t, r.Hops, 2)
+		require.Equal(t, ts.URL+"/a", r.Hops[0].URL)
+		require.Equal(t, http.StatusMovedPermanently, r.Hops[0].StatusCode)
+		require.Equal(t, "/b", r.Hops[0].Location)
+		require.Equal(t, http.StatusFound, r.Hops[1].StatusCode)
+		require.Equal(t, ts.URL+"/final", r.FinalURL)
+		require.Equal(t, http.StatusOK, links[0].StatusCode)
+		require.False(t, r.Loop)
+		require.False(t, r.Excessive)
+	})
+
+	t.Run("No Redirect", func(t *testing.T) {
+		require.Nil(t, links[1].Redirects)
+	})
+
+	t.Run("Loop", func(t *testing.T) {
+		r := links[2].Redirects
+		require.NotNil(t, r)
+		require.True(t, r.Loop)
+		require.Len(t, r.Hops, 2)
+		require.Equal(t, http.StatusFound, links[2].StatusCode, "A loop keeps the last redirect status")
+	})
+
+	t.Run("Excessive", func(t *testing.T) {
+		r := links[3].Redirects
+		require.NotNil(t, r)
+		require.Len(t, r.Hops, 4)
+		require.True(t, r.Excessive)
+		require.Equal(t, http.StatusOK, links[3].StatusCode)
+	})
+}
+
+func TestLinkChecker_Errors(t *testing.T) {
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Second)
+	}))
+	defer slow.Close()
+
+	links := []model.Link{
+		{Href: closedURL + "/gone"},
+		{Href: slow.URL + "/slow"},
+		{Href: "mailto:someone@example.com"},
+	}
+	links = analyzer.NewLinkChecker(3, 200*time.Millisecond).Run(context.Background(), links)
+
+	t.Run("Connection Refused", func(t *testing.T) {
+		require.Equal(t, 0, links[0].StatusCode)
+		require.Equal(t, model.LinkErrRefused, links[0].ErrorCategory)
+		require.NotEmpty(t, links[0].ErrorMessage)
+		require.True(t, links[0].IsBroken())
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		require.Equal(t, model.LinkErrTimeout, links[1].ErrorCategory)
+		require.True(t, links[1].IsBroken())
+	})
+
+	t.Run("Non-HTTP Skipped", func(t *testing.T) {
+		require.Empty(t, links[2].ErrorCategory)
+		require.False(t, links[2].IsBroken())
+	})
+}
+
+func TestLinkChecker_Progress(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	links := make([]model.Link, 7)
+	for i := range links {
+		links[i] = model.Link{Href: ts.URL + "/page"}
+	}
+
+	var mu sync.Mutex
+	var seen []int
+	ctx := analyzer.WithLinkProgress(context.Background(), func(checked, total int) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, 7, total)
+		seen = append(seen, checked)
+	})
+	analyzer.NewLinkChecker(3, time.Second).Run(ctx, links)
+
+	sort.Ints(seen)
+	require.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, seen, "every finished check is reported once")
+}
@@ -0,0 +1,88 @@
+package analyzer_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/analyzer"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+// moduleTestName is a module registered by this package, the way an add-on check would be.
+// It only touches pages carrying a probe paragraph, so the other tests are unaffected.
+const moduleTestName = "test_probe"
+
+func init() {
+	analyzer.Register(analyzer.NewModule(moduleTestName, func(_ context.Context, p *analyzer.Page, res *model.AnalysisResult) {
+		if probe := p.Doc.Find("p.probe"); probe.Length() > 0 {
+			res.Title += " (" + probe.Text() + ")"
+		}
+	}))
+}
+
+func TestHTMLAnalyzer_Modules(t *testing.T) {
+	var linkHits atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`<!DOCTYPE html><html><head><title>Mods</title>
+				<meta name="description" content="Module test"></head>
+				<body><h1>Hi</h1><p class="probe">para</p><a href="/a">a</a><a href="/b">b</a></body></html>`))
+		case "/robots.txt":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			linkHits.Add(1)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	a := analyzer.NewHTMLAnalyzerWithConfig(analyzer.Config{})
+
+	t.Run("All By Default", func(t *testing.T) {
+		linkHits.Store(0)
+		res, links, err := a.Analyze(context.Background(), u)
+		require.NoError(t, err)
+		require.Equal(t, analyzer.ModuleNames(), res.Modules)
+		require.Contains(t, res.Modules, moduleTestName)
+		require.Equal(t, "Mods (para)", res.Title)
+		require.Equal(t, 1, res.H1Count)
+		require.NotNil(t, res.SEO)
+		require.Len(t, links, 2)
+		require.Equal(t, int32(2), linkHits.Load())
+	})
+
+	t.Run("Metadata Only", func(t *testing.T) {
+		linkHits.Store(0)
+		ctx := analyzer.WithModules(context.Background(), []string{analyzer.ModuleSEO, analyzer.ModuleHeadings})
+		res, links, err := a.Analyze(ctx, u)
+		require.NoError(t, err)
+		require.Equal(t, []string{analyzer.ModuleHeadings, analyzer.ModuleSEO}, res.Modules)
+		require.Equal(t, "Mods", res.Title, "fetch metadata is recorded whatever the selection")
+		require.Equal(t, 1, res.H1Count)
+		require.Equal(t, "Module test", res.SEO.MetaDescription)
+		require.Nil(t, res.Accessibility)
+		require.Nil(t, res.Security)
+		require.Empty(t, links)
+		require.Zero(t, res.InternalLinkCount)
+		require.Zero(t, linkHits.Load(), "links should not be checked")
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		require.NoError(t, analyzer.ValidateModules([]string{analyzer.ModuleLinks, moduleTestName}))
+		require.EqualError(t, analyzer.ValidateModules([]string{"spellcheck"}), `unknown analysis module "spellcheck"`)
+	})
+
+	t.Run("Duplicate Registration", func(t *testing.T) {
+		require.Panics(t, func() {
+			analyzer.Register(analyzer.NewModule(analyzer.ModuleSEO, func(context.Context, *analyzer.Page, *model.AnalysisResult) {}))
+		})
+	})
+}
@@ -0,0 +1,191 @@
+package analyzer_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/analyzer"
+)
+
+// linkPage serves a page linking to n sub-paths of the same host.
+func linkPage(n int) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html><html><head><title>Links</title></head><body>")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, `<a href="/p%d">p%d</a>`, i, i)
+	}
+	b.WriteString("</body></html>")
+	return b.String()
+}
+
+func TestHTMLAnalyzer_HostConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(linkPage(6)))
+		case "/robots.txt":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			mu.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mu.Unlock()
+			time.Sleep(30 * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+
+	a := analyzer.NewHTMLAnalyzerWithConfig(analyzer.Config{HostConcurrency: 1})
+	res, links, err := a.Analyze(context.Background(), u)
+	require.NoError(t, err)
+	require.Len(t, links, 6)
+	require.Equal(t, 6, res.InternalLinkCount)
+	require.Equal(t, 1, maxInFlight, "only one request at a time should reach the host")
+}
+
+func TestHTMLAnalyzer_CrawlDelay(t *testing.T) {
+	var mu sync.Mutex
+	var hits []time.Time
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			_, _ = w.Write([]byte("User-agent: *\nCrawl-delay: 0.2\n"))
+			return
+		case "/":
+			_, _ = w.Write([]byte(linkPage(2)))
+		}
+		mu.Lock()
+		hits = append(hits, time.Now())
+		mu.Unlock()
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+
+	// No configured limits: only the robots.txt Crawl-delay spaces the requests out.
+	a := analyzer.NewHTMLAnalyzerWithConfig(analyzer.Config{})
+	_, links, err := a.Analyze(context.Background(), u)
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+
+	require.Len(t, hits, 3, "the page and both links should be fetched")
+	for i := 1; i < len(hits); i++ {
+		require.GreaterOrEqual(t, hits[i].Sub(hits[i-1]), 180*time.Millisecond,
+			"request %d came too soon after the previous one", i)
+	}
+}
+
+func TestHTMLAnalyzer_HostLimitCancelled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(linkPage(5)))
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+
+	// One request per second: the links queue behind the page fetch until the deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	a := analyzer.NewHTMLAnalyzerWithConfig(analyzer.Config{HostRPS: 1})
+	_, links, err := a.Analyze(ctx, u)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	for _, l := range links {
+		require.Zero(t, l.StatusCode, "%s should be left unchecked", l.Href)
+		require.Empty(t, l.ErrorCategory)
+	}
+}
+
+// unexported returns a settable view of v's unexported field name.
+func unexported(t *testing.T, v reflect.Value, name string) reflect.Value {
+	t.Helper()
+	f := v.FieldByName(name)
+	require.True(t, f.IsValid(), "missing field %s", name)
+	return reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+}
+
+func TestHTMLAnalyzer_CrawlDelayLifted(t *testing.T) {
+	var delay atomic.Bool
+	delay.Store(true)
+	var mu sync.Mutex
+	var hits []time.Time
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			if delay.Load() {
+				_, _ = w.Write([]byte("User-agent: *\nCrawl-delay: 0.2\n"))
+			}
+			return
+		case "/":
+			_, _ = w.Write([]byte(linkPage(2)))
+		}
+		mu.Lock()
+		hits = append(hits, time.Now())
+		mu.Unlock()
+	}))
+	defer ts.Close()
+
+	a := analyzer.NewHTMLAnalyzerWithConfig(analyzer.Config{})
+	clock := time.Now()
+	unexported(t, reflect.ValueOf(a).Elem().FieldByName("robots").Elem(), "now").
+		Set(reflect.ValueOf(func() time.Time { return clock }))
+
+	u, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	_, _, err = a.Analyze(context.Background(), u)
+	require.NoError(t, err)
+	require.Len(t, hits, 3)
+	require.GreaterOrEqual(t, hits[2].Sub(hits[0]), 360*time.Millisecond, "the Crawl-delay should apply")
+
+	// robots.txt drops the Crawl-delay; once the cached rules expire the host speeds up again.
+	delay.Store(false)
+	clock = clock.Add(25 * time.Hour)
+	mu.Lock()
+	hits = nil
+	mu.Unlock()
+	_, _, err = a.Analyze(context.Background(), u)
+	require.NoError(t, err)
+	require.Len(t, hits, 3)
+	require.Less(t, hits[2].Sub(hits[0]), 150*time.Millisecond, "the lifted Crawl-delay should no longer apply")
+}
+
+func TestHTMLAnalyzer_HostLimiterBounded(t *testing.T) {
+	a := analyzer.NewHTMLAnalyzerWithConfig(analyzer.Config{HostConcurrency: 1})
+	limit := reflect.ValueOf(a).Elem().FieldByName("limit").Elem()
+	unexported(t, limit, "size").SetInt(2)
+
+	for i := 0; i < 4; i++ {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`<html><head><title>Host</title></head></html>`))
+		}))
+		u, err := url.Parse(ts.URL + "/")
+		require.NoError(t, err)
+		_, _, err = a.Analyze(context.Background(), u)
+		require.NoError(t, err)
+		ts.Close()
+	}
+	require.Equal(t, 2, limit.FieldByName("hosts").Len(), "idle hosts beyond the bound should be forgotten")
+}
@@ -0,0 +1,78 @@
+package analyzer_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/analyzer"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+func TestHTMLAnalyzer_RequestOptions(t *testing.T) {
+	type seen struct{ agent, env, cookie string }
+	var mu sync.Mutex
+	requests := map[string]seen{}
+	record := func(prefix string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			requests[prefix+r.URL.Path] = seen{r.UserAgent(), r.Header.Get("X-Env"), r.Header.Get("Cookie")}
+			mu.Unlock()
+		}
+	}
+
+	other := httptest.NewServer(record("other"))
+	defer other.Close()
+	// Same address, different hostname: requests to it must not carry the URL's credentials.
+	otherURL := strings.Replace(other.URL, "127.0.0.1", "localhost", 1)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			record("page")(w, r)
+			_, _ = w.Write([]byte(`<html><body>
+				<a href="/inner">inner</a>
+				<a href="/away">away</a>
+				<a href="` + otherURL + `/external">external</a>
+			</body></html>`))
+		case "/away":
+			record("page")(w, r)
+			http.Redirect(w, r, otherURL+"/redirected", http.StatusFound)
+		case "/robots.txt":
+			_, _ = w.Write([]byte("User-agent: *\nDisallow:\n"))
+		default:
+			record("page")(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+
+	a := analyzer.NewHTMLAnalyzerWithConfig(analyzer.Config{UserAgent: "TestBot/2.0"})
+	ctx := analyzer.WithRequestOptions(context.Background(), model.RequestOptions{
+		Headers: map[string]string{"X-Env": "staging"},
+		Cookies: map[string]string{"session": "abc"},
+	})
+	_, links, err := a.Analyze(ctx, u)
+	require.NoError(t, err)
+	require.Len(t, links, 3)
+
+	t.Run("Same Host", func(t *testing.T) {
+		for _, path := range []string{"page/", "page/inner", "page/away"} {
+			require.Equal(t, seen{"TestBot/2.0", "staging", "session=abc"}, requests[path], path)
+		}
+	})
+
+	t.Run("Other Host", func(t *testing.T) {
+		for _, path := range []string{"other/external", "other/redirected"} {
+			require.Equal(t, seen{agent: "TestBot/2.0"}, requests[path], path)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package analyzer_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/analyzer"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+// robotsServer serves robots.txt with the status and body returned by robots, and a page
+// linking to /public and /private.
+func robotsServer(t *testing.T, robots func() (int, string)) (*httptest.Server, *atomic.Int32) {
+	var fetches atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			fetches.Add(1)
+			code, body := robots()
+			w.WriteHeader(code)
+			_, _ = w.Write([]byte(body))
+		case "/":
+			_, _ = w.Write([]byte(`<html><body><a href="/public">public</a><a href="/private">private</a></body></html>`))
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &fetches
+}
+
+func analyzeRoot(t *testing.T, a analyzer.Analyzer, ts *httptest.Server) (*model.AnalysisResult, []model.Link) {
+	u, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	res, links, err := a.Analyze(context.Background(), u)
+	require.NoError(t, err)
+	require.NotNil(t, res.Robots)
+	return res, links
+}
+
+func TestHTMLAnalyzer_Robots(t *testing.T) {
+	t.Run("Agent Group", func(t *testing.T) {
+		ts, _ := robotsServer(t, func() (int, string) {
+			return http.StatusOK, "User-agent: TestBot\nDisallow: /private\n\nUser-agent: *\nDisallow:\n\nSitemap: https://example.com/sitemap.xml\n"
+		})
+
+		res, links := analyzeRoot(t, analyzer.NewHTMLAnalyzerWithConfig(analyzer.Config{UserAgent: "TestBot/2.0"}), ts)
+		require.Equal(t, model.RobotsOK, res.Robots.Status)
+		require.False(t, res.Robots.PageDisallowed)
+		require.Equal(t, []string{"https://example.com/sitemap.xml"}, res.Robots.Sitemaps)
+		require.Equal(t, []string{ts.URL + "/private"}, res.Robots.SkippedLinks, "the TestBot group disallows /private")
+		require.Equal(t, http.StatusOK, links[0].StatusCode)
+		require.Zero(t, links[1].StatusCode, "a disallowed link is left unchecked")
+		require.Zero(t, res.BrokenLinkCount, "a disallowed link is not broken")
+
+		res, _ = analyzeRoot(t, analyzer.NewHTMLAnalyzerWithConfig(analyzer.Config{UserAgent: "OtherBot/1.0"}), ts)
+		require.Empty(t, res.Robots.SkippedLinks, "other agents fall back to the * group")
+	})
+
+	t.Run("Page Disallowed", func(t *testing.T) {
+		ts, _ := robotsServer(t, func() (int, string) { return http.StatusOK, "User-agent: *\nDisallow: /\n" })
+
+		res, _ := analyzeRoot(t, analyzer.NewHTMLAnalyzer(), ts)
+		require.True(t, res.Robots.PageDisallowed)
+		require.Len(t, res.Robots.SkippedLinks, 2)
+	})
+
+	t.Run("Client Error Allows All", func(t *testing.T) {
+		ts, _ := robotsServer(t, func() (int, string) { return http.StatusNotFound, "" })
+
+		res, links := analyzeRoot(t, analyzer.NewHTMLAnalyzer(), ts)
+		require.Equal(t, model.RobotsUnavailable, res.Robots.Status)
+		require.Equal(t, http.StatusNotFound, res.Robots.StatusCode)
+		require.False(t, res.Robots.PageDisallowed)
+		require.Empty(t, res.Robots.SkippedLinks)
+		require.NotZero(t, links[1].StatusCode)
+	})
+
+	t.Run("Server Error Disallows All", func(t *testing.T) {
+		ts, _ := robotsServer(t, func() (int, string) { return http.StatusServiceUnavailable, "" })
+
+		res, links := analyzeRoot(t, analyzer.NewHTMLAnalyzer(), ts)
+		require.Equal(t, model.RobotsUnreachable, res.Robots.Status)
+		require.True(t, res.Robots.PageDisallowed)
+		require.Len(t, res.Robots.SkippedLinks, 2)
+		for _, l := range links {
+			require.Zero(t, l.StatusCode)
+		}
+	})
+}
+
+func TestHTMLAnalyzer_RobotsCache(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	ts, fetches := robotsServer(t, func() (int, string) {
+		return int(status.Load()), "User-agent: *\nDisallow: /private\n"
+	})
+
+	a := analyzer.NewHTMLAnalyzerWithConfig(analyzer.Config{})
+	// Drive the cache clock so expiry can be tested without waiting.
+	clock := time.Now()
+	cache := reflect.ValueOf(a).Elem().FieldByName("robots").Elem()
+	nowField := cache.FieldByName("now")
+	require.True(t, nowField.IsValid(), "robots cache must have a now field")
+	reflect.NewAt(nowField.Type(), unsafe.Pointer(nowField.UnsafeAddr())).Elem().
+		Set(reflect.ValueOf(func() time.Time { return clock }))
+
+	res, _ := analyzeRoot(t, a, ts)
+	require.Equal(t, model.RobotsUnreachable, res.Robots.Status)
+	require.Equal(t, int32(1), fetches.Load(), "the page and its links share one robots.txt fetch")
+
+	status.Store(http.StatusOK)
+	res, _ = analyzeRoot(t, a, ts)
+	require.Equal(t, model.RobotsUnreachable, res.Robots.Status, "a server error is remembered for a while")
+	require.Equal(t, int32(1), fetches.Load())
+
+	clock = clock.Add(10 * time.Minute)
+	res, _ = analyzeRoot(t, a, ts)
+	require.Equal(t, model.RobotsOK, res.Robots.Status, "a server error is retried sooner than the full TTL")
+	require.Equal(t, []string{ts.URL + "/private"}, res.Robots.SkippedLinks)
+	require.Equal(t, int32(2), fetches.Load())
+
+	status.Store(http.StatusServiceUnavailable)
+	clock = clock.Add(25 * time.Hour)
+	res, _ = analyzeRoot(t, a, ts)
+	require.Equal(t, int32(3), fetches.Load(), "rules expire after a day")
+	require.Equal(t, model.RobotsOK, res.Robots.Status, "the last good rules outlive a server error")
+}
+
+func TestHTMLAnalyzer_RobotsCancelled(t *testing.T) {
+	var hang atomic.Bool
+	hang.Store(true)
+	ts, fetches := robotsServer(t, func() (int, string) {
+		return http.StatusOK, "User-agent: *\nDisallow: /private\n"
+	})
+	// Hold robots.txt until the client goes away while hang is set.
+	inner := ts.Config.Handler
+	ts.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" && hang.Load() {
+			fetches.Add(1)
+			<-r.Context().Done()
+			return
+		}
+		inner.ServeHTTP(w, r)
+	})
+
+	a := analyzer.NewHTMLAnalyzerWithConfig(analyzer.Config{})
+	u, err := url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, _, err = a.Analyze(ctx, u)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 2*time.Second, "the robots.txt fetch follows the analysis context")
+	require.Equal(t, int32(1), fetches.Load())
+
+	hang.Store(false)
+	res, _ := analyzeRoot(t, a, ts)
+	require.Equal(t, model.RobotsOK, res.Robots.Status, "an abandoned fetch is not cached")
+	require.Equal(t, int32(2), fetches.Load())
+}
@@ -0,0 +1,77 @@
+package analyzer_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/analyzer"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+func TestHTMLAnalyzer_BlockPrivateIPs(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte(`<html><body>
+				<a href="/ok">ok</a>
+				<a href="/bounce">bounce</a>
+				<a href="http://169.254.169.254/latest/meta-data/">metadata</a>
+			</body></html>`))
+		case "/bounce":
+			// Leaves the allowlisted hostname for the same server by IP address.
+			http.Redirect(w, r, ts.URL+"/ok", http.StatusFound)
+		}
+	}))
+	defer ts.Close()
+	viaLocalhost := strings.Replace(ts.URL, "127.0.0.1", "localhost", 1)
+
+	analyze := func(cfg analyzer.Config, raw string) (*model.AnalysisResult, []model.Link, error) {
+		u, err := url.Parse(raw)
+		require.NoError(t, err)
+		cfg.BlockPrivateIPs = true
+		return analyzer.NewHTMLAnalyzerWithConfig(cfg).Analyze(context.Background(), u)
+	}
+
+	t.Run("Loopback Blocked", func(t *testing.T) {
+		_, _, err := analyze(analyzer.Config{}, ts.URL+"/")
+		require.ErrorIs(t, err, analyzer.ErrBlockedAddress)
+	})
+
+	t.Run("Blocked After DNS Resolution", func(t *testing.T) {
+		_, _, err := analyze(analyzer.Config{}, viaLocalhost+"/")
+		require.ErrorIs(t, err, analyzer.ErrBlockedAddress, "localhost resolves to a loopback address")
+	})
+
+	t.Run("Allowed By Range", func(t *testing.T) {
+		_, links, err := analyze(analyzer.Config{AllowedHosts: []string{"127.0.0.0/8"}}, ts.URL+"/")
+		require.NoError(t, err)
+		require.Len(t, links, 3)
+		require.Equal(t, http.StatusOK, links[0].StatusCode)
+		require.Equal(t, http.StatusOK, links[1].StatusCode)
+		require.Equal(t, model.LinkErrBlocked, links[2].ErrorCategory, "the metadata service stays blocked")
+	})
+
+	t.Run("Allowed By Hostname", func(t *testing.T) {
+		res, links, err := analyze(analyzer.Config{AllowedHosts: []string{"LOCALHOST"}}, viaLocalhost+"/")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, links[0].StatusCode)
+		require.Zero(t, links[1].StatusCode)
+		require.Equal(t, model.LinkErrBlocked, links[1].ErrorCategory, "a redirect to an unlisted address is blocked")
+		require.Equal(t, model.LinkErrBlocked, links[2].ErrorCategory)
+		require.Equal(t, 2, res.ErrorLinkCount)
+	})
+
+	t.Run("Guard Off", func(t *testing.T) {
+		u, err := url.Parse(ts.URL + "/")
+		require.NoError(t, err)
+		_, _, err = analyzer.NewHTMLAnalyzerWithConfig(analyzer.Config{}).Analyze(context.Background(), u)
+		require.NoError(t, err)
+	})
+}
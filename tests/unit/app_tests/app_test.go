@@ -35,9 +35,10 @@ type MockCrawlerPool struct{}
 func (m *MockCrawlerPool) Start(ctx context.Context) {
 	// Do nothing in tests - don't block
 }
-func (m *MockCrawlerPool) Shutdown()       {}
-func (m *MockCrawlerPool) Submit(id uint)  {} // backward compatibility
-func (m *MockCrawlerPool) Enqueue(id uint) {}
+func (m *MockCrawlerPool) Shutdown()                             {}
+func (m *MockCrawlerPool) Submit(id uint)                        {} // backward compatibility
+func (m *MockCrawlerPool) Enqueue(id uint) error                 { return nil }
+func (m *MockCrawlerPool) Cancel(id uint, keepPartial bool) bool { return false }
 
 // setupHooks applies all patches so app.Run never starts a real server.
 func setupHooks(t *testing.T) {
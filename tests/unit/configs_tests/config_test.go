@@ -28,6 +28,19 @@ func TestLoad(t *testing.T) {
 		os.Setenv("MAX_CONCURRENT_CRAWLS", "10")
 		os.Setenv("CRAWL_TIMEOUT_SECONDS", "45")
 		os.Setenv("USER_AGENT", "TestAgent/2.0")
+		os.Setenv("SITE_CRAWL_MAX_DEPTH", "4")
+		os.Setenv("SITE_CRAWL_MAX_PAGES", "250")
+		os.Setenv("CRAWL_HOST_CONCURRENCY", "3")
+		os.Setenv("CRAWL_HOST_RPS", "0.5")
+		os.Setenv("CRAWL_MAX_BODY_BYTES", "1048576")
+		os.Setenv("CRAWL_BLOCK_PRIVATE_NETWORKS", "false")
+		os.Setenv("CRAWL_ALLOWED_HOSTS", "staging.internal, 10.0.0.0/8")
+		os.Setenv("QUEUE_LEASE_SECONDS", "90")
+		os.Setenv("QUEUE_POLL_INTERVAL_MS", "500")
+		os.Setenv("QUEUE_MAX_ATTEMPTS", "5")
+		os.Setenv("SCHEDULER_TICK_SECONDS", "15")
+		os.Setenv("WEBHOOK_MAX_ATTEMPTS", "8")
+		os.Setenv("WEBHOOK_TIMEOUT_SECONDS", "3")
 
 		cfg, err := configs.Load()
 		assert.NoError(t, err)
@@ -38,6 +51,19 @@ func TestLoad(t *testing.T) {
 		assert.Equal(t, 10, cfg.MaxConcurrentCrawls)
 		assert.Equal(t, 45*time.Second, cfg.CrawlTimeout)
 		assert.Equal(t, "TestAgent/2.0", cfg.UserAgent)
+		assert.Equal(t, 4, cfg.SiteCrawlMaxDepth)
+		assert.Equal(t, 250, cfg.SiteCrawlMaxPages)
+		assert.Equal(t, 3, cfg.HostConcurrency)
+		assert.Equal(t, 0.5, cfg.HostRPS)
+		assert.Equal(t, int64(1048576), cfg.MaxBodyBytes)
+		assert.False(t, cfg.BlockPrivateIPs)
+		assert.Equal(t, []string{"staging.internal", "10.0.0.0/8"}, cfg.AllowedHosts)
+		assert.Equal(t, 90*time.Second, cfg.QueueLease)
+		assert.Equal(t, 500*time.Millisecond, cfg.QueuePollInterval)
+		assert.Equal(t, 5, cfg.QueueMaxAttempts)
+		assert.Equal(t, 15*time.Second, cfg.SchedulerTick)
+		assert.Equal(t, 8, cfg.WebhookMaxAttempts)
+		assert.Equal(t, 3*time.Second, cfg.WebhookTimeout)
 		assert.Equal(t, "debug", cfg.LogLevel)
 		assert.Equal(t, "secret", cfg.JWTSecret)
 		assert.Equal(t, 48*time.Hour, cfg.JWTLifetime)
@@ -73,4 +99,52 @@ func TestLoad(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "invalid JWT_LIFETIME")
 	})
+
+	t.Run("NetworkGuardDefaults", func(t *testing.T) {
+		os.Clearenv()
+		os.Setenv("DB_USER", "u")
+		os.Setenv("DB_PASSWORD", "p")
+		os.Setenv("DB_NAME", "n")
+		os.Setenv("JWT_SECRET", "s")
+		cfg, err := configs.Load()
+		assert.NoError(t, err)
+		assert.True(t, cfg.BlockPrivateIPs, "private networks should be blocked by default")
+		assert.Empty(t, cfg.AllowedHosts)
+	})
+
+	t.Run("InvalidMaxBodyBytes", func(t *testing.T) {
+		os.Clearenv()
+		os.Setenv("DB_USER", "u")
+		os.Setenv("DB_PASSWORD", "p")
+		os.Setenv("DB_NAME", "n")
+		os.Setenv("JWT_SECRET", "s")
+		os.Setenv("CRAWL_MAX_BODY_BYTES", "5MB")
+		_, err := configs.Load()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid CRAWL_MAX_BODY_BYTES")
+	})
+
+	t.Run("InvalidWebhookTimeout", func(t *testing.T) {
+		os.Clearenv()
+		os.Setenv("DB_USER", "u")
+		os.Setenv("DB_PASSWORD", "p")
+		os.Setenv("DB_NAME", "n")
+		os.Setenv("JWT_SECRET", "s")
+		os.Setenv("WEBHOOK_TIMEOUT_SECONDS", "10s")
+		_, err := configs.Load()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid WEBHOOK_TIMEOUT_SECONDS")
+	})
+
+	t.Run("InvalidHostRPS", func(t *testing.T) {
+		os.Clearenv()
+		os.Setenv("DB_USER", "u")
+		os.Setenv("DB_PASSWORD", "p")
+		os.Setenv("DB_NAME", "n")
+		os.Setenv("JWT_SECRET", "s")
+		os.Setenv("CRAWL_HOST_RPS", "fast")
+		_, err := configs.Load()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid CRAWL_HOST_RPS")
+	})
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/fuzumoe/urlinsight-backend/internal/analyzer"
 	"github.com/fuzumoe/urlinsight-backend/internal/crawler"
 	"github.com/fuzumoe/urlinsight-backend/internal/model"
 	"github.com/fuzumoe/urlinsight-backend/internal/repository"
@@ -51,6 +52,10 @@ func (r *mockPRepo) FindByID(id uint) (*model.URL, error) {
 	}, nil
 }
 
+func (r *mockPRepo) Status(id uint) (string, error) {
+	return model.StatusQueued, nil
+}
+
 func (r *mockPRepo) SaveResults(id uint, res *model.AnalysisResult, links []model.Link) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -58,6 +63,13 @@ func (r *mockPRepo) SaveResults(id uint, res *model.AnalysisResult, links []mode
 	return nil
 }
 
+func (r *mockPRepo) SaveSiteResults(id uint, pages []model.PageResult, summary *model.SiteSummary) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saveResultsCalled = true
+	return nil
+}
+
 // Stub implementations for the rest of URLRepository.
 func (r *mockPRepo) Create(u *model.URL) error { return nil }
 func (r *mockPRepo) Delete(id uint) error      { return nil }
@@ -68,10 +80,18 @@ func (r *mockPRepo) Update(u *model.URL) error { return nil }
 func (r *mockPRepo) Results(id uint) (*model.URL, error) {
 	return &model.URL{OriginalURL: "http://example.com"}, nil
 }
-func (r *mockPRepo) ResultsWithDetails(id uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error) {
+func (r *mockPRepo) ResultsWithDetails(id, snapshotID uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error) {
 	return &model.URL{OriginalURL: "http://example.com/details"}, []*model.AnalysisResult{}, []*model.Link{}, nil
 }
 
+func (r *mockPRepo) IDsByUser(userID uint, f model.URLFilter, limit int) ([]uint, error) {
+	return nil, nil
+}
+
+func (r *mockPRepo) SiteSummary(id, snapshotID uint) (*model.SiteSummary, error) {
+	return &model.SiteSummary{URLID: id}, nil
+}
+
 // mockPAnalyzer implements analyzer.Analyzer for testing.
 type mockPAnalyzer struct{}
 
@@ -139,3 +159,176 @@ func TestPool_ProcessTasks(t *testing.T) {
 		assert.True(t, mockRepo.saveResultsCalled, "Expected SaveResults to be called")
 	})
 }
+
+// blockingAnalyzer blocks until its context is cancelled and then returns a partial result.
+type blockingAnalyzer struct {
+	started chan struct{}
+}
+
+func (a *blockingAnalyzer) Analyze(ctx context.Context, u *url.URL) (*model.AnalysisResult, []model.Link, error) {
+	close(a.started)
+	<-ctx.Done()
+	return &model.AnalysisResult{Title: "partial"}, []model.Link{{Href: "http://example.com/a"}}, ctx.Err()
+}
+
+func TestPool_Cancel(t *testing.T) {
+	run := func(t *testing.T, keepPartial bool) *mockPRepo {
+		repo := newMockPRepo()
+		anal := &blockingAnalyzer{started: make(chan struct{})}
+		p := crawler.New(repo, anal, 1, 1, time.Minute)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go p.Start(ctx)
+
+		assert.False(t, p.Cancel(7, keepPartial), "Cancel should report false when nothing is running")
+
+		require.NoError(t, p.Enqueue(7))
+		select {
+		case <-anal.started:
+		case <-time.After(2 * time.Second):
+			t.Fatal("analysis never started")
+		}
+		require.True(t, p.Cancel(7, keepPartial), "Cancel should find the running analysis")
+
+		require.Eventually(t, func() bool {
+			repo.mu.Lock()
+			defer repo.mu.Unlock()
+			st := repo.statusUpdates[7]
+			return len(st) > 0 && st[len(st)-1] == model.StatusStopped
+		}, 2*time.Second, 10*time.Millisecond, "a cancelled analysis should end as stopped")
+		return repo
+	}
+
+	t.Run("Discards Partial Results", func(t *testing.T) {
+		repo := run(t, false)
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		assert.False(t, repo.saveResultsCalled, "partial results should be discarded by default")
+	})
+
+	t.Run("Keeps Partial Results", func(t *testing.T) {
+		repo := run(t, true)
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		assert.True(t, repo.saveResultsCalled, "partial results should be saved when requested")
+	})
+}
+
+// recordingNotifier collects the analysis events a pool reports.
+type recordingNotifier struct {
+	events chan model.AnalysisEvent
+}
+
+func (n *recordingNotifier) AnalysisFinished(e model.AnalysisEvent) {
+	n.events <- e
+}
+
+func TestPool_Notifier(t *testing.T) {
+	run := func(t *testing.T, anal analyzer.Analyzer) model.AnalysisEvent {
+		n := &recordingNotifier{events: make(chan model.AnalysisEvent, 1)}
+		p := crawler.NewWithOptions(newTestRepo(), anal, 1, 1, time.Minute, crawler.Options{Notifier: n})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go p.Start(ctx)
+		require.NoError(t, p.Enqueue(11))
+
+		select {
+		case e := <-n.events:
+			return e
+		case <-time.After(2 * time.Second):
+			t.Fatal("no analysis event reported")
+		}
+		return model.AnalysisEvent{}
+	}
+
+	t.Run("Done", func(t *testing.T) {
+		e := run(t, &dummyAnalyzer{})
+		assert.Equal(t, model.StatusDone, e.Status)
+		assert.Equal(t, uint(11), e.URLID)
+		assert.Equal(t, "http://example.com", e.OriginalURL)
+		assert.Equal(t, 1, e.PagesCrawled)
+		assert.Empty(t, e.Error)
+		assert.False(t, e.FinishedAt.IsZero())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		e := run(t, &dummyAnalyzer{shouldError: true})
+		assert.Equal(t, model.StatusError, e.Status)
+		assert.Equal(t, "analyze error", e.Error)
+	})
+}
+
+// recordingReporter collects the progress events a pool reports.
+type recordingReporter struct {
+	mu     sync.Mutex
+	events []model.ProgressEvent
+}
+
+func (r *recordingReporter) Report(e model.ProgressEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *recordingReporter) statuses() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []string
+	for _, e := range r.events {
+		if e.Type == model.ProgressStatus {
+			out = append(out, e.Status)
+		}
+	}
+	return out
+}
+
+func TestPool_Progress(t *testing.T) {
+	run := func(t *testing.T, repo *testRepo, anal analyzer.Analyzer, id uint) *recordingReporter {
+		r := &recordingReporter{}
+		p := crawler.NewWithOptions(repo, anal, 1, 1, time.Minute, crawler.Options{Progress: r})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go p.Start(ctx)
+		require.NoError(t, p.Enqueue(id))
+
+		require.Eventually(t, func() bool {
+			st := r.statuses()
+			return len(st) > 0 && st[len(st)-1] != model.StatusQueued && st[len(st)-1] != model.StatusRunning
+		}, 2*time.Second, 10*time.Millisecond, "the analysis never finished")
+		return r
+	}
+
+	t.Run("Status Transitions", func(t *testing.T) {
+		r := run(t, newTestRepo(), &dummyAnalyzer{}, 12)
+		assert.Equal(t, []string{model.StatusQueued, model.StatusRunning, model.StatusDone}, r.statuses())
+		for _, e := range r.events {
+			assert.Equal(t, uint(12), e.URLID)
+			assert.False(t, e.At.IsZero())
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		r := run(t, newTestRepo(), &dummyAnalyzer{shouldError: true}, 13)
+		assert.Equal(t, []string{model.StatusQueued, model.StatusRunning, model.StatusError}, r.statuses())
+		assert.Equal(t, "analyze error", r.events[len(r.events)-1].Error)
+	})
+
+	t.Run("Site Crawl Pages", func(t *testing.T) {
+		repo := newTestRepo()
+		repo.crawlModes[14] = model.CrawlModeSite
+		r := run(t, repo, &siteAnalyzer{}, 14)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		var pages []int
+		for _, e := range r.events {
+			if e.Type == model.ProgressUpdate {
+				pages = append(pages, e.PagesCrawled)
+			}
+		}
+		assert.Equal(t, []int{1, 2}, pages)
+	})
+}
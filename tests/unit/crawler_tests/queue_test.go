@@ -0,0 +1,231 @@
+package crawler_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/crawler"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+// fakeJobRepo implements repository.CrawlJobRepository in memory.
+type fakeJobRepo struct {
+	mu        sync.Mutex
+	pending   []model.CrawlJob
+	nextID    uint
+	completed []uint
+	released  []uint
+	extended  []uint
+	owners    map[uint]string
+	recovered int
+	claimErr  error
+}
+
+func newFakeJobRepo() *fakeJobRepo {
+	return &fakeJobRepo{owners: make(map[uint]string)}
+}
+
+func (r *fakeJobRepo) Enqueue(urlID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	r.pending = append(r.pending, model.CrawlJob{ID: r.nextID, URLID: urlID, Status: model.JobPending})
+	return nil
+}
+
+func (r *fakeJobRepo) Claim(owner string, lease time.Duration, maxAttempts int) (*model.CrawlJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.claimErr != nil {
+		return nil, r.claimErr
+	}
+	if len(r.pending) == 0 {
+		return nil, nil
+	}
+	job := r.pending[0]
+	r.pending = r.pending[1:]
+	job.Status = model.JobLeased
+	job.LeaseOwner = owner
+	r.owners[job.ID] = owner
+	return &job, nil
+}
+
+func (r *fakeJobRepo) Extend(jobID uint, owner string, lease time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.extended = append(r.extended, jobID)
+	return nil
+}
+
+func (r *fakeJobRepo) Complete(jobID uint, owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.completed = append(r.completed, jobID)
+	return nil
+}
+
+func (r *fakeJobRepo) Release(jobID uint, owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.released = append(r.released, jobID)
+	return nil
+}
+
+func (r *fakeJobRepo) Recover(maxAttempts int) (int, error) {
+	return r.recovered, nil
+}
+
+func TestMemoryQueue(t *testing.T) {
+	t.Run("Push And Pop", func(t *testing.T) {
+		q := crawler.NewMemoryQueue(2)
+		require.NoError(t, q.Push(7))
+
+		job, err := q.Pop(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint(7), job.URLID)
+		assert.NoError(t, q.Ack(job))
+	})
+
+	t.Run("Full", func(t *testing.T) {
+		q := crawler.NewMemoryQueue(1)
+		require.NoError(t, q.Push(1))
+		assert.ErrorIs(t, q.Push(2), crawler.ErrQueueFull)
+	})
+
+	t.Run("Pop Honours Context", func(t *testing.T) {
+		q := crawler.NewMemoryQueue(1)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := q.Pop(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		q := crawler.NewMemoryQueue(1)
+		q.(interface{ Close() }).Close()
+
+		assert.ErrorIs(t, q.Push(1), crawler.ErrQueueClosed)
+		_, err := q.Pop(context.Background())
+		assert.ErrorIs(t, err, crawler.ErrQueueClosed)
+	})
+}
+
+func TestDBQueue(t *testing.T) {
+	t.Run("Push Pop Ack", func(t *testing.T) {
+		repo := newFakeJobRepo()
+		q := crawler.NewDBQueue(repo, time.Minute, 10*time.Millisecond, 3)
+
+		require.NoError(t, q.Push(42))
+		job, err := q.Pop(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), job.ID)
+		assert.Equal(t, uint(42), job.URLID)
+
+		require.NoError(t, q.Ack(job))
+		assert.Equal(t, []uint{1}, repo.completed)
+	})
+
+	t.Run("Release", func(t *testing.T) {
+		repo := newFakeJobRepo()
+		q := crawler.NewDBQueue(repo, time.Minute, 10*time.Millisecond, 3)
+
+		require.NoError(t, q.Push(5))
+		job, err := q.Pop(context.Background())
+		require.NoError(t, err)
+
+		require.NoError(t, q.Release(job))
+		assert.Equal(t, []uint{job.ID}, repo.released)
+	})
+
+	t.Run("Pop Waits For Push", func(t *testing.T) {
+		repo := newFakeJobRepo()
+		q := crawler.NewDBQueue(repo, time.Minute, time.Hour, 3)
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			_ = q.Push(9)
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		job, err := q.Pop(ctx)
+		require.NoError(t, err, "Push should wake a blocked Pop without waiting for the poll interval")
+		assert.Equal(t, uint(9), job.URLID)
+	})
+
+	t.Run("Pop Claim Error", func(t *testing.T) {
+		repo := newFakeJobRepo()
+		repo.claimErr = errors.New("db down")
+		q := crawler.NewDBQueue(repo, time.Minute, 10*time.Millisecond, 3)
+
+		_, err := q.Pop(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "db down")
+	})
+}
+
+func TestPool_DBQueue(t *testing.T) {
+	t.Run("Acks Processed Jobs", func(t *testing.T) {
+		jobs := newFakeJobRepo()
+		repo := newMockPRepo()
+		p := crawler.NewWithOptions(repo, &mockPAnalyzer{}, 1, 1, time.Second, crawler.Options{
+			Queue: crawler.NewDBQueue(jobs, time.Minute, 10*time.Millisecond, 3),
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			p.Start(ctx)
+			close(done)
+		}()
+
+		require.NoError(t, p.Enqueue(11))
+		require.Eventually(t, func() bool {
+			jobs.mu.Lock()
+			defer jobs.mu.Unlock()
+			return len(jobs.completed) == 1
+		}, 2*time.Second, 10*time.Millisecond, "the processed job should be acknowledged")
+
+		cancel()
+		<-done
+		assert.Empty(t, jobs.released, "finished jobs should not be released on shutdown")
+	})
+	t.Run("Releases Jobs Interrupted By Shutdown", func(t *testing.T) {
+		jobs := newFakeJobRepo()
+		repo := newMockPRepo()
+		anal := &blockingAnalyzer{started: make(chan struct{})}
+		n := &recordingNotifier{events: make(chan model.AnalysisEvent, 1)}
+		p := crawler.NewWithOptions(repo, anal, 1, 1, time.Minute, crawler.Options{
+			Queue:    crawler.NewDBQueue(jobs, time.Minute, 10*time.Millisecond, 3),
+			Notifier: n,
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			p.Start(ctx)
+			close(done)
+		}()
+
+		require.NoError(t, p.Enqueue(11))
+		select {
+		case <-anal.started:
+		case <-time.After(2 * time.Second):
+			t.Fatal("analysis never started")
+		}
+		cancel()
+		<-done
+
+		assert.Equal(t, []uint{1}, jobs.released, "the interrupted job should be released")
+		assert.Empty(t, jobs.completed)
+		assert.Equal(t, []string{model.StatusRunning}, repo.statusUpdates[11], "a shutdown is not a stop")
+		assert.Empty(t, n.events, "a shutdown should not be reported as the end of the analysis")
+	})
+}
@@ -3,7 +3,11 @@ package crawler_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -11,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/fuzumoe/urlinsight-backend/internal/analyzer"
 	"github.com/fuzumoe/urlinsight-backend/internal/crawler"
 	"github.com/fuzumoe/urlinsight-backend/internal/model"
 	"github.com/fuzumoe/urlinsight-backend/internal/repository"
@@ -23,6 +28,12 @@ type testRepo struct {
 	findByIDCalls     []uint
 	saveResultsCalled bool
 	urlStatus         map[uint]string
+	crawlModes        map[uint]string
+	originalURL       string // served by FindByID; http://example.com when empty
+	savedResult       *model.AnalysisResult
+	savedLinks        []model.Link
+	savedPages        []model.PageResult
+	savedSummary      *model.SiteSummary
 }
 
 // CountByUser implements repository.URLRepository.
@@ -34,6 +45,7 @@ func newTestRepo() *testRepo {
 	return &testRepo{
 		statusUpdates: make(map[uint][]string),
 		urlStatus:     make(map[uint]string),
+		crawlModes:    make(map[uint]string),
 	}
 }
 
@@ -53,20 +65,51 @@ func (r *testRepo) FindByID(id uint) (*model.URL, error) {
 	if !ok {
 		st = model.StatusQueued
 	}
+	original := r.originalURL
+	if original == "" {
+		original = "http://example.com"
+	}
 	return &model.URL{
 		ID:          id,
-		OriginalURL: "http://example.com",
+		OriginalURL: original,
 		Status:      st,
+		CrawlMode:   r.crawlModes[id],
+		MaxDepth:    1,
 	}, nil
 }
 
+func (r *testRepo) Status(id uint) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if st, ok := r.urlStatus[id]; ok {
+		return st, nil
+	}
+	return model.StatusQueued, nil
+}
+
 func (r *testRepo) SaveResults(id uint, res *model.AnalysisResult, links []model.Link) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.saveResultsCalled = true
+	r.savedResult, r.savedLinks = res, links
 	return nil
 }
 
+func (r *testRepo) SaveSiteResults(id uint, pages []model.PageResult, summary *model.SiteSummary) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saveResultsCalled = true
+	r.savedPages = pages
+	r.savedSummary = summary
+	return nil
+}
+
+func (r *testRepo) SiteSummary(id, snapshotID uint) (*model.SiteSummary, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.savedSummary, nil
+}
+
 // Stub implementations for the rest of the URLRepository interface.
 func (r *testRepo) Create(u *model.URL) error { return nil }
 func (r *testRepo) Delete(id uint) error      { return nil }
@@ -74,6 +117,9 @@ func (r *testRepo) ListByUser(userID uint, p repository.Pagination) ([]model.URL
 	return []model.URL{}, nil
 }
 func (r *testRepo) Update(u *model.URL) error { return nil }
+func (r *testRepo) IDsByUser(userID uint, f model.URLFilter, limit int) ([]uint, error) {
+	return nil, nil
+}
 func (r *testRepo) Results(id uint) (*model.URL, error) {
 	return &model.URL{
 		ID:          id,
@@ -81,7 +127,7 @@ func (r *testRepo) Results(id uint) (*model.URL, error) {
 		Status:      model.StatusDone,
 	}, nil
 }
-func (r *testRepo) ResultsWithDetails(id uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error) {
+func (r *testRepo) ResultsWithDetails(id, snapshotID uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error) {
 	return &model.URL{
 		ID:          id,
 		OriginalURL: "http://example.com/details",
@@ -109,6 +155,14 @@ func (a *dummyAnalyzer) Analyze(ctx context.Context, u *url.URL) (*model.Analysi
 	return res, links, nil
 }
 
+// untrustedAnalyzer fails every page with ErrUntrustedCertificate and its TLS report.
+type untrustedAnalyzer struct{}
+
+func (a *untrustedAnalyzer) Analyze(ctx context.Context, u *url.URL) (*model.AnalysisResult, []model.Link, error) {
+	return &model.AnalysisResult{TLS: &model.TLSReport{VerifyError: "x509: certificate has expired"}},
+		nil, fmt.Errorf("%w: x509: certificate has expired", analyzer.ErrUntrustedCertificate)
+}
+
 // cancelAnalyzer always returns context.Canceled.
 type cancelAnalyzer struct{}
 
@@ -116,6 +170,43 @@ func (a *cancelAnalyzer) Analyze(ctx context.Context, u *url.URL) (*model.Analys
 	return nil, nil, context.Canceled
 }
 
+// siteAnalyzer serves a tiny three-level site plus one external link, one page
+// disallowed by robots.txt and one document that is not HTML.
+type siteAnalyzer struct{}
+
+func (a *siteAnalyzer) Analyze(ctx context.Context, u *url.URL) (*model.AnalysisResult, []model.Link, error) {
+	if u.Path == "/report.pdf" {
+		return nil, nil, fmt.Errorf("%w: application/pdf", analyzer.ErrUnsupportedContentType)
+	}
+	res := &model.AnalysisResult{HTMLVersion: "HTML 5", Title: u.Path}
+	var links []model.Link
+	switch u.Path {
+	case "":
+		links = []model.Link{
+			{Href: "http://example.com/a", StatusCode: 200},
+			{Href: "http://example.com/a#top", StatusCode: 200},
+			{Href: "http://example.com/broken", StatusCode: 404},
+			{Href: "http://other.com/", IsExternal: true, StatusCode: 200},
+			{Href: "http://example.com/private"},
+			{Href: "http://example.com/report.pdf", StatusCode: 200},
+		}
+		res.Robots = &model.RobotsReport{Status: model.RobotsOK, SkippedLinks: []string{"http://example.com/private"}}
+	case "/a":
+		links = []model.Link{{Href: "http://example.com/deep", StatusCode: 200}}
+	}
+	for _, l := range links {
+		if l.IsExternal {
+			res.ExternalLinkCount++
+		} else {
+			res.InternalLinkCount++
+		}
+		if l.StatusCode >= 400 {
+			res.BrokenLinkCount++
+		}
+	}
+	return res, links, nil
+}
+
 // TestWorkerSuite groups all worker tests as subtests.
 func TestWorkerSuite(t *testing.T) {
 	t.Run("Process_Success", func(t *testing.T) {
@@ -141,6 +232,39 @@ func TestWorkerSuite(t *testing.T) {
 		assert.GreaterOrEqual(t, len(repo.findByIDCalls), 1, "Expected FindByID to be called at least once")
 	})
 
+	t.Run("Process_SiteCrawl", func(t *testing.T) {
+		repo := newTestRepo()
+		repo.crawlModes[5] = model.CrawlModeSite
+		require.NoError(t, repo.UpdateStatus(5, model.StatusQueued))
+
+		worker := crawler.NewWorker(1, context.Background(), repo, &siteAnalyzer{}, 1*time.Second)
+		tasks := make(chan uint, 1)
+		tasks <- 5
+		close(tasks)
+		worker.Run(tasks)
+
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		statuses := repo.statusUpdates[5]
+		assert.Equal(t, model.StatusDone, statuses[len(statuses)-1], "Final status should be Done")
+
+		// The root and /a are crawled; /deep is beyond MaxDepth and broken, external and
+		// robots-disallowed links are not followed. The PDF is skipped without counting as failed.
+		require.Len(t, repo.savedPages, 2)
+		assert.Equal(t, "http://example.com", repo.savedPages[0].Result.PageURL)
+		assert.Equal(t, 0, repo.savedPages[0].Result.Depth)
+		assert.Equal(t, "http://example.com/a", repo.savedPages[1].Result.PageURL)
+		assert.Equal(t, 1, repo.savedPages[1].Result.Depth)
+
+		require.NotNil(t, repo.savedSummary)
+		assert.Equal(t, 2, repo.savedSummary.PagesCrawled)
+		assert.Equal(t, 1, repo.savedSummary.MaxDepthReached)
+		assert.Equal(t, 0, repo.savedSummary.PagesFailed)
+		assert.Equal(t, 6, repo.savedSummary.InternalLinkCount)
+		assert.Equal(t, 1, repo.savedSummary.ExternalLinkCount)
+		assert.Equal(t, 1, repo.savedSummary.BrokenLinkCount)
+	})
+
 	t.Run("Process_AbortsIfStopped", func(t *testing.T) {
 		ctx := context.Background()
 		repo := newTestRepo()
@@ -190,6 +314,70 @@ func TestWorkerSuite(t *testing.T) {
 		assert.False(t, repo.saveResultsCalled, "SaveResults should not be called on error")
 	})
 
+	t.Run("Process_UntrustedCertificate", func(t *testing.T) {
+		repo := newTestRepo()
+		worker := crawler.NewWorker(5, context.Background(), repo, &untrustedAnalyzer{}, time.Second)
+		tasks := make(chan uint, 1)
+		tasks <- 5
+		close(tasks)
+		worker.Run(tasks)
+
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		statuses := repo.statusUpdates[5]
+		require.NotEmpty(t, statuses)
+		assert.Equal(t, model.StatusError, statuses[len(statuses)-1])
+		assert.True(t, repo.saveResultsCalled, "The certificate report should be saved")
+	})
+
+	t.Run("Process_TimeoutWhileCheckingLinks", func(t *testing.T) {
+		// 60 same-host links at 20 requests per second outlast the crawl timeout.
+		var page strings.Builder
+		page.WriteString("<html><body>")
+		for i := 0; i < 60; i++ {
+			fmt.Fprintf(&page, `<a href="/l/%d">%d</a>`, i, i)
+		}
+		page.WriteString("</body></html>")
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/":
+				_, _ = w.Write([]byte(page.String()))
+			case "/robots.txt":
+				http.NotFound(w, r)
+			}
+		}))
+		defer ts.Close()
+
+		cfg := analyzer.DefaultConfig()
+		cfg.HostRPS = 20
+		repo := newTestRepo()
+		repo.originalURL = ts.URL + "/"
+		worker := crawler.NewWorker(6, context.Background(), repo, analyzer.NewHTMLAnalyzerWithConfig(cfg), 500*time.Millisecond)
+		tasks := make(chan uint, 1)
+		tasks <- 6
+		close(tasks)
+		worker.Run(tasks)
+
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		statuses := repo.statusUpdates[6]
+		require.NotEmpty(t, statuses)
+		assert.Equal(t, model.StatusDone, statuses[len(statuses)-1], "a page fetched in time is not stopped by slow link checks")
+		require.NotNil(t, repo.savedResult)
+		assert.True(t, repo.savedResult.LinksIncomplete)
+		assert.Zero(t, repo.savedResult.BrokenLinkCount, "unchecked links are not broken")
+		require.Len(t, repo.savedLinks, 60)
+		checked := 0
+		for _, l := range repo.savedLinks {
+			assert.Empty(t, l.ErrorCategory, l.Href)
+			if l.StatusCode == http.StatusOK {
+				checked++
+			}
+		}
+		assert.Greater(t, checked, 0)
+		assert.Less(t, checked, 60)
+	})
+
 	t.Run("Run", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
@@ -0,0 +1,109 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/export"
+)
+
+var at = time.Date(2025, 7, 10, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+// writeTable writes a small table in format and returns the output.
+func writeTable(t *testing.T, format string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := export.NewWriter(format, &buf)
+	require.NoError(t, err)
+	require.NoError(t, w.Header([]string{"id", "title", "external", "checked_at", "expires_at"}))
+	require.NoError(t, w.Row([]any{uint(1), "Home <& co>", true, at, (*time.Time)(nil)}))
+	require.NoError(t, w.Row([]any{int64(2), "=HYPERLINK(\"x\")", false, at, &at}))
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestNewWriter(t *testing.T) {
+	_, err := export.NewWriter("pdf", io.Discard)
+	assert.EqualError(t, err, `unsupported export format "pdf"`)
+
+	for _, f := range export.Formats {
+		assert.True(t, export.ValidFormat(f))
+		assert.NotEmpty(t, export.ContentType(f))
+	}
+	assert.False(t, export.ValidFormat("json"))
+}
+
+func TestCSV(t *testing.T) {
+	recs, err := csv.NewReader(bytes.NewReader(writeTable(t, export.CSV))).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"id", "title", "external", "checked_at", "expires_at"},
+		{"1", "Home <& co>", "true", "2025-07-10T10:00:00Z", ""},
+		{"2", `'=HYPERLINK("x")`, "false", "2025-07-10T10:00:00Z", "2025-07-10T10:00:00Z"},
+	}, recs)
+}
+
+func TestNDJSON(t *testing.T) {
+	lines := strings.Split(strings.TrimSuffix(string(writeTable(t, export.NDJSON)), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, `{"id":1,"title":"Home <& co>","external":true,"checked_at":"2025-07-10T10:00:00Z","expires_at":null}`, lines[0])
+
+	var row map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &row))
+	assert.Equal(t, `=HYPERLINK("x")`, row["title"], "only CSV defuses formulas")
+	assert.Equal(t, "2025-07-10T10:00:00Z", row["expires_at"])
+}
+
+func TestXLSX(t *testing.T) {
+	out := writeTable(t, export.XLSX)
+	zr, err := zip.NewReader(bytes.NewReader(out), int64(len(out)))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		b, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(b)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+		assert.Contains(t, files, name)
+	}
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<row r="1"><c r="A1" t="inlineStr" s="1"><is><t xml:space="preserve">id</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="A2"><v>1</v></c><c r="B2" t="inlineStr"><is><t xml:space="preserve">Home &lt;&amp; co&gt;</t></is></c><c r="C2" t="b"><v>1</v></c><c r="D2" s="2"><v>45848.41666666667</v></c></row>`)
+	assert.Contains(t, sheet, `<c r="E3" s="2">`)
+	assert.True(t, strings.HasSuffix(sheet, `</sheetData></worksheet>`))
+}
+
+func TestXLSX_Columns(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := export.NewWriter(export.XLSX, &buf)
+	require.NoError(t, err)
+	cols := make([]string, 28)
+	for i := range cols {
+		cols[i] = "c"
+	}
+	require.NoError(t, w.Header(cols))
+	require.NoError(t, w.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	rc, err := zr.Open("xl/worksheets/sheet1.xml")
+	require.NoError(t, err)
+	sheet, _ := io.ReadAll(rc)
+	assert.Contains(t, string(sheet), `r="Z1"`)
+	assert.Contains(t, string(sheet), `r="AB1"`)
+}
@@ -0,0 +1,148 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/handler"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+	"github.com/fuzumoe/urlinsight-backend/internal/service"
+)
+
+// dummyAnalysisService is a dummy implementation of service.AnalysisService for testing.
+// User 1 owns every URL; snapshot 404 does not exist.
+type dummyAnalysisService struct {
+	lastFrom, lastTo uint
+}
+
+func (s *dummyAnalysisService) Record(res *model.AnalysisResult, links []model.Link) error {
+	return nil
+}
+
+func (s *dummyAnalysisService) List(urlID uint, p repository.Pagination) ([]*model.AnalysisResultDTO, error) {
+	return []*model.AnalysisResultDTO{{ID: 1, URLID: urlID}}, nil
+}
+
+func (s *dummyAnalysisService) History(userID, urlID uint, p repository.Pagination) (*model.PaginatedResponse[model.AnalysisResultDTO], error) {
+	if userID != 1 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &model.PaginatedResponse[model.AnalysisResultDTO]{
+		Data:       []model.AnalysisResultDTO{{ID: 2, URLID: urlID}, {ID: 1, URLID: urlID}},
+		Pagination: model.PaginationMetaDTO{Page: p.Page, PageSize: p.PageSize, TotalItems: 2, TotalPages: 1},
+	}, nil
+}
+
+func (s *dummyAnalysisService) Diff(userID, urlID, fromID, toID uint) (*model.SnapshotDiffDTO, error) {
+	s.lastFrom, s.lastTo = fromID, toID
+	if userID != 1 || fromID == 404 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &model.SnapshotDiffDTO{
+		URLID: urlID,
+		From:  &model.AnalysisResultDTO{ID: fromID},
+		To:    &model.AnalysisResultDTO{ID: toID},
+	}, nil
+}
+
+func (s *dummyAnalysisService) ExpiringCertificates(userID uint, days int) ([]model.ExpiringCertificateDTO, error) {
+	if days < 0 {
+		return nil, service.ErrInvalidDays
+	}
+	return []model.ExpiringCertificateDTO{{URLID: 1, OriginalURL: "https://partner.test", DaysLeft: days - 1}}, nil
+}
+
+func TestAnalysisHandler(t *testing.T) {
+	svc := &dummyAnalysisService{}
+	h := handler.NewAnalysisHandler(svc)
+	router := setupRouter()
+	h.RegisterProtectedRoutes(router.Group("/api", func(c *gin.Context) { c.Set("user_id", uint(1)) }))
+	h.RegisterProtectedRoutes(router.Group("/other", func(c *gin.Context) { c.Set("user_id", uint(2)) }))
+
+	t.Run("History", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/urls/3/snapshots", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp model.PaginatedResponse[model.AnalysisResultDTO]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Data, 2)
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/urls/3/diff?from=1&to=2", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, uint(1), svc.lastFrom)
+		assert.Equal(t, uint(2), svc.lastTo)
+	})
+
+	t.Run("Diff Defaults", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/urls/3/diff", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Zero(t, svc.lastFrom)
+		assert.Zero(t, svc.lastTo)
+	})
+
+	t.Run("Diff Invalid Query", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/urls/3/diff?from=abc", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Diff Not Found", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/urls/3/diff?from=404&to=2", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Other User's URL", func(t *testing.T) {
+		for _, path := range []string{"/other/urls/3/snapshots", "/other/urls/3/diff?from=1&to=2"} {
+			req, _ := http.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code, path)
+		}
+	})
+
+	t.Run("Expiring Certificates", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/certificates/expiring", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var certs []model.ExpiringCertificateDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &certs))
+		require.Len(t, certs, 1)
+		assert.Equal(t, 29, certs[0].DaysLeft, "days should default to 30")
+	})
+
+	t.Run("Expiring Certificates Invalid Days", func(t *testing.T) {
+		for _, q := range []string{"days=soon", "days=-1"} {
+			req, _ := http.NewRequest("GET", "/api/certificates/expiring?"+q, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, q)
+		}
+	})
+}
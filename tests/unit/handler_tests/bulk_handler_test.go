@@ -0,0 +1,191 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/handler"
+	"github.com/fuzumoe/urlinsight-backend/internal/importer"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+// recordingBulkService is a dummy implementation of service.BulkService that records what
+// the handler passed to Import.
+type recordingBulkService struct {
+	entries    []importer.Entry
+	sitemapURL string
+	opts       model.BulkImportOptions
+}
+
+func (s *recordingBulkService) Import(ctx context.Context, userID uint, entries []importer.Entry, sitemapURL string, opts model.BulkImportOptions) (*model.BulkImportResultDTO, error) {
+	s.entries, s.sitemapURL, s.opts = entries, sitemapURL, opts
+	if len(entries) == 0 && sitemapURL == "" {
+		return nil, errors.New("no URLs to import")
+	}
+	return &model.BulkImportResultDTO{Created: len(entries)}, nil
+}
+
+func (s *recordingBulkService) Start(userID uint, req *model.BulkActionRequestDTO) (*model.BulkActionResultDTO, error) {
+	return &model.BulkActionResultDTO{Matched: len(req.IDs), Succeeded: len(req.IDs)}, nil
+}
+
+func (s *recordingBulkService) Stop(userID uint, req *model.BulkActionRequestDTO) (*model.BulkActionResultDTO, error) {
+	return &model.BulkActionResultDTO{Matched: len(req.IDs), Succeeded: len(req.IDs)}, nil
+}
+
+func (s *recordingBulkService) Delete(userID uint, req *model.BulkActionRequestDTO) (*model.BulkActionResultDTO, error) {
+	if len(req.IDs) == 0 && req.Filter == nil {
+		return nil, errors.New("ids or filter is required")
+	}
+	return &model.BulkActionResultDTO{Matched: len(req.IDs), Succeeded: len(req.IDs)}, nil
+}
+
+func TestBulkHandler(t *testing.T) {
+	svc := &recordingBulkService{}
+	h := handler.NewBulkHandler(svc)
+	router := setupRouter()
+	api := router.Group("/api", func(c *gin.Context) { c.Set("user_id", uint(1)) })
+	h.RegisterProtectedRoutes(api)
+
+	do := func(path, contentType string, body *bytes.Buffer) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", path, body)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	values := func() []string {
+		out := make([]string, len(svc.entries))
+		for i, e := range svc.entries {
+			out[i] = e.Value
+		}
+		return out
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		body, _ := json.Marshal(model.BulkImportRequestDTO{
+			URLs:              []string{"example.com", "https://example.org"},
+			SitemapURL:        "https://example.com/sitemap.xml",
+			BulkImportOptions: model.BulkImportOptions{CrawlMode: model.CrawlModeSite, MaxDepth: 2, Start: true},
+		})
+		w := do("/api/urls/bulk", "application/json", bytes.NewBuffer(body))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var res model.BulkImportResultDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, 2, res.Created)
+		assert.Equal(t, []string{"example.com", "https://example.org"}, values())
+		assert.Equal(t, "https://example.com/sitemap.xml", svc.sitemapURL)
+		assert.Equal(t, model.BulkImportOptions{CrawlMode: model.CrawlModeSite, MaxDepth: 2, Start: true}, svc.opts)
+	})
+
+	t.Run("JSON Invalid Sitemap URL", func(t *testing.T) {
+		w := do("/api/urls/bulk", "application/json", bytes.NewBufferString(`{"sitemap_url":"not a url"}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Text With Query Options", func(t *testing.T) {
+		w := do("/api/urls/bulk?crawl_mode=site&max_pages=20&start=true", "text/plain; charset=utf-8",
+			bytes.NewBufferString("# shops\nexample.com\n\nhttps://example.org\n"))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []importer.Entry{{Row: 2, Value: "example.com"}, {Row: 4, Value: "https://example.org"}}, svc.entries)
+		assert.Equal(t, model.BulkImportOptions{CrawlMode: model.CrawlModeSite, MaxPages: 20, Start: true}, svc.opts)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		w := do("/api/urls/bulk", "text/csv", bytes.NewBufferString("name,url\nShop,example.com\nBlog,example.org\n"))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"example.com", "example.org"}, values())
+	})
+
+	t.Run("Sitemap Upload", func(t *testing.T) {
+		sitemap := `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>https://example.com/a</loc></url></urlset>`
+		w := do("/api/urls/bulk", "application/xml", bytes.NewBufferString(sitemap))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"https://example.com/a"}, values())
+
+		index := `<sitemapindex><sitemap><loc>https://example.com/s1.xml</loc></sitemap></sitemapindex>`
+		w = do("/api/urls/bulk", "application/xml", bytes.NewBufferString(index))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "sitemap_url")
+	})
+
+	t.Run("Multipart", func(t *testing.T) {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		require.NoError(t, mw.WriteField("max_depth", "3"))
+		fw, err := mw.CreateFormFile("file", "urls.csv")
+		require.NoError(t, err)
+		_, _ = fw.Write([]byte("url\nexample.com\n"))
+		require.NoError(t, mw.Close())
+
+		w := do("/api/urls/bulk", mw.FormDataContentType(), &buf)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []importer.Entry{{Row: 2, Value: "example.com"}}, svc.entries)
+		assert.Equal(t, 3, svc.opts.MaxDepth)
+	})
+
+	t.Run("Multipart Without File", func(t *testing.T) {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		require.NoError(t, mw.WriteField("start", "true"))
+		require.NoError(t, mw.Close())
+
+		w := do("/api/urls/bulk", mw.FormDataContentType(), &buf)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "field is required")
+	})
+
+	t.Run("Too Many Lines", func(t *testing.T) {
+		body := strings.Repeat("example.com\n", model.MaxBulkItems+1)
+		w := do("/api/urls/bulk", "text/plain", bytes.NewBufferString(body))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Unsupported Content Type", func(t *testing.T) {
+		w := do("/api/urls/bulk", "image/png", bytes.NewBufferString("x"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Body Too Large", func(t *testing.T) {
+		body := bytes.NewBuffer(make([]byte, 0, 11<<20))
+		for body.Len() < 11<<20 {
+			body.WriteString("# padding padding padding padding padding padding padding\n")
+		}
+		w := do("/api/urls/bulk", "text/plain", body)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("Actions", func(t *testing.T) {
+		body := func() *bytes.Buffer { return bytes.NewBufferString(`{"ids":[1,2]}`) }
+
+		w := do("/api/urls/bulk/start", "application/json", body())
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		var res model.BulkActionResultDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, 2, res.Succeeded)
+
+		assert.Equal(t, http.StatusAccepted, do("/api/urls/bulk/stop", "application/json", body()).Code)
+		assert.Equal(t, http.StatusOK, do("/api/urls/bulk/delete", "application/json", body()).Code)
+	})
+
+	t.Run("Action Errors", func(t *testing.T) {
+		w := do("/api/urls/bulk/delete", "application/json", bytes.NewBufferString(`{}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = do("/api/urls/bulk/delete", "application/json", bytes.NewBufferString(`{"filter":{"status":"bogus"}}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid payload")
+	})
+}
@@ -0,0 +1,134 @@
+package handler_test
+
+import (
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/export"
+	"github.com/fuzumoe/urlinsight-backend/internal/handler"
+)
+
+// dummyExportService is a dummy implementation of service.ExportService for testing.
+// URL 404 does not exist and URL 500 fails half-way through its links.
+type dummyExportService struct {
+	snapshotID uint
+	linkError  string
+}
+
+func (s *dummyExportService) URLs(userID uint, w export.Writer) error {
+	if err := w.Header([]string{"id", "original_url"}); err != nil {
+		return err
+	}
+	if err := w.Row([]any{uint(1), "https://example.com"}); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func (s *dummyExportService) Snapshots(userID, urlID uint, w export.Writer) error {
+	if urlID == 404 {
+		return gorm.ErrRecordNotFound
+	}
+	if err := w.Header([]string{"id", "title"}); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func (s *dummyExportService) Links(userID, urlID, snapshotID uint, linkError string, w export.Writer) error {
+	s.snapshotID, s.linkError = snapshotID, linkError
+	if urlID == 404 {
+		return gorm.ErrRecordNotFound
+	}
+	if err := w.Header([]string{"id", "href"}); err != nil {
+		return err
+	}
+	for i := 0; i < 2000; i++ {
+		if err := w.Row([]any{uint(i), "https://example.com/" + strings.Repeat("x", 20)}); err != nil {
+			return err
+		}
+	}
+	if urlID == 500 {
+		return errors.New("connection lost")
+	}
+	return w.Close()
+}
+
+func TestExportHandler(t *testing.T) {
+	svc := &dummyExportService{}
+	router := setupRouter()
+	api := router.Group("/api", func(c *gin.Context) { c.Set("user_id", uint(1)) })
+	handler.NewExportHandler(svc).RegisterProtectedRoutes(api)
+	handler.NewURLHandler(&dummyURLService{}).RegisterProtectedRoutes(api)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("URLs CSV By Default", func(t *testing.T) {
+		w := get("/api/urls/export")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="urls.csv"`, w.Header().Get("Content-Disposition"))
+		recs, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"id", "original_url"}, {"1", "https://example.com"}}, recs)
+	})
+
+	t.Run("URL Routes Still Resolve", func(t *testing.T) {
+		w := get("/api/urls/7")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":7`)
+	})
+
+	t.Run("Formats", func(t *testing.T) {
+		w := get("/api/urls/export?format=ndjson")
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		assert.Equal(t, "{\"id\":1,\"original_url\":\"https://example.com\"}\n", w.Body.String())
+
+		w = get("/api/urls/3/export/snapshots?format=xlsx")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `attachment; filename="url-3-snapshots.xlsx"`, w.Header().Get("Content-Disposition"))
+		assert.True(t, strings.HasPrefix(w.Body.String(), "PK"), "an XLSX file is a zip archive")
+
+		assert.Equal(t, http.StatusBadRequest, get("/api/urls/export?format=pdf").Code)
+	})
+
+	t.Run("Links", func(t *testing.T) {
+		w := get("/api/urls/3/export/links?snapshot=12&link_error=dns")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, uint(12), svc.snapshotID)
+		assert.Equal(t, "dns", svc.linkError)
+		assert.Equal(t, 2001, strings.Count(w.Body.String(), "\n"))
+
+		assert.Equal(t, http.StatusBadRequest, get("/api/urls/3/export/links?link_error=nope").Code)
+		assert.Equal(t, http.StatusBadRequest, get("/api/urls/3/export/links?snapshot=x").Code)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		for _, path := range []string{"/api/urls/404/export/links", "/api/urls/404/export/snapshots?format=xlsx"} {
+			w := get(path)
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+			assert.Empty(t, w.Header().Get("Content-Disposition"))
+		}
+	})
+
+	t.Run("Failure Mid-Stream", func(t *testing.T) {
+		w := get("/api/urls/500/export/links")
+		assert.Equal(t, http.StatusOK, w.Code, "the status was sent with the first rows")
+		assert.NotContains(t, w.Body.String(), "connection lost")
+	})
+}
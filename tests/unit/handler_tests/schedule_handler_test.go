@@ -0,0 +1,182 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/handler"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+)
+
+// dummyScheduleService is a dummy implementation of service.ScheduleService for testing.
+// User 1 owns every URL; URL ID 404 has no schedule.
+type dummyScheduleService struct{}
+
+func (s *dummyScheduleService) find(userID, urlID uint) error {
+	if userID != 1 || urlID == 404 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *dummyScheduleService) Set(userID, urlID uint, in *model.ScheduleInputDTO) (*model.ScheduleDTO, error) {
+	if userID != 1 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	next := time.Now().Add(time.Duration(in.IntervalSeconds) * time.Second)
+	return &model.ScheduleDTO{URLID: urlID, IntervalSeconds: in.IntervalSeconds, NextRunAt: &next}, nil
+}
+
+func (s *dummyScheduleService) Get(userID, urlID uint) (*model.ScheduleDTO, error) {
+	if err := s.find(userID, urlID); err != nil {
+		return nil, err
+	}
+	return &model.ScheduleDTO{URLID: urlID, Cron: "@daily"}, nil
+}
+
+func (s *dummyScheduleService) Pause(userID, urlID uint) (*model.ScheduleDTO, error) {
+	if err := s.find(userID, urlID); err != nil {
+		return nil, err
+	}
+	return &model.ScheduleDTO{URLID: urlID, Cron: "@daily", Paused: true}, nil
+}
+
+func (s *dummyScheduleService) Resume(userID, urlID uint) (*model.ScheduleDTO, error) {
+	if err := s.find(userID, urlID); err != nil {
+		return nil, err
+	}
+	next := time.Now().Add(time.Hour)
+	return &model.ScheduleDTO{URLID: urlID, Cron: "@daily", NextRunAt: &next}, nil
+}
+
+func (s *dummyScheduleService) Delete(userID, urlID uint) error { return s.find(userID, urlID) }
+
+func (s *dummyScheduleService) List(userID uint, p repository.Pagination) (*model.PaginatedResponse[model.ScheduleDTO], error) {
+	return &model.PaginatedResponse[model.ScheduleDTO]{
+		Data:       []model.ScheduleDTO{{URLID: 1, Cron: "@daily"}},
+		Pagination: model.PaginationMetaDTO{Page: p.Page, PageSize: p.PageSize, TotalItems: 1, TotalPages: 1},
+	}, nil
+}
+
+func TestScheduleHandler(t *testing.T) {
+	h := handler.NewScheduleHandler(&dummyScheduleService{})
+	router := setupRouter()
+	h.RegisterProtectedRoutes(router.Group("/api", func(c *gin.Context) { c.Set("user_id", uint(1)) }))
+	h.RegisterProtectedRoutes(router.Group("/other", func(c *gin.Context) { c.Set("user_id", uint(2)) }))
+
+	t.Run("Set", func(t *testing.T) {
+		body, _ := json.Marshal(model.ScheduleInputDTO{IntervalSeconds: 3600})
+		req, _ := http.NewRequest("PUT", "/api/urls/5/schedule", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var dto model.ScheduleDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &dto))
+		assert.Equal(t, uint(5), dto.URLID)
+		assert.NotNil(t, dto.NextRunAt)
+	})
+
+	t.Run("Set Invalid Payload", func(t *testing.T) {
+		body, _ := json.Marshal(model.ScheduleInputDTO{IntervalSeconds: 5})
+		req, _ := http.NewRequest("PUT", "/api/urls/5/schedule", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/urls/5/schedule", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Get Not Found", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/urls/404/schedule", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Pause", func(t *testing.T) {
+		req, _ := http.NewRequest("PATCH", "/api/urls/5/schedule/pause", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var dto model.ScheduleDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &dto))
+		assert.True(t, dto.Paused)
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		req, _ := http.NewRequest("PATCH", "/api/urls/5/schedule/resume", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		req, _ := http.NewRequest("DELETE", "/api/urls/5/schedule", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Other User's URL", func(t *testing.T) {
+		body, _ := json.Marshal(model.ScheduleInputDTO{IntervalSeconds: 3600})
+		for _, r := range []struct{ method, path string }{
+			{"PUT", "/other/urls/5/schedule"},
+			{"GET", "/other/urls/5/schedule"},
+			{"PATCH", "/other/urls/5/schedule/pause"},
+			{"PATCH", "/other/urls/5/schedule/resume"},
+			{"DELETE", "/other/urls/5/schedule"},
+		} {
+			req, _ := http.NewRequest(r.method, r.path, bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code, r.method+" "+r.path)
+		}
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		unauth := setupRouter()
+		unauth.GET("/api/urls/:id/schedule", h.Get)
+		req, _ := http.NewRequest("GET", "/api/urls/5/schedule", nil)
+		w := httptest.NewRecorder()
+		unauth.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/schedules?page=1&page_size=10", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp model.PaginatedResponse[model.ScheduleDTO]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Data, 1)
+	})
+}
@@ -0,0 +1,162 @@
+package handler_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/handler"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/stream"
+)
+
+// statusURLService serves URLs of user 1 with a status the test can change; URL 99 belongs
+// to user 2. It counts Get calls, which load the URL's results.
+type statusURLService struct {
+	*dummyURLService
+	mu     sync.Mutex
+	status string
+	gets   int
+}
+
+func (s *statusURLService) Get(id uint) (*model.URLDTO, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gets++
+	owner := uint(1)
+	if id == 99 {
+		owner = 2
+	}
+	return &model.URLDTO{ID: id, UserID: owner, Status: s.status}, nil
+}
+
+func (s *statusURLService) Status(id uint) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status, nil
+}
+
+func (s *statusURLService) getCalls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.gets
+}
+
+func (s *statusURLService) setStatus(st string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = st
+}
+
+type sseEvent struct {
+	name string
+	data model.ProgressEvent
+}
+
+// readEvent returns the next event on the stream, skipping comments.
+func readEvent(t *testing.T, r *bufio.Reader) sseEvent {
+	t.Helper()
+	var ev sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			ev.name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &ev.data))
+		case line == "" && ev.name != "":
+			return ev
+		}
+	}
+}
+
+func TestStreamHandler(t *testing.T) {
+	svc := &statusURLService{dummyURLService: &dummyURLService{}, status: model.StatusQueued}
+	broker := stream.NewBroker()
+	h := handler.NewStreamHandler(svc, broker, 50*time.Millisecond)
+
+	router := setupRouter()
+	api := router.Group("/api", func(c *gin.Context) { c.Set("user_id", uint(1)) })
+	h.RegisterProtectedRoutes(api)
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	open := func(t *testing.T, path string) (*http.Response, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+path, nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			cancel()
+			resp.Body.Close()
+		})
+		return resp, cancel
+	}
+
+	t.Run("Streams Current Status Then Reported Events", func(t *testing.T) {
+		resp, _ := open(t, "/api/urls/5/events")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Content-Type"), "text/event-stream")
+		r := bufio.NewReader(resp.Body)
+
+		ev := readEvent(t, r)
+		assert.Equal(t, model.ProgressStatus, ev.name)
+		assert.Equal(t, model.StatusQueued, ev.data.Status)
+		assert.Equal(t, uint(5), ev.data.URLID)
+
+		broker.Report(model.ProgressEvent{URLID: 6, Type: model.ProgressStatus, Status: model.StatusError})
+		broker.Report(model.ProgressEvent{URLID: 5, Type: model.ProgressStatus, Status: model.StatusRunning})
+		broker.Report(model.ProgressEvent{URLID: 5, Type: model.ProgressUpdate, Status: model.StatusRunning,
+			PageURL: "http://example.com", LinksChecked: 3, LinksTotal: 10})
+
+		ev = readEvent(t, r)
+		assert.Equal(t, model.ProgressStatus, ev.name)
+		assert.Equal(t, model.StatusRunning, ev.data.Status, "events of other URLs are not streamed")
+		ev = readEvent(t, r)
+		assert.Equal(t, model.ProgressUpdate, ev.name)
+		assert.Equal(t, 3, ev.data.LinksChecked)
+		assert.Equal(t, 10, ev.data.LinksTotal)
+	})
+
+	t.Run("Heartbeat Picks Up Status Changes Made Elsewhere", func(t *testing.T) {
+		svc.setStatus(model.StatusRunning)
+		resp, _ := open(t, "/api/urls/7/events")
+		r := bufio.NewReader(resp.Body)
+		assert.Equal(t, model.StatusRunning, readEvent(t, r).data.Status)
+		gets := svc.getCalls()
+
+		svc.setStatus(model.StatusDone)
+		ev := readEvent(t, r)
+		assert.Equal(t, model.ProgressStatus, ev.name)
+		assert.Equal(t, model.StatusDone, ev.data.Status)
+		assert.Equal(t, gets, svc.getCalls(), "heartbeats read the status alone")
+	})
+
+	t.Run("Unsubscribes On Disconnect", func(t *testing.T) {
+		resp, cancel := open(t, "/api/urls/8/events")
+		readEvent(t, bufio.NewReader(resp.Body))
+		require.Equal(t, 1, broker.Subscribers(8))
+
+		cancel()
+		require.Eventually(t, func() bool { return broker.Subscribers(8) == 0 }, 2*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Other User's URL", func(t *testing.T) {
+		resp, _ := open(t, "/api/urls/99/events")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Zero(t, broker.Subscribers(99))
+	})
+}
@@ -3,6 +3,7 @@ package handler_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -10,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 
 	"github.com/fuzumoe/urlinsight-backend/internal/handler"
 	"github.com/fuzumoe/urlinsight-backend/internal/model"
@@ -32,6 +34,10 @@ func (s *dummyURLService) Get(id uint) (*model.URLDTO, error) {
 	}, nil
 }
 
+func (s *dummyURLService) Status(id uint) (string, error) {
+	return model.StatusQueued, nil
+}
+
 func (s *dummyURLService) List(userID uint, p repository.Pagination) (*model.PaginatedResponse[model.URLDTO], error) {
 	return &model.PaginatedResponse[model.URLDTO]{
 		Data: []model.URLDTO{{
@@ -61,7 +67,7 @@ func (s *dummyURLService) Start(id uint) error {
 	return nil
 }
 
-func (s *dummyURLService) Stop(id uint) error {
+func (s *dummyURLService) Stop(id uint, keepPartial bool) error {
 	return nil
 }
 
@@ -75,13 +81,34 @@ func (s *dummyURLService) Results(id uint) (*model.URLDTO, error) {
 }
 
 // ResultsWithDetails returns the raw URL with details needed by the Results handler.
-func (s *dummyURLService) ResultsWithDetails(id uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error) {
+// URL 2 is a site crawl.
+func (s *dummyURLService) ResultsWithDetails(id, snapshotID uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error) {
+	if snapshotID == 404 {
+		return nil, nil, nil, fmt.Errorf("failed to get detailed URL results: %w", gorm.ErrRecordNotFound)
+	}
+	mode := model.CrawlModePage
+	if id == 2 {
+		mode = model.CrawlModeSite
+	}
 	return &model.URL{
 		ID:          id,
 		UserID:      1,
 		OriginalURL: "http://example.com/results",
 		Status:      model.StatusDone,
-	}, []*model.AnalysisResult{}, []*model.Link{}, nil
+		CrawlMode:   mode,
+	}, []*model.AnalysisResult{}, []*model.Link{
+		{ID: 1, URLID: id, Href: "http://example.com/ok", StatusCode: 200},
+		{ID: 2, URLID: id, Href: "http://nowhere.invalid/", ErrorCategory: model.LinkErrDNS},
+		{ID: 3, URLID: id, Href: "http://example.com:81/", ErrorCategory: model.LinkErrTimeout},
+	}, nil
+}
+
+// SiteSummary returns a fixed site-crawl summary; older runs crawled one page fewer.
+func (s *dummyURLService) SiteSummary(id, snapshotID uint) (*model.SiteSummary, error) {
+	if snapshotID != 0 {
+		return &model.SiteSummary{URLID: id, PagesCrawled: 2}, nil
+	}
+	return &model.SiteSummary{URLID: id, PagesCrawled: 3}, nil
 }
 
 // setupRouter returns a new Gin engine in test mode.
@@ -236,6 +263,15 @@ func TestURLHandler(t *testing.T) {
 		assert.Equal(t, model.StatusStopped, resp["status"])
 	})
 
+	t.Run("Stop Invalid KeepPartial", func(t *testing.T) {
+		req, err := http.NewRequest("PATCH", "/api/urls/1/stop?keep_partial=maybe", nil)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
 	t.Run("Results", func(t *testing.T) {
 		req, err := http.NewRequest("GET", "/api/urls/1/results", nil)
 		require.NoError(t, err)
@@ -249,4 +285,62 @@ func TestURLHandler(t *testing.T) {
 		// Check that the URL inside the response has status "done" as returned by ResultsWithDetails.
 		assert.Equal(t, model.StatusDone, dto.URL.Status)
 	})
+
+	t.Run("Results Invalid Snapshot", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/api/urls/1/results?snapshot=latest", nil)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Results Snapshot Not Found", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/api/urls/1/results?snapshot=404", nil)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "snapshot not found")
+	})
+
+	t.Run("Results Site Summary Of Snapshot", func(t *testing.T) {
+		for path, want := range map[string]int{"/api/urls/2/results": 3, "/api/urls/2/results?snapshot=9": 2} {
+			req, err := http.NewRequest("GET", path, nil)
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var dto model.URLResultsDTO
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &dto))
+			require.NotNil(t, dto.SiteSummary, path)
+			assert.Equal(t, want, dto.SiteSummary.PagesCrawled, path)
+		}
+	})
+
+	t.Run("Results Link Error Filter", func(t *testing.T) {
+		for filter, want := range map[string]int{"": 3, "any": 2, "dns": 1, "tls": 0} {
+			req, err := http.NewRequest("GET", "/api/urls/1/results?link_error="+filter, nil)
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var dto model.URLResultsDTO
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &dto))
+			assert.Len(t, dto.Links, want, "link_error=%q", filter)
+		}
+	})
+
+	t.Run("Results Invalid Link Error", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/api/urls/1/results?link_error=bogus", nil)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid link_error")
+	})
 }
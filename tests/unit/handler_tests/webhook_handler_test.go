@@ -0,0 +1,160 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/handler"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+)
+
+// dummyWebhookService is a dummy implementation of service.WebhookService for testing.
+// Webhook 404 and delivery 404 do not exist.
+type dummyWebhookService struct{}
+
+func (s *dummyWebhookService) Create(userID uint, in *model.WebhookInputDTO) (*model.WebhookDTO, error) {
+	for _, e := range in.Events {
+		if !model.ValidWebhookEvent(e) {
+			return nil, errors.New("unknown webhook event")
+		}
+	}
+	return &model.WebhookDTO{ID: 1, URL: in.URL, Events: in.Events, Active: true, Secret: "s3cret"}, nil
+}
+
+func (s *dummyWebhookService) Get(userID, id uint) (*model.WebhookDTO, error) {
+	if id == 404 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &model.WebhookDTO{ID: id, URL: "https://ci.example.com/hook"}, nil
+}
+
+func (s *dummyWebhookService) Update(userID, id uint, in *model.WebhookInputDTO) (*model.WebhookDTO, error) {
+	return &model.WebhookDTO{ID: id, URL: in.URL, Events: in.Events}, nil
+}
+
+func (s *dummyWebhookService) Delete(userID, id uint) error {
+	if id == 404 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *dummyWebhookService) List(userID uint, p repository.Pagination) (*model.PaginatedResponse[model.WebhookDTO], error) {
+	return &model.PaginatedResponse[model.WebhookDTO]{
+		Data:       []model.WebhookDTO{{ID: 1, URL: "https://ci.example.com/hook"}},
+		Pagination: model.PaginationMetaDTO{Page: p.Page, PageSize: p.PageSize, TotalItems: 1, TotalPages: 1},
+	}, nil
+}
+
+func (s *dummyWebhookService) Deliveries(userID, id uint, p repository.Pagination) (*model.PaginatedResponse[model.WebhookDelivery], error) {
+	return &model.PaginatedResponse[model.WebhookDelivery]{
+		Data:       []model.WebhookDelivery{{ID: 2, WebhookID: id, Status: model.DeliverySucceeded}},
+		Pagination: model.PaginationMetaDTO{Page: p.Page, PageSize: p.PageSize, TotalItems: 1, TotalPages: 1},
+	}, nil
+}
+
+func (s *dummyWebhookService) Redeliver(userID, id, deliveryID uint) (*model.WebhookDelivery, error) {
+	if deliveryID == 404 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &model.WebhookDelivery{ID: deliveryID + 1, WebhookID: id, Status: model.DeliveryPending}, nil
+}
+
+func TestWebhookHandler(t *testing.T) {
+	h := handler.NewWebhookHandler(&dummyWebhookService{})
+	router := setupRouter()
+	api := router.Group("/api", func(c *gin.Context) { c.Set("user_id", uint(1)) })
+	h.RegisterProtectedRoutes(api)
+
+	do := func(method, path string, body any) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			_ = json.NewEncoder(&buf).Encode(body)
+		}
+		req, _ := http.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		w := do("POST", "/api/webhooks", model.WebhookInputDTO{
+			URL:    "https://ci.example.com/hook",
+			Events: []string{model.EventAnalysisCompleted},
+		})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var dto model.WebhookDTO
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &dto))
+		assert.Equal(t, "s3cret", dto.Secret)
+	})
+
+	t.Run("Create Invalid Payload", func(t *testing.T) {
+		w := do("POST", "/api/webhooks", model.WebhookInputDTO{URL: "not a url", Events: []string{model.EventAnalysisCompleted}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = do("POST", "/api/webhooks", model.WebhookInputDTO{URL: "https://ci.example.com/hook"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Create Unknown Event", func(t *testing.T) {
+		w := do("POST", "/api/webhooks", model.WebhookInputDTO{URL: "https://ci.example.com/hook", Events: []string{"nope"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, do("GET", "/api/webhooks/5", nil).Code)
+		assert.Equal(t, http.StatusNotFound, do("GET", "/api/webhooks/404", nil).Code)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		w := do("PUT", "/api/webhooks/5", model.WebhookInputDTO{
+			URL:    "https://new.example.com/hook",
+			Events: []string{model.EventAnalysisFailed},
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, do("DELETE", "/api/webhooks/5", nil).Code)
+		assert.Equal(t, http.StatusNotFound, do("DELETE", "/api/webhooks/404", nil).Code)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		w := do("GET", "/api/webhooks?page=1&page_size=10", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp model.PaginatedResponse[model.WebhookDTO]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Data, 1)
+	})
+
+	t.Run("Deliveries", func(t *testing.T) {
+		w := do("GET", "/api/webhooks/5/deliveries", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp model.PaginatedResponse[model.WebhookDelivery]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Data, 1)
+		assert.Equal(t, uint(5), resp.Data[0].WebhookID)
+	})
+
+	t.Run("Redeliver", func(t *testing.T) {
+		w := do("POST", "/api/webhooks/5/deliveries/2/redeliver", nil)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		var del model.WebhookDelivery
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &del))
+		assert.Equal(t, uint(3), del.ID)
+
+		assert.Equal(t, http.StatusNotFound, do("POST", "/api/webhooks/5/deliveries/404/redeliver", nil).Code)
+		assert.Equal(t, http.StatusBadRequest, do("POST", "/api/webhooks/5/deliveries/x/redeliver", nil).Code)
+	})
+}
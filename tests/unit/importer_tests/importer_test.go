@@ -0,0 +1,170 @@
+package importer_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/importer"
+)
+
+func TestParseText(t *testing.T) {
+	in := "\ufeffexample.com\n\n# staging\n  https://example.org/a  \n"
+
+	entries, err := importer.ParseText(strings.NewReader(in), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []importer.Entry{
+		{Row: 1, Value: "example.com"},
+		{Row: 4, Value: "https://example.org/a"},
+	}, entries)
+
+	_, err = importer.ParseText(strings.NewReader("a.example\nb.example\nc.example\n"), 2)
+	assert.True(t, errors.Is(err, importer.ErrTooManyEntries))
+}
+
+func TestParseCSV(t *testing.T) {
+	t.Run("Header", func(t *testing.T) {
+		in := "name,URL\nShop,example.com\nEmpty,\nBlog, https://example.org\n"
+		entries, err := importer.ParseCSV(strings.NewReader(in), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []importer.Entry{
+			{Row: 2, Value: "example.com"},
+			{Row: 4, Value: "https://example.org"},
+		}, entries)
+	})
+
+	t.Run("No Header", func(t *testing.T) {
+		in := "\ufeffexample.com,shop\nexample.org\n"
+		entries, err := importer.ParseCSV(strings.NewReader(in), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []importer.Entry{
+			{Row: 1, Value: "example.com"},
+			{Row: 2, Value: "example.org"},
+		}, entries)
+	})
+
+	t.Run("Too Many", func(t *testing.T) {
+		_, err := importer.ParseCSV(strings.NewReader("original_url\na.example\nb.example\n"), 1)
+		assert.True(t, errors.Is(err, importer.ErrTooManyEntries))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := importer.ParseCSV(strings.NewReader("url\n\"unterminated\n"), 10)
+		assert.ErrorContains(t, err, "invalid CSV")
+	})
+}
+
+func TestNormalize(t *testing.T) {
+	for _, tc := range []struct {
+		in, want, err string
+	}{
+		{in: "example.com", want: "https://example.com"},
+		{in: "  HTTP://Example.COM:80/Path?q=1#frag ", want: "http://example.com/Path?q=1"},
+		{in: "https://example.com:443/", want: "https://example.com/"},
+		{in: "https://example.com:8443/x", want: "https://example.com:8443/x"},
+		{in: "http://[::1]:80/", want: "http://[::1]/"},
+		{in: "", err: "empty URL"},
+		{in: "ftp://example.com", err: `unsupported scheme "ftp"`},
+		{in: "https://", err: "missing host"},
+		{in: "https://exa mple.com/%zz", err: "invalid URL"},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := importer.Normalize(tc.in)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+const urlset = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc> https://example.com/ </loc><lastmod>2025-01-01</lastmod></url>
+  <url><loc>https://example.com/about</loc></url>
+</urlset>`
+
+func TestParseSitemap(t *testing.T) {
+	t.Run("URL Set", func(t *testing.T) {
+		pages, sitemaps, err := importer.ParseSitemap(strings.NewReader(urlset))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/", "https://example.com/about"}, pages)
+		assert.Empty(t, sitemaps)
+	})
+
+	t.Run("Index", func(t *testing.T) {
+		in := `<sitemapindex><sitemap><loc>https://example.com/s1.xml</loc></sitemap></sitemapindex>`
+		pages, sitemaps, err := importer.ParseSitemap(strings.NewReader(in))
+		require.NoError(t, err)
+		assert.Empty(t, pages)
+		assert.Equal(t, []string{"https://example.com/s1.xml"}, sitemaps)
+	})
+
+	t.Run("Gzip", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write([]byte(urlset))
+		require.NoError(t, zw.Close())
+
+		pages, _, err := importer.ParseSitemap(&buf)
+		require.NoError(t, err)
+		assert.Len(t, pages, 2)
+	})
+
+	t.Run("Not A Sitemap", func(t *testing.T) {
+		_, _, err := importer.ParseSitemap(strings.NewReader(`<rss><channel/></rss>`))
+		assert.ErrorIs(t, err, importer.ErrNotSitemap)
+
+		_, _, err = importer.ParseSitemap(strings.NewReader(`not xml`))
+		assert.ErrorContains(t, err, "invalid sitemap")
+	})
+}
+
+func TestSitemapFetcher(t *testing.T) {
+	var agent string
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		agent = r.UserAgent()
+		_, _ = w.Write([]byte(`<sitemapindex>
+  <sitemap><loc>` + srv.URL + `/pages.xml</loc></sitemap>
+  <sitemap><loc>` + srv.URL + `/posts.xml</loc></sitemap>
+</sitemapindex>`))
+	})
+	mux.HandleFunc("/pages.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(urlset))
+	})
+	mux.HandleFunc("/posts.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<urlset><url><loc>https://example.com/posts/1</loc></url></urlset>`))
+	})
+
+	f := importer.NewSitemapFetcher(nil, "URLInsightBot/1.0")
+
+	t.Run("Follows Index", func(t *testing.T) {
+		pages, err := f.Fetch(context.Background(), srv.URL+"/sitemap.xml", 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/", "https://example.com/about", "https://example.com/posts/1"}, pages)
+		assert.Equal(t, "URLInsightBot/1.0", agent)
+	})
+
+	t.Run("Too Many", func(t *testing.T) {
+		_, err := f.Fetch(context.Background(), srv.URL+"/sitemap.xml", 2)
+		assert.ErrorIs(t, err, importer.ErrTooManyEntries)
+	})
+
+	t.Run("Bad Status", func(t *testing.T) {
+		_, err := f.Fetch(context.Background(), srv.URL+"/missing.xml", 10)
+		assert.ErrorContains(t, err, "unexpected status 404")
+	})
+}
@@ -28,6 +28,8 @@ func TestAnalysisResult(t *testing.T) {
 			InternalLinkCount: 10,
 			ExternalLinkCount: 20,
 			BrokenLinkCount:   5,
+			ErrorLinkCount:    3,
+			CertExpiresAt:     &updatedAt,
 			CreatedAt:         createdAt,
 			UpdatedAt:         updatedAt,
 		}
@@ -47,7 +49,11 @@ func TestAnalysisResult(t *testing.T) {
 		assert.Equal(t, result.HasLoginForm, dto.HasLoginForm, "HasLoginForm should match")
 		assert.WithinDuration(t, result.CreatedAt, dto.CreatedAt, time.Second, "CreatedAt should match")
 		assert.WithinDuration(t, result.UpdatedAt, dto.UpdatedAt, time.Second, "UpdatedAt should match")
-		// Note: The DTO does not include link count fields.
+		assert.Equal(t, 10, dto.InternalLinkCount, "InternalLinkCount should match")
+		assert.Equal(t, 20, dto.ExternalLinkCount, "ExternalLinkCount should match")
+		assert.Equal(t, 5, dto.BrokenLinkCount, "BrokenLinkCount should match")
+		assert.Equal(t, 3, dto.ErrorLinkCount, "ErrorLinkCount should match")
+		assert.Equal(t, result.CertExpiresAt, dto.CertExpiresAt, "CertExpiresAt should match")
 	})
 
 	t.Run("From Create Input", func(t *testing.T) {
@@ -50,6 +50,34 @@ func TestLink(t *testing.T) {
 		assert.NotZero(t, link.UpdatedAt, "UpdatedAt should be set")
 	})
 
+	t.Run("Is Broken", func(t *testing.T) {
+		assert.False(t, (&model.Link{StatusCode: 200}).IsBroken())
+		assert.False(t, (&model.Link{}).IsBroken(), "an unchecked link is not broken")
+		assert.True(t, (&model.Link{StatusCode: 404}).IsBroken())
+		assert.True(t, (&model.Link{ErrorCategory: model.LinkErrDNS}).IsBroken())
+	})
+
+	t.Run("Filter By Error", func(t *testing.T) {
+		links := []*model.Link{
+			{Href: "https://ok.test", StatusCode: 200},
+			{Href: "https://dns.test", ErrorCategory: model.LinkErrDNS},
+			{Href: "https://tls.test", ErrorCategory: model.LinkErrTLS},
+		}
+
+		assert.Len(t, model.FilterLinksByError(links, ""), 3, "an empty filter keeps every link")
+		assert.Len(t, model.FilterLinksByError(links, model.LinkErrorAny), 2)
+		tls := model.FilterLinksByError(links, model.LinkErrTLS)
+		assert.Len(t, tls, 1)
+		assert.Equal(t, "https://tls.test", tls[0].Href)
+		assert.Empty(t, model.FilterLinksByError(links, model.LinkErrTimeout))
+	})
+
+	t.Run("Valid Error Filter", func(t *testing.T) {
+		assert.True(t, model.ValidLinkErrorFilter(model.LinkErrorAny))
+		assert.True(t, model.ValidLinkErrorFilter(model.LinkErrRefused))
+		assert.False(t, model.ValidLinkErrorFilter("bogus"))
+	})
+
 	t.Run("Table Name", func(t *testing.T) {
 		expected := "links"
 		link := model.Link{}
@@ -17,6 +17,11 @@ func TestAllModelsContainsExpectedTypes(t *testing.T) {
 		"AnalysisResult",
 		"Link",
 		"BlacklistedToken",
+		"SiteSummary",
+		"CrawlJob",
+		"Schedule",
+		"Webhook",
+		"WebhookDelivery",
 	}
 
 	// Collect actual type names from model.AllModels.
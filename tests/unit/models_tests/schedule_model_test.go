@@ -0,0 +1,27 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+func TestSchedule(t *testing.T) {
+	t.Run("ToDTO", func(t *testing.T) {
+		next := time.Now()
+		s := &model.Schedule{ID: 1, URLID: 9, CronExpr: "@daily", NextRunAt: &next}
+
+		dto := s.ToDTO()
+		assert.Equal(t, uint(9), dto.URLID)
+		assert.Equal(t, "@daily", dto.Cron)
+		assert.Equal(t, &next, dto.NextRunAt)
+		assert.False(t, dto.Paused)
+	})
+
+	t.Run("Table Name", func(t *testing.T) {
+		assert.Equal(t, "schedules", model.Schedule{}.TableName(), "TableName should return 'schedules'")
+	})
+}
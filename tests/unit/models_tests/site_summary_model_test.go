@@ -0,0 +1,29 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+func TestSiteSummary(t *testing.T) {
+	t.Run("Add", func(t *testing.T) {
+		s := &model.SiteSummary{}
+		s.Add(&model.AnalysisResult{Depth: 0, InternalLinkCount: 4, ExternalLinkCount: 1, BrokenLinkCount: 1, ErrorLinkCount: 1})
+		s.Add(&model.AnalysisResult{Depth: 2, InternalLinkCount: 2, HasLoginForm: true})
+
+		assert.Equal(t, 2, s.PagesCrawled, "PagesCrawled should count every added page")
+		assert.Equal(t, 2, s.MaxDepthReached, "MaxDepthReached should track the deepest page")
+		assert.Equal(t, 6, s.InternalLinkCount, "InternalLinkCount should be summed")
+		assert.Equal(t, 1, s.ExternalLinkCount, "ExternalLinkCount should be summed")
+		assert.Equal(t, 1, s.BrokenLinkCount, "BrokenLinkCount should be summed")
+		assert.Equal(t, 1, s.ErrorLinkCount, "ErrorLinkCount should be summed")
+		assert.Equal(t, 1, s.LoginFormPages, "LoginFormPages should count pages with a login form")
+	})
+
+	t.Run("Table Name", func(t *testing.T) {
+		assert.Equal(t, "site_summaries", model.SiteSummary{}.TableName(), "TableName should return 'site_summaries'")
+	})
+}
@@ -0,0 +1,80 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	from := &model.AnalysisResult{ID: 1, URLID: 7, Title: "Home", HTMLVersion: "HTML 5", H1Count: 1, H2Count: 4}
+	to := &model.AnalysisResult{ID: 2, URLID: 7, Title: "Welcome", HTMLVersion: "HTML 5", H1Count: 1, H2Count: 2, H3Count: 1}
+
+	fromLinks := []model.Link{
+		{Href: "https://a.test/kept", StatusCode: 200},
+		{Href: "https://a.test/breaks", StatusCode: 301},
+		{Href: "https://a.test/was-broken", StatusCode: 500},
+		{Href: "https://a.test/unknown", StatusCode: 0},
+		{Href: "https://a.test/removed", StatusCode: 200},
+	}
+	toLinks := []model.Link{
+		{Href: "https://a.test/kept", StatusCode: 200},
+		{Href: "https://a.test/breaks", StatusCode: 404},
+		{Href: "https://a.test/was-broken", StatusCode: 503},
+		{Href: "https://a.test/unknown", StatusCode: 404},
+		{Href: "https://a.test/added", StatusCode: 200},
+	}
+
+	d := model.DiffSnapshots(from, to, fromLinks, toLinks)
+
+	t.Run("Fields", func(t *testing.T) {
+		require.NotNil(t, d.Title, "a changed title should be reported")
+		assert.Equal(t, "Home", d.Title.From)
+		assert.Equal(t, "Welcome", d.Title.To)
+		assert.Nil(t, d.HTMLVersion, "an unchanged HTML version should be omitted")
+		assert.Nil(t, d.SecurityGrade, "snapshots without a security report have no grade change")
+		assert.Equal(t, uint(1), d.From.ID)
+		assert.Equal(t, uint(2), d.To.ID)
+	})
+
+	t.Run("Headings", func(t *testing.T) {
+		assert.Equal(t, model.HeadingDeltaDTO{H2: -2, H3: 1}, d.Headings)
+	})
+
+	t.Run("Links", func(t *testing.T) {
+		require.Len(t, d.LinksAdded, 1)
+		assert.Equal(t, "https://a.test/added", d.LinksAdded[0].Href)
+		require.Len(t, d.LinksRemoved, 1)
+		assert.Equal(t, "https://a.test/removed", d.LinksRemoved[0].Href)
+	})
+
+	t.Run("Newly Broken", func(t *testing.T) {
+		// Only links that were OK before count; already-broken and unchecked links do not.
+		require.Len(t, d.NewlyBroken, 1)
+		assert.Equal(t, model.LinkStatusChangeDTO{Href: "https://a.test/breaks", FromStatus: 301, ToStatus: 404}, d.NewlyBroken[0])
+	})
+}
+
+func TestDiffSnapshots_SecurityGrade(t *testing.T) {
+	from := &model.AnalysisResult{ID: 1, URLID: 7, Security: &model.SecurityReport{Grade: "A"}}
+	to := &model.AnalysisResult{ID: 2, URLID: 7, Security: &model.SecurityReport{Grade: "C"}}
+
+	d := model.DiffSnapshots(from, to, nil, nil)
+	require.NotNil(t, d.SecurityGrade)
+	assert.Equal(t, "A", d.SecurityGrade.From)
+	assert.Equal(t, "C", d.SecurityGrade.To)
+}
+
+func TestDiffSnapshots_LinkErrors(t *testing.T) {
+	from := &model.AnalysisResult{ID: 1, URLID: 7}
+	to := &model.AnalysisResult{ID: 2, URLID: 7}
+	fromLinks := []model.Link{{Href: "https://a.test/down", StatusCode: 200}}
+	toLinks := []model.Link{{Href: "https://a.test/down", ErrorCategory: model.LinkErrRefused}}
+
+	d := model.DiffSnapshots(from, to, fromLinks, toLinks)
+	require.Len(t, d.NewlyBroken, 1)
+	assert.Equal(t, model.LinkStatusChangeDTO{Href: "https://a.test/down", FromStatus: 200, ToError: model.LinkErrRefused}, d.NewlyBroken[0])
+}
@@ -144,6 +144,30 @@ func TestURL(t *testing.T) {
 		assert.NotZero(t, u.UpdatedAt, "UpdatedAt should be set")
 	})
 
+	t.Run("Request Options", func(t *testing.T) {
+		u := &model.URL{
+			RequestHeaders: map[string]string{"Authorization": "Basic Zm9vOmJhcg=="},
+			RequestCookies: map[string]string{"session": "abc"},
+		}
+		opts := u.RequestOptions()
+		assert.Equal(t, u.RequestHeaders, opts.Headers)
+		assert.Equal(t, u.RequestCookies, opts.Cookies)
+		assert.Equal(t, u.RequestHeaders, u.ToDTO().RequestHeaders, "the DTO should expose the headers")
+	})
+
+	t.Run("Validate Request Options", func(t *testing.T) {
+		assert.NoError(t, model.ValidateRequestOptions(nil, nil))
+		assert.NoError(t, model.ValidateRequestOptions(
+			map[string]string{"X-Env": "staging", "User-Agent": "Custom/1.0"},
+			map[string]string{"session": "abc123"},
+		))
+		assert.Error(t, model.ValidateRequestOptions(map[string]string{"X Env": "staging"}, nil), "spaces are not allowed in header names")
+		assert.Error(t, model.ValidateRequestOptions(map[string]string{"X-Env": "a\nb"}, nil), "newlines are not allowed in header values")
+		assert.Error(t, model.ValidateRequestOptions(map[string]string{"cookie": "a=b"}, nil), "cookies have their own field")
+		assert.Error(t, model.ValidateRequestOptions(nil, map[string]string{"bad;name": "x"}))
+		assert.Error(t, model.ValidateRequestOptions(nil, map[string]string{"": "x"}))
+	})
+
 	t.Run("Table Name", func(t *testing.T) {
 		expected := "urls"
 		u := model.URL{}
@@ -50,10 +50,20 @@ func TestAnalysisResultRepo(t *testing.T) {
 
 		mock.ExpectBegin()
 		exec := mock.ExpectExec(regexp.QuoteMeta(
-			"INSERT INTO `analysis_results` (`url_id`,`html_version`,`title`,`h1_count`,`h2_count`,`h3_count`,`h4_count`,`h5_count`,`h6_count`,`has_login_form`,`internal_link_count`,`external_link_count`,`broken_link_count`,`created_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)",
+			"INSERT INTO `analysis_results` (`url_id`,`page_url`,`depth`,`status_code`,`content_type`,`charset`,`body_truncated`,`links_incomplete`,`modules`,`health`,`assertions`,`html_version`,`title`,`h1_count`,`h2_count`,`h3_count`,`h4_count`,`h5_count`,`h6_count`,`has_login_form`,`internal_link_count`,`external_link_count`,`broken_link_count`,`error_link_count`,`seo`,`structured_data`,`accessibility`,`security`,`tls`,`cert_expires_at`,`redirects`,`robots`,`timing_dns_ms`,`timing_connect_ms`,`timing_tls_ms`,`timing_ttfb_ms`,`timing_download_ms`,`timing_total_ms`,`timing_response_bytes`,`created_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)",
 		))
 		exec.WithArgs(
 			testResult.URLID,
+			"",               // page_url
+			0,                // depth
+			0,                // status_code
+			"",               // content_type
+			"",               // charset
+			false,            // body_truncated
+			false,            // links_incomplete
+			sqlmock.AnyArg(), // modules
+			"",               // health
+			sqlmock.AnyArg(), // assertions
 			testResult.HTMLVersion,
 			testResult.Title,
 			testResult.H1Count,
@@ -63,9 +73,19 @@ func TestAnalysisResultRepo(t *testing.T) {
 			testResult.H5Count,
 			testResult.H6Count,
 			testResult.HasLoginForm,
-			0,                // internal_link_count default
-			0,                // external_link_count default
-			0,                // broken_link_count default
+			0,                   // internal_link_count default
+			0,                   // external_link_count default
+			0,                   // broken_link_count default
+			0,                   // error_link_count default
+			sqlmock.AnyArg(),    // seo
+			sqlmock.AnyArg(),    // structured_data
+			sqlmock.AnyArg(),    // accessibility
+			sqlmock.AnyArg(),    // security
+			sqlmock.AnyArg(),    // tls
+			sqlmock.AnyArg(),    // cert_expires_at
+			sqlmock.AnyArg(),    // redirects
+			sqlmock.AnyArg(),    // robots
+			0, 0, 0, 0, 0, 0, 0, // timing
 			sqlmock.AnyArg(), // created_at
 			sqlmock.AnyArg(), // updated_at
 			sqlmock.AnyArg(), // deleted_at
@@ -166,4 +186,127 @@ func TestAnalysisResultRepo(t *testing.T) {
 		assert.Equal(t, "Fourth Analysis", results[1].Title)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("Links By Snapshot", func(t *testing.T) {
+		db, mock := setupAnaMockDB(t)
+		repo := repository.NewAnalysisResultRepo(db)
+		snap := &model.AnalysisResult{ID: 5, URLID: 42, CreatedAt: time.Now()}
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `links` WHERE analysis_result_id = ? AND `links`.`deleted_at` IS NULL ORDER BY id",
+		)).WithArgs(uint(5)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "url_id", "analysis_result_id", "href", "status_code"}).
+				AddRow(1, 42, 5, "https://a.test", 200))
+
+		links, err := repo.Links(snap)
+		require.NoError(t, err)
+		require.Len(t, links, 1)
+		require.NotNil(t, links[0].AnalysisResultID)
+		assert.Equal(t, uint(5), *links[0].AnalysisResultID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Legacy Links Between Snapshots", func(t *testing.T) {
+		db, mock := setupAnaMockDB(t)
+		repo := repository.NewAnalysisResultRepo(db)
+		created := time.Now().Add(-time.Hour)
+		nextCreated := time.Now()
+		snap := &model.AnalysisResult{ID: 5, URLID: 42, CreatedAt: created}
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `links` WHERE analysis_result_id = ? AND `links`.`deleted_at` IS NULL ORDER BY id",
+		)).WithArgs(uint(5)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `analysis_results` WHERE (url_id = ? AND id > ?) AND `analysis_results`.`deleted_at` IS NULL ORDER BY id LIMIT ?",
+		)).WithArgs(uint(42), uint(5), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "url_id", "created_at"}).AddRow(6, 42, nextCreated))
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `links` WHERE (url_id = ? AND analysis_result_id IS NULL AND created_at >= ?) AND created_at < ? AND `links`.`deleted_at` IS NULL ORDER BY id",
+		)).WithArgs(uint(42), created, nextCreated).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "url_id", "href", "status_code"}).
+				AddRow(1, 42, "https://a.test", 200))
+
+		links, err := repo.Links(snap)
+		require.NoError(t, err)
+		require.Len(t, links, 1)
+		assert.Equal(t, "https://a.test", links[0].Href)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Legacy Links Of Latest Snapshot", func(t *testing.T) {
+		db, mock := setupAnaMockDB(t)
+		repo := repository.NewAnalysisResultRepo(db)
+		created := time.Now()
+		snap := &model.AnalysisResult{ID: 6, URLID: 42, CreatedAt: created}
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `links` WHERE analysis_result_id = ? AND `links`.`deleted_at` IS NULL ORDER BY id",
+		)).WithArgs(uint(6)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `analysis_results` WHERE (url_id = ? AND id > ?) AND `analysis_results`.`deleted_at` IS NULL ORDER BY id LIMIT ?",
+		)).WithArgs(uint(42), uint(6), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `links` WHERE (url_id = ? AND analysis_result_id IS NULL AND created_at >= ?) AND `links`.`deleted_at` IS NULL ORDER BY id",
+		)).WithArgs(uint(42), created).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		links, err := repo.Links(snap)
+		require.NoError(t, err)
+		assert.Empty(t, links)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Latest Root Snapshots", func(t *testing.T) {
+		db, mock := setupAnaMockDB(t)
+		repo := repository.NewAnalysisResultRepo(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `analysis_results` WHERE (url_id = ? AND depth = 0) AND `analysis_results`.`deleted_at` IS NULL ORDER BY id DESC LIMIT ?",
+		)).WithArgs(uint(42), 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "url_id", "depth"}).AddRow(9, 42, 0).AddRow(3, 42, 0))
+
+		latest, err := repo.Latest(42, 2)
+		require.NoError(t, err)
+		require.Len(t, latest, 2)
+		assert.Equal(t, uint(9), latest[0].ID)
+		assert.Equal(t, uint(3), latest[1].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("FindURL Of Other User", func(t *testing.T) {
+		db, mock := setupAnaMockDB(t)
+		repo := repository.NewAnalysisResultRepo(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `urls` WHERE user_id = ? AND `urls`.`id` = ? AND `urls`.`deleted_at` IS NULL ORDER BY `urls`.`id` LIMIT ?",
+		)).WithArgs(uint(2), uint(42), 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := repo.FindURL(2, 42)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ExpiringCertificates", func(t *testing.T) {
+		db, mock := setupAnaMockDB(t)
+		repo := repository.NewAnalysisResultRepo(db)
+		before := time.Now().Add(30 * 24 * time.Hour)
+		expires := time.Now().Add(24 * time.Hour)
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT ar.url_id, urls.original_url, ar.id AS snapshot_id, ar.cert_expires_at AS expires_at FROM analysis_results AS ar JOIN urls ON urls.id = ar.url_id AND urls.deleted_at IS NULL WHERE urls.user_id = ? AND ar.id IN (SELECT MAX(id) FROM `analysis_results` WHERE depth = 0 AND `analysis_results`.`deleted_at` IS NULL GROUP BY `url_id`) AND ar.cert_expires_at < ? ORDER BY ar.cert_expires_at",
+		)).WithArgs(uint(3), before).
+			WillReturnRows(sqlmock.NewRows([]string{"url_id", "original_url", "snapshot_id", "expires_at"}).
+				AddRow(8, "https://partner.test", 41, expires))
+
+		certs, err := repo.ExpiringCertificates(3, before)
+		require.NoError(t, err)
+		require.Len(t, certs, 1)
+		assert.Equal(t, uint(8), certs[0].URLID)
+		assert.Equal(t, uint(41), certs[0].SnapshotID)
+		assert.Equal(t, "https://partner.test", certs[0].OriginalURL)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
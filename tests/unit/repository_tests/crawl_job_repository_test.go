@@ -0,0 +1,185 @@
+package repository_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+)
+
+func TestCrawlJobRepo(t *testing.T) {
+	lockURL := "SELECT `id` FROM `urls` WHERE `urls`.`id` = ? AND `urls`.`deleted_at` IS NULL LIMIT ? FOR UPDATE"
+	countOpen := "SELECT count(*) FROM `crawl_jobs` WHERE url_id = ? AND status IN (?,?)"
+	claimable := "SELECT * FROM `crawl_jobs` WHERE (status = ? AND available_at <= ?) OR (status = ? AND leased_until < ?) ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED"
+
+	t.Run("Enqueue", func(t *testing.T) {
+		db, mock := setupLinkMockDB(t)
+		repo := repository.NewCrawlJobRepo(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(lockURL)).WithArgs(uint(7), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		mock.ExpectQuery(regexp.QuoteMeta(countOpen)).
+			WithArgs(uint(7), model.JobPending, model.JobLeased).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(regexp.QuoteMeta(
+			"INSERT INTO `crawl_jobs` (`url_id`,`status`,`attempts`,`lease_owner`,`leased_until`,`available_at`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?)",
+		)).WithArgs(
+			uint(7), model.JobPending, 0, "", nil,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+		).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		require.NoError(t, repo.Enqueue(7))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Enqueue Skips Open Job", func(t *testing.T) {
+		db, mock := setupLinkMockDB(t)
+		repo := repository.NewCrawlJobRepo(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(lockURL)).WithArgs(uint(7), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		mock.ExpectQuery(regexp.QuoteMeta(countOpen)).
+			WithArgs(uint(7), model.JobPending, model.JobLeased).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectCommit()
+
+		require.NoError(t, repo.Enqueue(7))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Claim Empty", func(t *testing.T) {
+		db, mock := setupLinkMockDB(t)
+		repo := repository.NewCrawlJobRepo(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(claimable)).
+			WithArgs(model.JobPending, sqlmock.AnyArg(), model.JobLeased, sqlmock.AnyArg(), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+
+		job, err := repo.Claim("worker-a", time.Minute, 3)
+		require.NoError(t, err)
+		assert.Nil(t, job, "an empty queue should yield no job")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Claim", func(t *testing.T) {
+		db, mock := setupLinkMockDB(t)
+		repo := repository.NewCrawlJobRepo(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(claimable)).
+			WithArgs(model.JobPending, sqlmock.AnyArg(), model.JobLeased, sqlmock.AnyArg(), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "url_id", "status", "attempts"}).
+				AddRow(5, 42, model.JobPending, 1))
+		mock.ExpectExec(regexp.QuoteMeta(
+			"UPDATE `crawl_jobs` SET `attempts`=attempts + 1,`lease_owner`=?,`leased_until`=?,`status`=?,`updated_at`=? WHERE id = ?",
+		)).WithArgs("worker-a", sqlmock.AnyArg(), model.JobLeased, sqlmock.AnyArg(), uint(5)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		job, err := repo.Claim("worker-a", time.Minute, 3)
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		assert.Equal(t, uint(42), job.URLID)
+		assert.Equal(t, model.JobLeased, job.Status)
+		assert.Equal(t, 2, job.Attempts, "claiming should count an attempt")
+		assert.Equal(t, "worker-a", job.LeaseOwner)
+		require.NotNil(t, job.LeasedUntil)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Claim Fails Exhausted Jobs", func(t *testing.T) {
+		db, mock := setupLinkMockDB(t)
+		repo := repository.NewCrawlJobRepo(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(claimable)).
+			WithArgs(model.JobPending, sqlmock.AnyArg(), model.JobLeased, sqlmock.AnyArg(), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "url_id", "status", "attempts"}).
+				AddRow(5, 42, model.JobPending, 3))
+		mock.ExpectExec(regexp.QuoteMeta(
+			"UPDATE `crawl_jobs` SET `leased_until`=?,`status`=?,`updated_at`=? WHERE id IN (?)",
+		)).WithArgs(nil, model.JobFailed, sqlmock.AnyArg(), uint(5)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(
+			"UPDATE `urls` SET `status`=?,`updated_at`=? WHERE id IN (?) AND `urls`.`deleted_at` IS NULL",
+		)).WithArgs(model.StatusError, sqlmock.AnyArg(), uint(42)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(claimable)).
+			WithArgs(model.JobPending, sqlmock.AnyArg(), model.JobLeased, sqlmock.AnyArg(), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+
+		job, err := repo.Claim("worker-a", time.Minute, 3)
+		require.NoError(t, err)
+		assert.Nil(t, job, "a job out of attempts should be failed, not leased")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Extend Lease Lost", func(t *testing.T) {
+		db, mock := setupLinkMockDB(t)
+		repo := repository.NewCrawlJobRepo(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(
+			"UPDATE `crawl_jobs` SET `leased_until`=?,`updated_at`=? WHERE id = ? AND lease_owner = ? AND status = ?",
+		)).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), uint(5), "worker-a", model.JobLeased).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.Extend(5, "worker-a", time.Minute)
+		assert.ErrorIs(t, err, repository.ErrLeaseLost)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Release Lease Lost", func(t *testing.T) {
+		db, mock := setupLinkMockDB(t)
+		repo := repository.NewCrawlJobRepo(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `crawl_jobs` WHERE `crawl_jobs`.`id` = ? ORDER BY `crawl_jobs`.`id` LIMIT ?",
+		)).WithArgs(uint(5), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "url_id", "status", "lease_owner"}).
+				AddRow(5, 42, model.JobLeased, "worker-b"))
+		mock.ExpectRollback()
+
+		err := repo.Release(5, "worker-a")
+		assert.ErrorIs(t, err, repository.ErrLeaseLost)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Release Returns The Attempt", func(t *testing.T) {
+		db, mock := setupLinkMockDB(t)
+		repo := repository.NewCrawlJobRepo(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `crawl_jobs` WHERE `crawl_jobs`.`id` = ? ORDER BY `crawl_jobs`.`id` LIMIT ?",
+		)).WithArgs(uint(5), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "url_id", "status", "attempts", "lease_owner"}).
+				AddRow(5, 42, model.JobLeased, 3, "worker-a"))
+		mock.ExpectExec(regexp.QuoteMeta(
+			"UPDATE `crawl_jobs` SET `attempts`=GREATEST(attempts - 1, 0),`available_at`=?,`lease_owner`=?,`leased_until`=?,`status`=?,`updated_at`=? WHERE id = ?",
+		)).WithArgs(sqlmock.AnyArg(), "", nil, model.JobPending, sqlmock.AnyArg(), uint(5)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(
+			"UPDATE `urls` SET `status`=?,`updated_at`=? WHERE id = ? AND `urls`.`deleted_at` IS NULL",
+		)).WithArgs(model.StatusQueued, sqlmock.AnyArg(), uint(42)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		require.NoError(t, repo.Release(5, "worker-a"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
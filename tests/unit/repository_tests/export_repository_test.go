@@ -0,0 +1,131 @@
+package repository_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+)
+
+func TestExportRepo(t *testing.T) {
+	t.Run("FindURL", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		repo := repository.NewExportRepo(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `urls` WHERE user_id = ? AND `urls`.`id` = ? AND `urls`.`deleted_at` IS NULL ORDER BY `urls`.`id` LIMIT ?",
+		)).WithArgs(uint(3), uint(7), 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := repo.FindURL(3, 7)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("EachURL", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		repo := repository.NewExportRepo(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `urls` WHERE user_id = ? AND `urls`.`deleted_at` IS NULL ORDER BY `urls`.`id` LIMIT ?",
+		)).WithArgs(uint(3), 500).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "user_id", "original_url"}).
+				AddRow(4, 3, "https://a.example").
+				AddRow(9, 3, "https://b.example"),
+		)
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `analysis_results` WHERE id IN (SELECT MAX(id) FROM `analysis_results` WHERE (depth = 0 AND url_id IN (?,?)) AND `analysis_results`.`deleted_at` IS NULL GROUP BY `url_id`) AND `analysis_results`.`deleted_at` IS NULL",
+		)).WithArgs(uint(4), uint(9)).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "url_id", "status_code"}).AddRow(30, 9, 200),
+		)
+
+		var got []string
+		err := repo.EachURL(3, func(u *model.URL, latest *model.AnalysisResult) error {
+			if latest == nil {
+				got = append(got, u.OriginalURL+" -")
+				return nil
+			}
+			got = append(got, u.OriginalURL+" 200")
+			assert.Equal(t, uint(30), latest.ID)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://a.example -", "https://b.example 200"}, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("EachSnapshot Stops On Error", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		repo := repository.NewExportRepo(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `analysis_results` WHERE url_id = ? AND `analysis_results`.`deleted_at` IS NULL ORDER BY `analysis_results`.`id` LIMIT ?",
+		)).WithArgs(uint(4), 500).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "url_id"}).AddRow(1, 4).AddRow(2, 4),
+		)
+
+		calls := 0
+		err := repo.EachSnapshot(4, func(res *model.AnalysisResult) error {
+			calls++
+			return errors.New("client went away")
+		})
+		assert.EqualError(t, err, "client went away")
+		assert.Equal(t, 1, calls)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("EachLink", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		repo := repository.NewExportRepo(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `links` WHERE url_id = ? AND ((analysis_result_id >= ? AND (? = 0 OR analysis_result_id < ?)) OR (analysis_result_id IS NULL AND created_at >= (SELECT created_at FROM analysis_results WHERE id = ?) AND (? = 0 OR created_at < (SELECT created_at FROM analysis_results WHERE id = ?)))) AND error_category = ? AND `links`.`deleted_at` IS NULL ORDER BY `links`.`id` LIMIT ?",
+		)).WithArgs(uint(4), uint(10), uint(20), uint(20), uint(10), uint(20), uint(20), model.LinkErrDNS, 500).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "url_id", "analysis_result_id", "href", "error_category"}).
+				AddRow(100, 4, 10, "https://gone.example", model.LinkErrDNS).
+				AddRow(101, 4, nil, "https://legacy.example", model.LinkErrDNS),
+		)
+
+		var hrefs []string
+		err := repo.EachLink(4, 10, 20, model.LinkErrDNS, func(l *model.Link) error {
+			hrefs = append(hrefs, l.Href)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://gone.example", "https://legacy.example"}, hrefs)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("EachLink Latest Run In Batches", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		repo := repository.NewExportRepo(db)
+
+		full := sqlmock.NewRows([]string{"id", "url_id"})
+		for i := 1; i <= 500; i++ {
+			full.AddRow(i, 4)
+		}
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `links` WHERE url_id = ? AND ((analysis_result_id >= ? AND (? = 0 OR analysis_result_id < ?)) OR (analysis_result_id IS NULL AND created_at >= (SELECT created_at FROM analysis_results WHERE id = ?) AND (? = 0 OR created_at < (SELECT created_at FROM analysis_results WHERE id = ?)))) AND error_category <> '' AND `links`.`deleted_at` IS NULL ORDER BY `links`.`id` LIMIT ?",
+		)).WithArgs(uint(4), uint(10), uint(0), uint(0), uint(10), uint(0), uint(0), 500).WillReturnRows(full)
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `links` WHERE url_id = ? AND ((analysis_result_id >= ? AND (? = 0 OR analysis_result_id < ?)) OR (analysis_result_id IS NULL AND created_at >= (SELECT created_at FROM analysis_results WHERE id = ?) AND (? = 0 OR created_at < (SELECT created_at FROM analysis_results WHERE id = ?)))) AND error_category <> '' AND `links`.`id` > ? AND `links`.`deleted_at` IS NULL ORDER BY `links`.`id` LIMIT ?",
+		)).WithArgs(uint(4), uint(10), uint(0), uint(0), uint(10), uint(0), uint(0), uint(500), 500).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "url_id"}).AddRow(501, 4),
+		)
+
+		count := 0
+		err := repo.EachLink(4, 10, 0, model.LinkErrorAny, func(l *model.Link) error {
+			count++
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 501, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -42,12 +42,17 @@ func TestLinkRepo(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta(
-			"INSERT INTO `links` (`url_id`,`href`,`is_external`,`status_code`,`created_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?)",
+			"INSERT INTO `links` (`url_id`,`analysis_result_id`,`href`,`is_external`,`status_code`,`error_category`,`error_message`,`redirects`,`ttfb_ms`,`created_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?)",
 		)).WithArgs(
 			testLink.URLID,
+			nil, // analysis_result_id
 			testLink.Href,
 			testLink.IsExternal,
 			testLink.StatusCode,
+			"",               // error_category
+			"",               // error_message
+			nil,              // redirects
+			0,                // ttfb_ms
 			sqlmock.AnyArg(), // created_at
 			sqlmock.AnyArg(), // updated_at
 			sqlmock.AnyArg(), // deleted_at (nil)
@@ -121,12 +126,17 @@ func TestLinkRepo(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta(
-			"UPDATE `links` SET `url_id`=?,`href`=?,`is_external`=?,`status_code`=?,`created_at`=?,`updated_at`=?,`deleted_at`=? WHERE `links`.`deleted_at` IS NULL AND `id` = ?",
+			"UPDATE `links` SET `url_id`=?,`analysis_result_id`=?,`href`=?,`is_external`=?,`status_code`=?,`error_category`=?,`error_message`=?,`redirects`=?,`ttfb_ms`=?,`created_at`=?,`updated_at`=?,`deleted_at`=? WHERE `links`.`deleted_at` IS NULL AND `id` = ?",
 		)).WithArgs(
 			testLink.URLID,
+			nil, // analysis_result_id
 			testLink.Href,
 			testLink.IsExternal,
 			testLink.StatusCode,
+			"",  // error_category
+			"",  // error_message
+			nil, // redirects
+			0,   // ttfb_ms
 			testLink.CreatedAt,
 			sqlmock.AnyArg(), // updated_at will be updated
 			nil,              // deleted_at is nil
@@ -0,0 +1,101 @@
+package repository_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+)
+
+func TestScheduleRepo(t *testing.T) {
+	t.Run("Due", func(t *testing.T) {
+		db, mock := setupLinkMockDB(t)
+		repo := repository.NewScheduleRepo(db)
+		now := time.Now()
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT `schedules`.`id`,`schedules`.`url_id`,`schedules`.`cron_expr`,`schedules`.`interval_seconds`,`schedules`.`paused`,`schedules`.`next_run_at`,`schedules`.`last_run_at`,`schedules`.`created_at`,`schedules`.`updated_at` FROM `schedules` JOIN urls u ON u.id = schedules.url_id AND u.deleted_at IS NULL WHERE (schedules.paused = ? AND schedules.next_run_at <= ?) AND u.status <> ? AND (NOT EXISTS (SELECT 1 FROM crawl_jobs j WHERE j.url_id = schedules.url_id AND j.status IN (?,?))) ORDER BY schedules.next_run_at LIMIT ?",
+		)).WithArgs(false, now, model.StatusRunning, model.JobPending, model.JobLeased, 50).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "url_id", "interval_seconds", "next_run_at"}).
+				AddRow(1, 9, 3600, now.Add(-time.Minute)))
+
+		due, err := repo.Due(now, 50)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, uint(9), due[0].URLID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Advance", func(t *testing.T) {
+		db, mock := setupLinkMockDB(t)
+		repo := repository.NewScheduleRepo(db)
+		prev := time.Now().Add(-time.Minute)
+		now, next := time.Now(), time.Now().Add(time.Hour)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(
+			"UPDATE `schedules` SET `last_run_at`=?,`next_run_at`=?,`updated_at`=? WHERE id = ? AND next_run_at = ?",
+		)).WithArgs(now, next, sqlmock.AnyArg(), uint(1), prev).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		ok, err := repo.Advance(1, prev, now, next)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Advance Lost Race", func(t *testing.T) {
+		db, mock := setupLinkMockDB(t)
+		repo := repository.NewScheduleRepo(db)
+		prev := time.Now().Add(-time.Minute)
+		now, next := time.Now(), time.Now().Add(time.Hour)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(
+			"UPDATE `schedules` SET `last_run_at`=?,`next_run_at`=?,`updated_at`=? WHERE id = ? AND next_run_at = ?",
+		)).WithArgs(now, next, sqlmock.AnyArg(), uint(1), prev).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		ok, err := repo.Advance(1, prev, now, next)
+		require.NoError(t, err)
+		assert.False(t, ok, "another scheduler advanced the row first")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("FindByURL Of Other User", func(t *testing.T) {
+		db, mock := setupLinkMockDB(t)
+		repo := repository.NewScheduleRepo(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT `schedules`.`id`,`schedules`.`url_id`,`schedules`.`cron_expr`,`schedules`.`interval_seconds`,`schedules`.`paused`,`schedules`.`next_run_at`,`schedules`.`last_run_at`,`schedules`.`created_at`,`schedules`.`updated_at` FROM `schedules` JOIN urls u ON u.id = schedules.url_id AND u.deleted_at IS NULL WHERE schedules.url_id = ? AND u.user_id = ? ORDER BY `schedules`.`id` LIMIT ?",
+		)).WithArgs(uint(9), uint(2), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "url_id"}))
+
+		_, err := repo.FindByURL(2, 9)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		db, mock := setupLinkMockDB(t)
+		repo := repository.NewScheduleRepo(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `schedules` WHERE url_id = ?")).
+			WithArgs(uint(9)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		require.NoError(t, repo.Delete(9))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
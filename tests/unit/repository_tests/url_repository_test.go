@@ -41,12 +41,19 @@ func TestURLRepo(t *testing.T) {
 
 		mock.ExpectBegin()
 		exec := mock.ExpectExec(regexp.QuoteMeta(
-			"INSERT INTO `urls` (`user_id`,`original_url`,`status`,`created_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?)",
+			"INSERT INTO `urls` (`user_id`,`original_url`,`status`,`crawl_mode`,`max_depth`,`max_pages`,`request_headers`,`request_cookies`,`modules`,`assertions`,`created_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?)",
 		))
 		exec.WithArgs(
 			testURL.UserID,
 			testURL.OriginalURL,
 			"queued",
+			"page",
+			0,
+			0,
+			sqlmock.AnyArg(), // request_headers
+			sqlmock.AnyArg(), // request_cookies
+			sqlmock.AnyArg(), // modules
+			sqlmock.AnyArg(), // assertions
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
@@ -103,6 +110,33 @@ func TestURLRepo(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
+	t.Run("Status", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		repo := repository.NewURLRepo(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT `status` FROM `urls` WHERE id = ? AND `urls`.`deleted_at` IS NULL LIMIT ?",
+		)).WithArgs(uint(7), 1).WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(model.StatusRunning))
+
+		status, err := repo.Status(7)
+		assert.NoError(t, err)
+		assert.Equal(t, model.StatusRunning, status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Status_NotFound", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		repo := repository.NewURLRepo(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT `status` FROM `urls` WHERE id = ? AND `urls`.`deleted_at` IS NULL LIMIT ?",
+		)).WithArgs(uint(999), 1).WillReturnRows(sqlmock.NewRows([]string{"status"}))
+
+		_, err := repo.Status(999)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 	t.Run("ListByUser", func(t *testing.T) {
 		db, mock := setupMockDB(t)
 		repo := repository.NewURLRepo(db)
@@ -136,6 +170,7 @@ func TestURLRepo(t *testing.T) {
 			UserID:      1,
 			OriginalURL: "old",
 			Status:      "queued",
+			CrawlMode:   model.CrawlModePage,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		}
@@ -143,9 +178,12 @@ func TestURLRepo(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta(
-			"UPDATE `urls` SET `user_id`=?,`original_url`=?,`status`=?,`created_at`=?,`updated_at`=?,`deleted_at`=? WHERE `urls`.`deleted_at` IS NULL AND `id` = ?",
+			"UPDATE `urls` SET `user_id`=?,`original_url`=?,`status`=?,`crawl_mode`=?,`max_depth`=?,`max_pages`=?,`request_headers`=?,`request_cookies`=?,`modules`=?,`assertions`=?,`created_at`=?,`updated_at`=?,`deleted_at`=? WHERE `urls`.`deleted_at` IS NULL AND `id` = ?",
 		)).WithArgs(
 			testURL.UserID, testURL.OriginalURL, testURL.Status,
+			testURL.CrawlMode, testURL.MaxDepth, testURL.MaxPages,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), // request headers and cookies
+			sqlmock.AnyArg(), sqlmock.AnyArg(), // modules and assertions
 			testURL.CreatedAt, sqlmock.AnyArg(), nil, testURL.ID,
 		).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit()
@@ -224,10 +262,20 @@ func TestURLRepo(t *testing.T) {
 
 		mock.ExpectBegin()
 		exec := mock.ExpectExec(regexp.QuoteMeta(
-			"INSERT INTO `analysis_results` (`url_id`,`html_version`,`title`,`h1_count`,`h2_count`,`h3_count`,`h4_count`,`h5_count`,`h6_count`,`has_login_form`,`internal_link_count`,`external_link_count`,`broken_link_count`,`created_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)",
+			"INSERT INTO `analysis_results` (`url_id`,`page_url`,`depth`,`status_code`,`content_type`,`charset`,`body_truncated`,`links_incomplete`,`modules`,`health`,`assertions`,`html_version`,`title`,`h1_count`,`h2_count`,`h3_count`,`h4_count`,`h5_count`,`h6_count`,`has_login_form`,`internal_link_count`,`external_link_count`,`broken_link_count`,`error_link_count`,`seo`,`structured_data`,`accessibility`,`security`,`tls`,`cert_expires_at`,`redirects`,`robots`,`timing_dns_ms`,`timing_connect_ms`,`timing_tls_ms`,`timing_ttfb_ms`,`timing_download_ms`,`timing_total_ms`,`timing_response_bytes`,`created_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)",
 		))
 		exec.WithArgs(
 			urlID,
+			"",               // page_url
+			0,                // depth
+			0,                // status_code
+			"",               // content_type
+			"",               // charset
+			false,            // body_truncated
+			false,            // links_incomplete
+			sqlmock.AnyArg(), // modules
+			"",               // health
+			sqlmock.AnyArg(), // assertions
 			analysisRes.HTMLVersion,
 			analysisRes.Title,
 			analysisRes.H1Count,
@@ -237,19 +285,29 @@ func TestURLRepo(t *testing.T) {
 			analysisRes.H5Count,
 			analysisRes.H6Count,
 			analysisRes.HasLoginForm,
-			0, // default internal_link_count
-			0, // default external_link_count
-			0, // default broken_link_count
+			0,                   // default internal_link_count
+			0,                   // default external_link_count
+			0,                   // default broken_link_count
+			0,                   // default error_link_count
+			sqlmock.AnyArg(),    // seo
+			sqlmock.AnyArg(),    // structured_data
+			sqlmock.AnyArg(),    // accessibility
+			sqlmock.AnyArg(),    // security
+			sqlmock.AnyArg(),    // tls
+			sqlmock.AnyArg(),    // cert_expires_at
+			sqlmock.AnyArg(),    // redirects
+			sqlmock.AnyArg(),    // robots
+			0, 0, 0, 0, 0, 0, 0, // timing
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
 		).WillReturnResult(sqlmock.NewResult(30, 1))
 		// Updated expectation for links - includes is_external and status_code.
 		mock.ExpectExec(regexp.QuoteMeta(
-			"INSERT INTO `links` (`url_id`,`href`,`is_external`,`status_code`,`created_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?),(?,?,?,?,?,?,?)",
+			"INSERT INTO `links` (`url_id`,`analysis_result_id`,`href`,`is_external`,`status_code`,`error_category`,`error_message`,`redirects`,`ttfb_ms`,`created_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?),(?,?,?,?,?,?,?,?,?,?,?,?)",
 		)).WithArgs(
-			urlID, links[0].Href, false, 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-			urlID, links[1].Href, false, 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			urlID, 30, links[0].Href, false, 0, "", "", nil, 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			urlID, 30, links[1].Href, false, 0, "", "", nil, 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 		).WillReturnResult(sqlmock.NewResult(100, 2))
 		mock.ExpectCommit()
 
@@ -292,6 +350,10 @@ func TestURLRepo(t *testing.T) {
 		// Expected JSON response returned by the raw query.
 		expectedJSON := `{"url":{"id":15,"user_id":99,"original_url":"https://results.test","status":"completed","created_at":"2025-07-11T00:00:00.000000Z","updated_at":"2025-07-11T00:00:00.000000Z"},"analysis_results":[],"links":[]}`
 
+		// The latest run starts at the newest root-page snapshot.
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT COALESCE(MAX(id), 0) FROM `analysis_results` WHERE (url_id = ? AND depth = 0) AND `analysis_results`.`deleted_at` IS NULL",
+		)).WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"COALESCE(MAX(id), 0)"}).AddRow(21))
 		mock.ExpectQuery(regexp.QuoteMeta(
 			`SELECT
   JSON_OBJECT(
@@ -301,6 +363,9 @@ func TestURLRepo(t *testing.T) {
         'user_id',      u.user_id,
         'original_url', u.original_url,
         'status',       u.status,
+        'crawl_mode',   u.crawl_mode,
+        'max_depth',    u.max_depth,
+        'max_pages',    u.max_pages,
         'created_at',   DATE_FORMAT(u.created_at, '%Y-%m-%dT%H:%i:%s.%fZ'),
         'updated_at',   DATE_FORMAT(u.updated_at, '%Y-%m-%dT%H:%i:%s.%fZ')
       ),
@@ -310,6 +375,15 @@ func TestURLRepo(t *testing.T) {
                  JSON_OBJECT(
                    'id',                  ar.id,
                    'url_id',              ar.url_id,
+                   'page_url',            ar.page_url,
+                   'depth',               ar.depth,
+                   'status_code',         ar.status_code,
+                   'content_type',        ar.content_type,
+                   'charset',             ar.charset,
+                   'body_truncated',      IF(ar.body_truncated = 1, CAST('true' AS JSON), CAST('false' AS JSON)),
+                   'modules',             ar.modules,
+                   'health',              ar.health,
+                   'assertions',          ar.assertions,
                    'html_version',        ar.html_version,
                    'title',               ar.title,
                    'h1_count',            ar.h1_count,
@@ -322,35 +396,70 @@ func TestURLRepo(t *testing.T) {
                    'internal_link_count', ar.internal_link_count,
                    'external_link_count', ar.external_link_count,
                    'broken_link_count',   ar.broken_link_count,
+                   'error_link_count',    ar.error_link_count,
+                   'links_incomplete',    IF(ar.links_incomplete = 1, CAST('true' AS JSON), CAST('false' AS JSON)),
+                   'seo',                 ar.seo,
+                   'structured_data',     ar.structured_data,
+                   'accessibility',       ar.accessibility,
+                   'security',            ar.security,
+                   'tls',                 ar.tls,
+                   'cert_expires_at',     DATE_FORMAT(ar.cert_expires_at, '%Y-%m-%dT%H:%i:%s.%fZ'),
+                   'redirects',           ar.redirects,
+                   'robots',              ar.robots,
+                   'timing',              JSON_OBJECT(
+                                            'dns_ms',         ar.timing_dns_ms,
+                                            'connect_ms',     ar.timing_connect_ms,
+                                            'tls_ms',         ar.timing_tls_ms,
+                                            'ttfb_ms',        ar.timing_ttfb_ms,
+                                            'download_ms',    ar.timing_download_ms,
+                                            'total_ms',       ar.timing_total_ms,
+                                            'response_bytes', ar.timing_response_bytes
+                                          ),
                    'created_at',          DATE_FORMAT(ar.created_at, '%Y-%m-%dT%H:%i:%s.%fZ'),
                    'updated_at',          DATE_FORMAT(ar.updated_at, '%Y-%m-%dT%H:%i:%s.%fZ')
                  )
                )
         FROM   analysis_results ar
         WHERE  ar.url_id = u.id
+          AND  ar.deleted_at IS NULL
+          AND  ar.id >= ? AND (? = 0 OR ar.id < ?)
         ORDER BY ar.created_at DESC
       ),
     'links',
       (
         SELECT JSON_ARRAYAGG(
                  JSON_OBJECT(
-                   'id',          l.id,
-                   'url_id',      l.url_id,
-                   'href',        l.href,
-                   'is_external', IF(l.is_external = 1, CAST('true' AS JSON), CAST('false' AS JSON)),
-                   'status_code', l.status_code
+                   'id',                 l.id,
+                   'url_id',             l.url_id,
+                   'analysis_result_id', l.analysis_result_id,
+                   'href',               l.href,
+                   'is_external',        IF(l.is_external = 1, CAST('true' AS JSON), CAST('false' AS JSON)),
+                   'status_code',        l.status_code,
+                   'error_category',     l.error_category,
+                   'error_message',      l.error_message,
+                   'redirects',          l.redirects,
+                   'ttfb_ms',            l.ttfb_ms
                  )
                )
         FROM   links l
         WHERE  l.url_id = u.id
+          AND  l.deleted_at IS NULL
+          AND  (
+                 (l.analysis_result_id >= ? AND (? = 0 OR l.analysis_result_id < ?))
+                 OR (
+                   l.analysis_result_id IS NULL
+                   AND l.created_at >= (SELECT created_at FROM analysis_results WHERE id = ?)
+                   AND (? = 0 OR l.created_at < (SELECT created_at FROM analysis_results WHERE id = ?))
+                 )
+               )
       )
   ) AS result_document
 FROM urls u
 WHERE u.id = ?`)).
-			WithArgs(id).
+			WithArgs(21, 0, 0, 21, 0, 0, 21, 0, 0, id).
 			WillReturnRows(sqlmock.NewRows([]string{"result_document"}).AddRow(expectedJSON))
 
-		resultURL, resultAR, resultLinks, err := repo.ResultsWithDetails(id)
+		resultURL, resultAR, resultLinks, err := repo.ResultsWithDetails(id, 0)
 		assert.NoError(t, err)
 		// Check URL fields
 		assert.Equal(t, uint(15), resultURL.ID)
@@ -362,6 +471,19 @@ WHERE u.id = ?`)).
 		assert.Len(t, resultLinks, 0)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+	t.Run("ResultsWithDetails Unknown Snapshot", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		repo := repository.NewURLRepo(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `analysis_results` WHERE url_id = ? AND `analysis_results`.`id` = ? AND `analysis_results`.`deleted_at` IS NULL ORDER BY `analysis_results`.`id` LIMIT ?",
+		)).WithArgs(uint(15), uint(7), 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, _, _, err := repo.ResultsWithDetails(15, 7)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 	// Add this test case to the TestURLRepo function
 	t.Run("CountByUser", func(t *testing.T) {
 		db, mock := setupMockDB(t)
@@ -405,4 +527,121 @@ WHERE u.id = ?`)).
 		assert.Equal(t, 0, count)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("SaveSiteResults", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		repo := repository.NewURLRepo(db)
+		urlID := uint(12)
+		pages := []model.PageResult{
+			{
+				Result: &model.AnalysisResult{PageURL: "https://example.com", HTMLVersion: "HTML 5"},
+				Links:  []model.Link{{Href: "https://example.com/a"}},
+			},
+		}
+		summary := &model.SiteSummary{PagesCrawled: 1}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `analysis_results`")).
+			WillReturnResult(sqlmock.NewResult(40, 1))
+		mock.ExpectExec(regexp.QuoteMeta(
+			"INSERT INTO `links` (`url_id`,`analysis_result_id`,`href`,`is_external`,`status_code`,`error_category`,`error_message`,`redirects`,`ttfb_ms`,`created_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?)",
+		)).WithArgs(
+			urlID, 40, "https://example.com/a", false, 0, "", "", nil, 0,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+		).WillReturnResult(sqlmock.NewResult(300, 1))
+		mock.ExpectExec(regexp.QuoteMeta(
+			"INSERT INTO `site_summaries` (`url_id`,`pages_crawled`,`pages_failed`,`max_depth_reached`,`internal_link_count`,`external_link_count`,`broken_link_count`,`error_link_count`,`login_form_pages`,`created_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?)",
+		)).WithArgs(
+			urlID, 1, 0, 0, 0, 0, 0, 0, 0,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+		).WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectCommit()
+
+		err := repo.SaveSiteResults(urlID, pages, summary)
+		assert.NoError(t, err)
+		assert.Equal(t, urlID, pages[0].Result.URLID)
+		require.NotNil(t, pages[0].Links[0].AnalysisResultID)
+		assert.Equal(t, uint(40), *pages[0].Links[0].AnalysisResultID)
+		assert.Equal(t, uint(7), summary.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("SiteSummary Latest", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		repo := repository.NewURLRepo(db)
+		urlID := uint(12)
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `site_summaries` WHERE url_id = ? AND `site_summaries`.`deleted_at` IS NULL ORDER BY created_at DESC,`site_summaries`.`id` LIMIT ?",
+		)).WithArgs(urlID, 1).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "url_id", "pages_crawled", "broken_link_count"}).
+				AddRow(7, urlID, 25, 3),
+		)
+
+		summary, err := repo.SiteSummary(urlID, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 25, summary.PagesCrawled)
+		assert.Equal(t, 3, summary.BrokenLinkCount)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("SiteSummary Of Snapshot", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		repo := repository.NewURLRepo(db)
+		urlID := uint(12)
+
+		// Snapshot 35 belongs to the run that starts at root snapshot 30; the next run starts at 40.
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `analysis_results` WHERE url_id = ? AND `analysis_results`.`id` = ? AND `analysis_results`.`deleted_at` IS NULL ORDER BY `analysis_results`.`id` LIMIT ?",
+		)).WithArgs(urlID, uint(35), 1).WillReturnRows(sqlmock.NewRows([]string{"id", "url_id"}).AddRow(35, urlID))
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT COALESCE(MAX(id), 0) FROM `analysis_results` WHERE (url_id = ? AND depth = 0) AND id <= ? AND `analysis_results`.`deleted_at` IS NULL",
+		)).WithArgs(urlID, uint(35)).WillReturnRows(sqlmock.NewRows([]string{"COALESCE(MAX(id), 0)"}).AddRow(30))
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT COALESCE(MIN(id), 0) FROM `analysis_results` WHERE (url_id = ? AND depth = 0 AND id > ?) AND `analysis_results`.`deleted_at` IS NULL",
+		)).WithArgs(urlID, uint(30)).WillReturnRows(sqlmock.NewRows([]string{"COALESCE(MIN(id), 0)"}).AddRow(40))
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `site_summaries` WHERE url_id = ? AND created_at >= (SELECT `created_at` FROM `analysis_results` WHERE id = ? AND `analysis_results`.`deleted_at` IS NULL) AND created_at < (SELECT `created_at` FROM `analysis_results` WHERE id = ? AND `analysis_results`.`deleted_at` IS NULL) AND `site_summaries`.`deleted_at` IS NULL ORDER BY created_at DESC,`site_summaries`.`id` LIMIT ?",
+		)).WithArgs(urlID, uint(30), uint(40), 1).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "url_id", "pages_crawled"}).AddRow(5, urlID, 10),
+		)
+
+		summary, err := repo.SiteSummary(urlID, 35)
+		require.NoError(t, err)
+		assert.Equal(t, uint(5), summary.ID)
+		assert.Equal(t, 10, summary.PagesCrawled)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("IDsByUser", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		repo := repository.NewURLRepo(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT `id` FROM `urls` WHERE user_id = ? AND status = ? AND original_url LIKE ? AND `urls`.`deleted_at` IS NULL ORDER BY id LIMIT ?",
+		)).WithArgs(uint(3), "done", `%50\%\_off%`, 11).WillReturnRows(
+			sqlmock.NewRows([]string{"id"}).AddRow(4).AddRow(9),
+		)
+
+		ids, err := repo.IDsByUser(3, model.URLFilter{Status: model.StatusDone, Query: "50%_off"}, 11)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{4, 9}, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("IDsByUser With IDs", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		repo := repository.NewURLRepo(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT `id` FROM `urls` WHERE user_id = ? AND id IN (?,?) AND crawl_mode = ? AND `urls`.`deleted_at` IS NULL ORDER BY id LIMIT ?",
+		)).WithArgs(uint(3), uint(4), uint(5), "site", 11).WillReturnRows(
+			sqlmock.NewRows([]string{"id"}).AddRow(5),
+		)
+
+		ids, err := repo.IDsByUser(3, model.URLFilter{IDs: []uint{4, 5}, CrawlMode: model.CrawlModeSite}, 11)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{5}, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
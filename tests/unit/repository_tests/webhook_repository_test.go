@@ -0,0 +1,80 @@
+package repository_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+)
+
+func TestWebhookRepo(t *testing.T) {
+	t.Run("ActiveByUser", func(t *testing.T) {
+		db, mock := setupLinkMockDB(t)
+		repo := repository.NewWebhookRepo(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `webhooks` WHERE (user_id = ? AND active = ?) AND `webhooks`.`deleted_at` IS NULL",
+		)).WithArgs(uint(3), true).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "target_url", "events", "active"}).
+				AddRow(1, 3, "https://hooks.example.com", `["analysis.completed"]`, true))
+
+		hooks, err := repo.ActiveByUser(3)
+		require.NoError(t, err)
+		require.Len(t, hooks, 1)
+		assert.True(t, hooks[0].Subscribed(model.EventAnalysisCompleted))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("DueDeliveries", func(t *testing.T) {
+		db, mock := setupLinkMockDB(t)
+		repo := repository.NewWebhookRepo(db)
+		now := time.Now()
+
+		mock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `webhook_deliveries` WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at LIMIT ?",
+		)).WithArgs(model.DeliveryPending, now, 100).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "webhook_id", "status", "next_attempt_at"}).
+				AddRow(7, 1, model.DeliveryPending, now.Add(-time.Second)))
+
+		due, err := repo.DueDeliveries(now, 100)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, uint(7), due[0].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ClaimDelivery", func(t *testing.T) {
+		for _, tc := range []struct {
+			name    string
+			rows    int64
+			claimed bool
+		}{
+			{"Claimed", 1, true},
+			{"Taken By Another Dispatcher", 0, false},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				db, mock := setupLinkMockDB(t)
+				repo := repository.NewWebhookRepo(db)
+				prev, until := time.Now(), time.Now().Add(20*time.Second)
+
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"UPDATE `webhook_deliveries` SET `next_attempt_at`=?,`updated_at`=? WHERE id = ? AND status = ? AND next_attempt_at = ?",
+				)).WithArgs(until, sqlmock.AnyArg(), uint(7), model.DeliveryPending, prev).
+					WillReturnResult(sqlmock.NewResult(0, tc.rows))
+				mock.ExpectCommit()
+
+				ok, err := repo.ClaimDelivery(7, prev, until)
+				require.NoError(t, err)
+				assert.Equal(t, tc.claimed, ok)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package scheduler_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+	"github.com/fuzumoe/urlinsight-backend/internal/scheduler"
+)
+
+// fakeScheduleRepo implements repository.ScheduleRepository for testing.
+type fakeScheduleRepo struct {
+	mu       sync.Mutex
+	due      []model.Schedule
+	advanced []uint
+	lost     map[uint]bool
+	dueErr   error
+}
+
+func (r *fakeScheduleRepo) Save(s *model.Schedule) error { return nil }
+func (r *fakeScheduleRepo) FindByURL(userID, urlID uint) (*model.Schedule, error) {
+	return nil, nil
+}
+func (r *fakeScheduleRepo) Delete(urlID uint) error { return nil }
+func (r *fakeScheduleRepo) ListByUser(userID uint, p repository.Pagination) ([]model.ScheduleDTO, error) {
+	return nil, nil
+}
+func (r *fakeScheduleRepo) CountByUser(userID uint) (int, error) { return 0, nil }
+
+func (r *fakeScheduleRepo) Due(now time.Time, limit int) ([]model.Schedule, error) {
+	return r.due, r.dueErr
+}
+
+func (r *fakeScheduleRepo) Advance(id uint, prev, ranAt, next time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lost[id] {
+		return false, nil
+	}
+	r.advanced = append(r.advanced, id)
+	return true, nil
+}
+
+// fakeStarter records the URLs it was asked to start.
+type fakeStarter struct {
+	started []uint
+	err     error
+}
+
+func (s *fakeStarter) Start(id uint) error {
+	s.started = append(s.started, id)
+	return s.err
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("Cron", func(t *testing.T) {
+		assert.NoError(t, scheduler.Validate(&model.Schedule{CronExpr: "0 6 * * *"}))
+	})
+
+	t.Run("Interval", func(t *testing.T) {
+		assert.NoError(t, scheduler.Validate(&model.Schedule{IntervalSeconds: 3600}))
+	})
+
+	t.Run("Neither Or Both", func(t *testing.T) {
+		assert.ErrorIs(t, scheduler.Validate(&model.Schedule{}), scheduler.ErrInvalidSchedule)
+		assert.ErrorIs(t, scheduler.Validate(&model.Schedule{CronExpr: "@daily", IntervalSeconds: 60}), scheduler.ErrInvalidSchedule)
+	})
+
+	t.Run("Bad Cron", func(t *testing.T) {
+		err := scheduler.Validate(&model.Schedule{CronExpr: "every day"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid cron expression")
+	})
+
+	t.Run("Interval Too Short", func(t *testing.T) {
+		assert.Error(t, scheduler.Validate(&model.Schedule{IntervalSeconds: 10}))
+	})
+}
+
+func TestNextRun(t *testing.T) {
+	from := time.Date(2025, 3, 10, 7, 30, 0, 0, time.UTC)
+
+	t.Run("Cron", func(t *testing.T) {
+		next, err := scheduler.NextRun(&model.Schedule{CronExpr: "0 6 * * *"}, from)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2025, 3, 11, 6, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("Interval", func(t *testing.T) {
+		next, err := scheduler.NextRun(&model.Schedule{IntervalSeconds: 900}, from)
+		require.NoError(t, err)
+		assert.Equal(t, from.Add(15*time.Minute), next)
+	})
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+
+	t.Run("Starts Due URLs", func(t *testing.T) {
+		repo := &fakeScheduleRepo{due: []model.Schedule{
+			{ID: 1, URLID: 10, IntervalSeconds: 3600, NextRunAt: &past},
+			{ID: 2, URLID: 20, CronExpr: "@hourly", NextRunAt: &past},
+		}}
+		starter := &fakeStarter{}
+
+		n := scheduler.New(repo, starter, time.Minute).RunDue()
+		assert.Equal(t, 2, n)
+		assert.Equal(t, []uint{10, 20}, starter.started)
+		assert.Equal(t, []uint{1, 2}, repo.advanced)
+	})
+
+	t.Run("Skips Schedules Advanced Elsewhere", func(t *testing.T) {
+		repo := &fakeScheduleRepo{
+			due:  []model.Schedule{{ID: 1, URLID: 10, IntervalSeconds: 3600, NextRunAt: &past}},
+			lost: map[uint]bool{1: true},
+		}
+		starter := &fakeStarter{}
+
+		assert.Equal(t, 0, scheduler.New(repo, starter, time.Minute).RunDue())
+		assert.Empty(t, starter.started, "a schedule claimed by another instance must not run twice")
+	})
+
+	t.Run("Start Error", func(t *testing.T) {
+		repo := &fakeScheduleRepo{due: []model.Schedule{{ID: 1, URLID: 10, IntervalSeconds: 3600, NextRunAt: &past}}}
+		starter := &fakeStarter{err: errors.New("queue full")}
+
+		assert.Equal(t, 0, scheduler.New(repo, starter, time.Minute).RunDue())
+		assert.Equal(t, []uint{1}, repo.advanced, "the schedule still moves on so a failing URL does not spin")
+	})
+
+	t.Run("Due Error", func(t *testing.T) {
+		repo := &fakeScheduleRepo{dueErr: errors.New("db down")}
+		starter := &fakeStarter{}
+
+		assert.Equal(t, 0, scheduler.New(repo, starter, time.Minute).RunDue())
+		assert.Empty(t, starter.started)
+	})
+}
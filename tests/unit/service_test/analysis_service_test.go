@@ -3,6 +3,9 @@ package service_test
 import (
 	"errors"
 	"testing"
+	"time"
+
+	"gorm.io/gorm"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -23,11 +26,47 @@ func (m *MockAnalysisRepo) Create(ar *model.AnalysisResult, links []model.Link)
 	return args.Error(0)
 }
 
+func (m *MockAnalysisRepo) FindURL(userID, id uint) (*model.URL, error) {
+	args := m.Called(userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.URL), args.Error(1)
+}
+
 func (m *MockAnalysisRepo) ListByURL(urlID uint, p repository.Pagination) ([]model.AnalysisResult, error) {
 	args := m.Called(urlID, p)
 	return args.Get(0).([]model.AnalysisResult), args.Error(1)
 }
 
+func (m *MockAnalysisRepo) CountByURL(urlID uint) (int, error) {
+	args := m.Called(urlID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAnalysisRepo) FindByURL(urlID, id uint) (*model.AnalysisResult, error) {
+	args := m.Called(urlID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AnalysisResult), args.Error(1)
+}
+
+func (m *MockAnalysisRepo) Latest(urlID uint, n int) ([]model.AnalysisResult, error) {
+	args := m.Called(urlID, n)
+	return args.Get(0).([]model.AnalysisResult), args.Error(1)
+}
+
+func (m *MockAnalysisRepo) Links(res *model.AnalysisResult) ([]model.Link, error) {
+	args := m.Called(res)
+	return args.Get(0).([]model.Link), args.Error(1)
+}
+
+func (m *MockAnalysisRepo) ExpiringCertificates(userID uint, before time.Time) ([]model.ExpiringCertificateDTO, error) {
+	args := m.Called(userID, before)
+	return args.Get(0).([]model.ExpiringCertificateDTO), args.Error(1)
+}
+
 func TestAnalysisService_Record(t *testing.T) {
 	// Setup
 	mockRepo := new(MockAnalysisRepo)
@@ -168,3 +207,157 @@ func TestAnalysisService_List(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestAnalysisService_History(t *testing.T) {
+	mockRepo := new(MockAnalysisRepo)
+	svc := service.NewAnalysisService(mockRepo)
+	p := repository.Pagination{Page: 1, PageSize: 2}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo.On("FindURL", uint(1), uint(42)).Return(&model.URL{ID: 42, UserID: 1}, nil).Once()
+		mockRepo.On("ListByURL", uint(42), p).Return([]model.AnalysisResult{{ID: 3, URLID: 42, BrokenLinkCount: 4}, {ID: 2, URLID: 42}}, nil).Once()
+		mockRepo.On("CountByURL", uint(42)).Return(3, nil).Once()
+
+		res, err := svc.History(1, 42, p)
+		require.NoError(t, err)
+		require.Len(t, res.Data, 2)
+		assert.Equal(t, uint(3), res.Data[0].ID)
+		assert.Equal(t, 4, res.Data[0].BrokenLinkCount, "history carries the link counts")
+		assert.Equal(t, 3, res.Pagination.TotalItems)
+		assert.Equal(t, 2, res.Pagination.TotalPages)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Other User's URL", func(t *testing.T) {
+		mockRepo := new(MockAnalysisRepo)
+		svc := service.NewAnalysisService(mockRepo)
+		mockRepo.On("FindURL", uint(2), uint(42)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		_, err := svc.History(2, 42, p)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockRepo.AssertNotCalled(t, "ListByURL", uint(42), p)
+	})
+}
+
+func TestAnalysisService_Diff(t *testing.T) {
+	from := &model.AnalysisResult{ID: 1, URLID: 42, Title: "Old", HTMLVersion: "HTML 5", H1Count: 1}
+	to := &model.AnalysisResult{ID: 2, URLID: 42, Title: "New", HTMLVersion: "HTML 5", H1Count: 2}
+	fromLinks := []model.Link{{Href: "https://a.test", StatusCode: 200}}
+	toLinks := []model.Link{{Href: "https://a.test", StatusCode: 404}, {Href: "https://b.test", StatusCode: 200}}
+
+	t.Run("Explicit Snapshots", func(t *testing.T) {
+		mockRepo := new(MockAnalysisRepo)
+		svc := service.NewAnalysisService(mockRepo)
+		mockRepo.On("FindURL", uint(1), uint(42)).Return(&model.URL{ID: 42, UserID: 1}, nil).Once()
+		mockRepo.On("FindByURL", uint(42), uint(1)).Return(from, nil).Once()
+		mockRepo.On("FindByURL", uint(42), uint(2)).Return(to, nil).Once()
+		mockRepo.On("Links", from).Return(fromLinks, nil).Once()
+		mockRepo.On("Links", to).Return(toLinks, nil).Once()
+
+		diff, err := svc.Diff(1, 42, 1, 2)
+		require.NoError(t, err)
+		require.NotNil(t, diff.Title)
+		assert.Equal(t, "New", diff.Title.To)
+		assert.Equal(t, 1, diff.Headings.H1)
+		require.Len(t, diff.NewlyBroken, 1)
+		assert.Len(t, diff.LinksAdded, 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Defaults To Latest Two", func(t *testing.T) {
+		mockRepo := new(MockAnalysisRepo)
+		svc := service.NewAnalysisService(mockRepo)
+		mockRepo.On("FindURL", uint(1), uint(42)).Return(&model.URL{ID: 42, UserID: 1}, nil).Once()
+		mockRepo.On("Latest", uint(42), 2).Return([]model.AnalysisResult{*to, *from}, nil).Once()
+		mockRepo.On("FindByURL", uint(42), uint(1)).Return(from, nil).Once()
+		mockRepo.On("FindByURL", uint(42), uint(2)).Return(to, nil).Once()
+		mockRepo.On("Links", from).Return(fromLinks, nil).Once()
+		mockRepo.On("Links", to).Return(toLinks, nil).Once()
+
+		diff, err := svc.Diff(1, 42, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), diff.From.ID)
+		assert.Equal(t, uint(2), diff.To.ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not Enough Snapshots", func(t *testing.T) {
+		mockRepo := new(MockAnalysisRepo)
+		svc := service.NewAnalysisService(mockRepo)
+		mockRepo.On("FindURL", uint(1), uint(42)).Return(&model.URL{ID: 42, UserID: 1}, nil).Once()
+		mockRepo.On("Latest", uint(42), 2).Return([]model.AnalysisResult{*to}, nil).Once()
+
+		_, err := svc.Diff(1, 42, 0, 0)
+		assert.ErrorIs(t, err, service.ErrNotEnoughSnapshots)
+	})
+
+	t.Run("Only One ID", func(t *testing.T) {
+		mockRepo := new(MockAnalysisRepo)
+		svc := service.NewAnalysisService(mockRepo)
+		mockRepo.On("FindURL", uint(1), uint(42)).Return(&model.URL{ID: 42, UserID: 1}, nil).Once()
+
+		_, err := svc.Diff(1, 42, 1, 0)
+		assert.ErrorIs(t, err, service.ErrSnapshotPair)
+	})
+
+	t.Run("Snapshot Of Another URL", func(t *testing.T) {
+		mockRepo := new(MockAnalysisRepo)
+		svc := service.NewAnalysisService(mockRepo)
+		mockRepo.On("FindURL", uint(1), uint(42)).Return(&model.URL{ID: 42, UserID: 1}, nil).Once()
+		mockRepo.On("FindByURL", uint(42), uint(9)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		_, err := svc.Diff(1, 42, 9, 2)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Other User's URL", func(t *testing.T) {
+		mockRepo := new(MockAnalysisRepo)
+		svc := service.NewAnalysisService(mockRepo)
+		mockRepo.On("FindURL", uint(2), uint(42)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		_, err := svc.Diff(2, 42, 1, 2)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockRepo.AssertNotCalled(t, "FindByURL", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAnalysisService_ExpiringCertificates(t *testing.T) {
+	t.Run("Days Left", func(t *testing.T) {
+		mockRepo := new(MockAnalysisRepo)
+		svc := service.NewAnalysisService(mockRepo)
+		now := time.Now()
+		certs := []model.ExpiringCertificateDTO{
+			{URLID: 1, ExpiresAt: now.Add(-36 * time.Hour)},
+			{URLID: 2, ExpiresAt: now.Add(10*24*time.Hour + time.Hour)},
+		}
+		mockRepo.On("ExpiringCertificates", uint(7), mock.MatchedBy(func(before time.Time) bool {
+			d := before.Sub(now)
+			return d > 29*24*time.Hour && d <= 30*24*time.Hour+time.Minute
+		})).Return(certs, nil).Once()
+
+		out, err := svc.ExpiringCertificates(7, 30)
+		require.NoError(t, err)
+		require.Len(t, out, 2)
+		assert.Equal(t, -2, out[0].DaysLeft, "Expired certificates have negative days left")
+		assert.Equal(t, 10, out[1].DaysLeft)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		mockRepo := new(MockAnalysisRepo)
+		svc := service.NewAnalysisService(mockRepo)
+		mockRepo.On("ExpiringCertificates", uint(7), mock.Anything).
+			Return([]model.ExpiringCertificateDTO(nil), nil).Once()
+
+		out, err := svc.ExpiringCertificates(7, 0)
+		require.NoError(t, err)
+		assert.NotNil(t, out)
+		assert.Empty(t, out)
+	})
+
+	t.Run("Negative Days", func(t *testing.T) {
+		svc := service.NewAnalysisService(new(MockAnalysisRepo))
+		_, err := svc.ExpiringCertificates(7, -1)
+		assert.ErrorIs(t, err, service.ErrInvalidDays)
+	})
+}
@@ -0,0 +1,187 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/importer"
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/service"
+)
+
+// fakeSitemaps returns fixed pages for any sitemap.
+type fakeSitemaps struct {
+	pages []string
+	err   error
+	max   int
+}
+
+func (f *fakeSitemaps) Fetch(ctx context.Context, sitemapURL string, max int) ([]string, error) {
+	f.max = max
+	return f.pages, f.err
+}
+
+// newBulkService wires a bulk service over a mocked repository whose Create assigns IDs
+// from 100 upwards.
+func newBulkService(sitemaps service.SitemapFetcher) (service.BulkService, *MockURLRepo) {
+	repo := new(MockURLRepo)
+	next := uint(100)
+	repo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*model.URL).ID = next
+		next++
+	}).Return(nil).Maybe()
+	return service.NewBulkService(repo, service.NewURLService(repo, &DummyCrawlerPool{}), sitemaps), repo
+}
+
+func TestBulkService_Import(t *testing.T) {
+	t.Run("Rows", func(t *testing.T) {
+		svc, _ := newBulkService(&fakeSitemaps{})
+		entries := importer.FromStrings([]string{
+			"example.com",
+			"HTTPS://Example.com:443/pricing#plans",
+			"ftp://example.com/file",
+			"https://example.com",
+			"http://",
+		})
+
+		res, err := svc.Import(context.Background(), 1, entries, "", model.BulkImportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, res.Created)
+		assert.Equal(t, 3, res.Failed)
+		assert.Zero(t, res.Started)
+		require.Len(t, res.Rows, 5)
+
+		assert.Equal(t, model.BulkRowResultDTO{Row: 1, Input: "example.com", URL: "https://example.com", ID: 100}, res.Rows[0])
+		assert.Equal(t, "https://example.com/pricing", res.Rows[1].URL)
+		assert.Equal(t, uint(101), res.Rows[1].ID)
+		assert.Equal(t, `unsupported scheme "ftp"`, res.Rows[2].Error)
+		assert.Equal(t, "duplicate of row 1", res.Rows[3].Error)
+		assert.Zero(t, res.Rows[3].ID)
+		assert.Equal(t, "missing host", res.Rows[4].Error)
+	})
+
+	t.Run("Sitemap Rows Follow The List", func(t *testing.T) {
+		sm := &fakeSitemaps{pages: []string{"https://example.com/a", "https://example.com/b"}}
+		svc, _ := newBulkService(sm)
+
+		res, err := svc.Import(context.Background(), 1, importer.FromStrings([]string{"https://example.com"}),
+			"https://example.com/sitemap.xml", model.BulkImportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 3, res.Created)
+		assert.Equal(t, 3, res.Rows[2].Row)
+		assert.Equal(t, "https://example.com/b", res.Rows[2].URL)
+		assert.Equal(t, model.MaxBulkItems-1, sm.max, "the sitemap gets what is left of the budget")
+	})
+
+	t.Run("Sitemap Error", func(t *testing.T) {
+		svc, _ := newBulkService(&fakeSitemaps{err: errors.New("fetch sitemap: unexpected status 404")})
+
+		_, err := svc.Import(context.Background(), 1, nil, "https://example.com/sitemap.xml", model.BulkImportOptions{})
+		assert.EqualError(t, err, "fetch sitemap: unexpected status 404")
+	})
+
+	t.Run("Start", func(t *testing.T) {
+		svc, repo := newBulkService(&fakeSitemaps{})
+		repo.On("FindByID", uint(100)).Return(&model.URL{ID: 100}, nil).Once()
+		repo.On("UpdateStatus", uint(100), model.StatusQueued).Return(nil).Once()
+		repo.On("FindByID", uint(101)).Return(nil, errors.New("db down")).Once()
+
+		res, err := svc.Import(context.Background(), 1, importer.FromStrings([]string{"a.example", "b.example"}), "",
+			model.BulkImportOptions{CrawlMode: model.CrawlModeSite, MaxDepth: 2, Start: true})
+		require.NoError(t, err)
+		assert.Equal(t, 2, res.Created)
+		assert.Equal(t, 1, res.Started)
+		assert.Empty(t, res.Rows[0].Error)
+		assert.Contains(t, res.Rows[1].Error, "created but not started")
+		repo.AssertCalled(t, "Create", mock.MatchedBy(func(u *model.URL) bool {
+			return u.CrawlMode == model.CrawlModeSite && u.MaxDepth == 2 && u.UserID == 1
+		}))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Options", func(t *testing.T) {
+		svc, repo := newBulkService(&fakeSitemaps{})
+
+		_, err := svc.Import(context.Background(), 1, importer.FromStrings([]string{"example.com"}), "",
+			model.BulkImportOptions{CrawlMode: model.CrawlModeSite, Modules: []string{"seo"}})
+		assert.EqualError(t, err, `site crawls need the "links" module`)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Nothing To Import", func(t *testing.T) {
+		svc, _ := newBulkService(&fakeSitemaps{})
+
+		_, err := svc.Import(context.Background(), 1, nil, "", model.BulkImportOptions{})
+		assert.EqualError(t, err, "no URLs to import")
+	})
+}
+
+func TestBulkService_Actions(t *testing.T) {
+	t.Run("Delete By IDs", func(t *testing.T) {
+		svc, repo := newBulkService(&fakeSitemaps{})
+		repo.On("IDsByUser", uint(1), model.URLFilter{IDs: []uint{3, 9, 4}}, model.MaxBulkItems+1).
+			Return([]uint{3, 4}, nil).Once()
+		repo.On("Delete", uint(3)).Return(nil).Once()
+		repo.On("Delete", uint(4)).Return(errors.New("url not found")).Once()
+
+		res, err := svc.Delete(1, &model.BulkActionRequestDTO{IDs: []uint{3, 9, 4, 3}})
+		require.NoError(t, err)
+		assert.Equal(t, 2, res.Matched)
+		assert.Equal(t, 1, res.Succeeded)
+		assert.Equal(t, 2, res.Failed)
+		assert.Equal(t, []model.BulkItemResultDTO{
+			{ID: 3},
+			{ID: 9, Error: "not found"},
+			{ID: 4, Error: "url not found"},
+		}, res.Results)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Stop By Filter", func(t *testing.T) {
+		svc, repo := newBulkService(&fakeSitemaps{})
+		f := model.URLFilter{Status: model.StatusRunning, Query: "shop"}
+		repo.On("IDsByUser", uint(1), f, model.MaxBulkItems+1).Return([]uint{5}, nil).Once()
+		repo.On("FindByID", uint(5)).Return(&model.URL{ID: 5}, nil).Once()
+		repo.On("UpdateStatus", uint(5), model.StatusStopped).Return(nil).Once()
+
+		res, err := svc.Stop(1, &model.BulkActionRequestDTO{
+			Filter:      &model.URLFilterDTO{Status: model.StatusRunning, Query: "shop"},
+			KeepPartial: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, res.Succeeded)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Filter Matches Too Many", func(t *testing.T) {
+		svc, repo := newBulkService(&fakeSitemaps{})
+		repo.On("IDsByUser", uint(1), mock.Anything, model.MaxBulkItems+1).
+			Return(make([]uint, model.MaxBulkItems+1), nil).Once()
+
+		_, err := svc.Start(1, &model.BulkActionRequestDTO{Filter: &model.URLFilterDTO{CrawlMode: model.CrawlModePage}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "narrow it down")
+	})
+
+	for _, tc := range []struct {
+		name string
+		req  model.BulkActionRequestDTO
+		err  string
+	}{
+		{"Empty", model.BulkActionRequestDTO{}, "ids or filter is required"},
+		{"Both", model.BulkActionRequestDTO{IDs: []uint{1}, Filter: &model.URLFilterDTO{Status: model.StatusDone}}, "give either ids or filter, not both"},
+		{"Empty Filter", model.BulkActionRequestDTO{Filter: &model.URLFilterDTO{}}, "filter needs at least one of status, crawl_mode or q"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			svc, repo := newBulkService(&fakeSitemaps{})
+			_, err := svc.Delete(1, &tc.req)
+			assert.EqualError(t, err, tc.err)
+			repo.AssertNotCalled(t, "IDsByUser", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
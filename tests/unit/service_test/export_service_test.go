@@ -0,0 +1,195 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/service"
+)
+
+// MockExportRepo is a mock implementation of repository.ExportRepository. The Each methods
+// feed fn the records given as the call's first return value.
+type MockExportRepo struct {
+	mock.Mock
+}
+
+func (m *MockExportRepo) FindURL(userID, id uint) (*model.URL, error) {
+	args := m.Called(userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.URL), args.Error(1)
+}
+
+func (m *MockExportRepo) RunBounds(urlID, snapshotID uint) (uint, uint, error) {
+	args := m.Called(urlID, snapshotID)
+	return args.Get(0).(uint), args.Get(1).(uint), args.Error(2)
+}
+
+func (m *MockExportRepo) EachURL(userID uint, fn func(u *model.URL, latest *model.AnalysisResult) error) error {
+	args := m.Called(userID)
+	latest := args.Get(1).([]*model.AnalysisResult)
+	for i, u := range args.Get(0).([]*model.URL) {
+		if err := fn(u, latest[i]); err != nil {
+			return err
+		}
+	}
+	return args.Error(2)
+}
+
+func (m *MockExportRepo) EachSnapshot(urlID uint, fn func(res *model.AnalysisResult) error) error {
+	args := m.Called(urlID)
+	for _, res := range args.Get(0).([]*model.AnalysisResult) {
+		if err := fn(res); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockExportRepo) EachLink(urlID, start, end uint, linkError string, fn func(l *model.Link) error) error {
+	args := m.Called(urlID, start, end, linkError)
+	for _, l := range args.Get(0).([]*model.Link) {
+		if err := fn(l); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+// tableWriter records what an export writes.
+type tableWriter struct {
+	columns []string
+	rows    [][]any
+	closed  bool
+}
+
+func (w *tableWriter) Header(columns []string) error {
+	w.columns = columns
+	return nil
+}
+
+func (w *tableWriter) Row(values []any) error {
+	w.rows = append(w.rows, values)
+	return nil
+}
+
+func (w *tableWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+// cell returns the value of column name in row i.
+func (w *tableWriter) cell(i int, name string) any {
+	for c, col := range w.columns {
+		if col == name {
+			return w.rows[i][c]
+		}
+	}
+	return "no such column"
+}
+
+func TestExportService(t *testing.T) {
+	at := time.Date(2025, 7, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("URLs", func(t *testing.T) {
+		repo := new(MockExportRepo)
+		svc := service.NewExportService(repo)
+		repo.On("EachURL", uint(1)).Return(
+			[]*model.URL{
+				{ID: 4, OriginalURL: "https://a.example", Status: model.StatusQueued},
+				{ID: 9, OriginalURL: "https://b.example", Status: model.StatusDone},
+			},
+			[]*model.AnalysisResult{
+				nil,
+				{ID: 30, StatusCode: 200, Title: "B", BrokenLinkCount: 2, Timing: model.PageTiming{TTFBMs: 85}, CreatedAt: at},
+			},
+			nil,
+		)
+
+		w := &tableWriter{}
+		require.NoError(t, svc.URLs(1, w))
+		assert.True(t, w.closed)
+		require.Len(t, w.rows, 2)
+		for _, row := range w.rows {
+			assert.Len(t, row, len(w.columns))
+		}
+		assert.Equal(t, "https://a.example", w.cell(0, "original_url"))
+		assert.Nil(t, w.cell(0, "snapshot_id"))
+		assert.Equal(t, uint(30), w.cell(1, "snapshot_id"))
+		assert.Equal(t, at, w.cell(1, "analyzed_at"))
+		assert.Equal(t, 2, w.cell(1, "broken_link_count"))
+		assert.Equal(t, int64(85), w.cell(1, "ttfb_ms"))
+	})
+
+	t.Run("Snapshots", func(t *testing.T) {
+		repo := new(MockExportRepo)
+		svc := service.NewExportService(repo)
+		repo.On("FindURL", uint(1), uint(4)).Return(&model.URL{ID: 4}, nil)
+		repo.On("EachSnapshot", uint(4)).Return([]*model.AnalysisResult{{
+			ID: 30, URLID: 4, PageURL: "https://a.example/", H2Count: 3,
+			Redirects: &model.RedirectChain{FinalURL: "https://a.example/", Hops: make([]model.RedirectHop, 2)},
+		}}, nil)
+
+		w := &tableWriter{}
+		require.NoError(t, svc.Snapshots(1, 4, w))
+		require.Len(t, w.rows, 1)
+		assert.Len(t, w.rows[0], len(w.columns))
+		assert.Equal(t, 3, w.cell(0, "h2_count"))
+		assert.Equal(t, "https://a.example/", w.cell(0, "final_url"))
+		assert.Equal(t, 2, w.cell(0, "redirect_hops"))
+	})
+
+	t.Run("Links", func(t *testing.T) {
+		repo := new(MockExportRepo)
+		svc := service.NewExportService(repo)
+		snap := uint(30)
+		repo.On("FindURL", uint(1), uint(4)).Return(&model.URL{ID: 4}, nil)
+		repo.On("RunBounds", uint(4), uint(31)).Return(uint(30), uint(40), nil)
+		repo.On("EachLink", uint(4), uint(30), uint(40), model.LinkErrorAny).Return([]*model.Link{
+			{ID: 100, AnalysisResultID: &snap, Href: "https://gone.example", ErrorCategory: model.LinkErrDNS},
+			{ID: 101, Href: "https://legacy.example", StatusCode: 404},
+		}, nil)
+
+		w := &tableWriter{}
+		require.NoError(t, svc.Links(1, 4, 31, model.LinkErrorAny, w))
+		require.Len(t, w.rows, 2)
+		assert.Equal(t, uint(30), w.cell(0, "snapshot_id"))
+		assert.Equal(t, model.LinkErrDNS, w.cell(0, "error_category"))
+		assert.Nil(t, w.cell(1, "snapshot_id"))
+		assert.Equal(t, 0, w.cell(1, "redirect_hops"))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Not Found Writes Nothing", func(t *testing.T) {
+		repo := new(MockExportRepo)
+		svc := service.NewExportService(repo)
+		repo.On("FindURL", uint(2), uint(4)).Return(nil, gorm.ErrRecordNotFound)
+		repo.On("FindURL", uint(1), uint(4)).Return(&model.URL{ID: 4}, nil)
+		repo.On("RunBounds", uint(4), uint(99)).Return(uint(0), uint(0), gorm.ErrRecordNotFound)
+
+		w := &tableWriter{}
+		assert.ErrorIs(t, svc.Snapshots(2, 4, w), gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, svc.Links(2, 4, 0, "", w), gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, svc.Links(1, 4, 99, "", w), gorm.ErrRecordNotFound)
+		assert.Nil(t, w.columns)
+		repo.AssertNotCalled(t, "EachLink", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure Leaves Writer Open", func(t *testing.T) {
+		repo := new(MockExportRepo)
+		svc := service.NewExportService(repo)
+		repo.On("EachURL", uint(1)).Return([]*model.URL{}, []*model.AnalysisResult{}, errors.New("connection lost"))
+
+		w := &tableWriter{}
+		assert.EqualError(t, svc.URLs(1, w), "connection lost")
+		assert.False(t, w.closed)
+	})
+}
@@ -0,0 +1,221 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+	"github.com/fuzumoe/urlinsight-backend/internal/service"
+)
+
+// MockScheduleRepo mocks repository.ScheduleRepository.
+type MockScheduleRepo struct {
+	mock.Mock
+}
+
+func (m *MockScheduleRepo) Save(s *model.Schedule) error {
+	args := m.Called(s)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepo) FindByURL(userID, urlID uint) (*model.Schedule, error) {
+	args := m.Called(userID, urlID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleRepo) Delete(urlID uint) error {
+	args := m.Called(urlID)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepo) ListByUser(userID uint, p repository.Pagination) ([]model.ScheduleDTO, error) {
+	args := m.Called(userID, p)
+	return args.Get(0).([]model.ScheduleDTO), args.Error(1)
+}
+
+func (m *MockScheduleRepo) CountByUser(userID uint) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockScheduleRepo) Due(now time.Time, limit int) ([]model.Schedule, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]model.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleRepo) Advance(id uint, prev, ranAt, next time.Time) (bool, error) {
+	args := m.Called(id, prev, ranAt, next)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestScheduleService_Set(t *testing.T) {
+	userID, urlID := uint(1), uint(7)
+
+	t.Run("Interval", func(t *testing.T) {
+		repo, urlRepo := new(MockScheduleRepo), new(MockURLRepo)
+		svc := service.NewScheduleService(repo, urlRepo)
+
+		urlRepo.On("FindByID", urlID).Return(&model.URL{ID: urlID, UserID: userID}, nil).Once()
+		repo.On("Save", mock.MatchedBy(func(s *model.Schedule) bool {
+			return s.URLID == urlID && s.IntervalSeconds == 3600 && s.NextRunAt != nil
+		})).Return(nil).Once()
+
+		before := time.Now()
+		dto, err := svc.Set(userID, urlID, &model.ScheduleInputDTO{IntervalSeconds: 3600})
+		require.NoError(t, err)
+		require.NotNil(t, dto.NextRunAt)
+		assert.WithinDuration(t, before.Add(time.Hour), *dto.NextRunAt, 5*time.Second)
+		repo.AssertExpectations(t)
+		urlRepo.AssertExpectations(t)
+	})
+
+	t.Run("Paused Has No Next Run", func(t *testing.T) {
+		repo, urlRepo := new(MockScheduleRepo), new(MockURLRepo)
+		svc := service.NewScheduleService(repo, urlRepo)
+
+		urlRepo.On("FindByID", urlID).Return(&model.URL{ID: urlID, UserID: userID}, nil).Once()
+		repo.On("Save", mock.Anything).Return(nil).Once()
+
+		dto, err := svc.Set(userID, urlID, &model.ScheduleInputDTO{Cron: "0 6 * * *", Paused: true})
+		require.NoError(t, err)
+		assert.True(t, dto.Paused)
+		assert.Nil(t, dto.NextRunAt)
+	})
+
+	t.Run("Invalid Schedule", func(t *testing.T) {
+		repo, urlRepo := new(MockScheduleRepo), new(MockURLRepo)
+		svc := service.NewScheduleService(repo, urlRepo)
+
+		urlRepo.On("FindByID", urlID).Return(&model.URL{ID: urlID, UserID: userID}, nil).Once()
+
+		_, err := svc.Set(userID, urlID, &model.ScheduleInputDTO{Cron: "not a cron"})
+		assert.Error(t, err)
+		repo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("URL Not Found", func(t *testing.T) {
+		repo, urlRepo := new(MockScheduleRepo), new(MockURLRepo)
+		svc := service.NewScheduleService(repo, urlRepo)
+
+		urlRepo.On("FindByID", urlID).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		_, err := svc.Set(userID, urlID, &model.ScheduleInputDTO{IntervalSeconds: 3600})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Other User's URL", func(t *testing.T) {
+		repo, urlRepo := new(MockScheduleRepo), new(MockURLRepo)
+		svc := service.NewScheduleService(repo, urlRepo)
+
+		urlRepo.On("FindByID", urlID).Return(&model.URL{ID: urlID, UserID: 2}, nil).Once()
+
+		_, err := svc.Set(userID, urlID, &model.ScheduleInputDTO{IntervalSeconds: 3600})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		repo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+}
+
+func TestScheduleService_PauseResume(t *testing.T) {
+	userID, urlID := uint(1), uint(7)
+
+	t.Run("Pause", func(t *testing.T) {
+		repo := new(MockScheduleRepo)
+		svc := service.NewScheduleService(repo, new(MockURLRepo))
+		next := time.Now().Add(time.Hour)
+
+		repo.On("FindByURL", userID, urlID).Return(&model.Schedule{URLID: urlID, IntervalSeconds: 3600, NextRunAt: &next}, nil).Once()
+		repo.On("Save", mock.MatchedBy(func(s *model.Schedule) bool { return s.Paused && s.NextRunAt == nil })).Return(nil).Once()
+
+		dto, err := svc.Pause(userID, urlID)
+		require.NoError(t, err)
+		assert.True(t, dto.Paused)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		repo := new(MockScheduleRepo)
+		svc := service.NewScheduleService(repo, new(MockURLRepo))
+
+		repo.On("FindByURL", userID, urlID).Return(&model.Schedule{URLID: urlID, IntervalSeconds: 3600, Paused: true}, nil).Once()
+		repo.On("Save", mock.MatchedBy(func(s *model.Schedule) bool { return !s.Paused && s.NextRunAt != nil })).Return(nil).Once()
+
+		dto, err := svc.Resume(userID, urlID)
+		require.NoError(t, err)
+		assert.False(t, dto.Paused)
+		assert.NotNil(t, dto.NextRunAt)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		repo := new(MockScheduleRepo)
+		svc := service.NewScheduleService(repo, new(MockURLRepo))
+
+		repo.On("FindByURL", userID, urlID).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		_, err := svc.Pause(userID, urlID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestScheduleService_Delete(t *testing.T) {
+	userID, urlID := uint(1), uint(7)
+
+	t.Run("Success", func(t *testing.T) {
+		repo := new(MockScheduleRepo)
+		svc := service.NewScheduleService(repo, new(MockURLRepo))
+
+		repo.On("FindByURL", userID, urlID).Return(&model.Schedule{URLID: urlID}, nil).Once()
+		repo.On("Delete", urlID).Return(nil).Once()
+
+		require.NoError(t, svc.Delete(userID, urlID))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Other User's Schedule", func(t *testing.T) {
+		repo := new(MockScheduleRepo)
+		svc := service.NewScheduleService(repo, new(MockURLRepo))
+
+		repo.On("FindByURL", uint(2), urlID).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		assert.ErrorIs(t, svc.Delete(2, urlID), gorm.ErrRecordNotFound)
+		repo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+}
+
+func TestScheduleService_List(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := new(MockScheduleRepo)
+		svc := service.NewScheduleService(repo, new(MockURLRepo))
+		p := repository.Pagination{Page: 1, PageSize: 10}
+
+		repo.On("ListByUser", uint(1), p).Return([]model.ScheduleDTO{{URLID: 3, IntervalSeconds: 60}}, nil).Once()
+		repo.On("CountByUser", uint(1)).Return(1, nil).Once()
+
+		res, err := svc.List(1, p)
+		require.NoError(t, err)
+		require.Len(t, res.Data, 1)
+		assert.Equal(t, uint(3), res.Data[0].URLID)
+		assert.Equal(t, 1, res.Pagination.TotalPages)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		repo := new(MockScheduleRepo)
+		svc := service.NewScheduleService(repo, new(MockURLRepo))
+		p := repository.Pagination{Page: 1, PageSize: 10}
+
+		repo.On("ListByUser", uint(1), p).Return([]model.ScheduleDTO(nil), errors.New("db down")).Once()
+
+		_, err := svc.List(1, p)
+		assert.Error(t, err)
+	})
+}
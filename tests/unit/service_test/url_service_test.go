@@ -19,9 +19,10 @@ import (
 // DummyCrawlerPool updated to match new interface
 type DummyCrawlerPool struct{}
 
-func (d *DummyCrawlerPool) Start(ctx context.Context) {}
-func (d *DummyCrawlerPool) Enqueue(id uint)           {}
-func (d *DummyCrawlerPool) Shutdown()                 {}
+func (d *DummyCrawlerPool) Start(ctx context.Context)             {}
+func (d *DummyCrawlerPool) Enqueue(id uint) error                 { return nil }
+func (d *DummyCrawlerPool) Cancel(id uint, keepPartial bool) bool { return false }
+func (d *DummyCrawlerPool) Shutdown()                             {}
 
 // MockCrawlerPool updated to match new interface
 type MockCrawlerPool struct {
@@ -31,8 +32,13 @@ type MockCrawlerPool struct {
 func (m *MockCrawlerPool) Start(ctx context.Context) {
 	m.Called(ctx)
 }
-func (m *MockCrawlerPool) Enqueue(id uint) {
-	m.Called(id)
+func (m *MockCrawlerPool) Enqueue(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+func (m *MockCrawlerPool) Cancel(id uint, keepPartial bool) bool {
+	args := m.Called(id, keepPartial)
+	return args.Bool(0)
 }
 func (m *MockCrawlerPool) Shutdown() {
 	m.Called()
@@ -56,6 +62,11 @@ func (m *MockURLRepo) FindByID(id uint) (*model.URL, error) {
 	return args.Get(0).(*model.URL), args.Error(1)
 }
 
+func (m *MockURLRepo) Status(id uint) (string, error) {
+	args := m.Called(id)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockURLRepo) ListByUser(userID uint, p repository.Pagination) ([]model.URL, error) {
 	args := m.Called(userID, p)
 	return args.Get(0).([]model.URL), args.Error(1)
@@ -95,14 +106,32 @@ func (m *MockURLRepo) SaveResults(urlID uint, analysisRes *model.AnalysisResult,
 }
 
 // New method added to fully implement repository.URLRepository.
-func (m *MockURLRepo) ResultsWithDetails(id uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error) {
-	args := m.Called(id)
+func (m *MockURLRepo) ResultsWithDetails(id, snapshotID uint) (*model.URL, []*model.AnalysisResult, []*model.Link, error) {
+	args := m.Called(id, snapshotID)
 	if args.Get(0) == nil {
 		return nil, nil, nil, args.Error(3)
 	}
 	return args.Get(0).(*model.URL), args.Get(1).([]*model.AnalysisResult), args.Get(2).([]*model.Link), args.Error(3)
 }
 
+func (m *MockURLRepo) SaveSiteResults(id uint, pages []model.PageResult, summary *model.SiteSummary) error {
+	args := m.Called(id, pages, summary)
+	return args.Error(0)
+}
+
+func (m *MockURLRepo) IDsByUser(userID uint, f model.URLFilter, limit int) ([]uint, error) {
+	args := m.Called(userID, f, limit)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockURLRepo) SiteSummary(id, snapshotID uint) (*model.SiteSummary, error) {
+	args := m.Called(id, snapshotID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SiteSummary), args.Error(1)
+}
+
 func TestURLService_Create(t *testing.T) {
 	mockRepo := new(MockURLRepo)
 	dummyPool := &DummyCrawlerPool{}
@@ -146,6 +175,59 @@ func TestURLService_Create(t *testing.T) {
 		assert.Equal(t, uint(0), id)
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("Invalid Request Header", func(t *testing.T) {
+		bad := &model.CreateURLInputDTO{
+			UserID:         1,
+			OriginalURL:    "https://example.com",
+			RequestHeaders: map[string]string{"Bad Header": "x"},
+		}
+
+		id, err := svc.Create(bad)
+		assert.EqualError(t, err, `invalid request header "Bad Header"`)
+		assert.Equal(t, uint(0), id)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Module", func(t *testing.T) {
+		bad := &model.CreateURLInputDTO{
+			UserID:      1,
+			OriginalURL: "https://example.com",
+			Modules:     []string{"seo", "spellcheck"},
+		}
+
+		id, err := svc.Create(bad)
+		assert.EqualError(t, err, `unknown analysis module "spellcheck"`)
+		assert.Equal(t, uint(0), id)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Site Crawl Without Links", func(t *testing.T) {
+		bad := &model.CreateURLInputDTO{
+			UserID:      1,
+			OriginalURL: "https://example.com",
+			CrawlMode:   model.CrawlModeSite,
+			Modules:     []string{"seo"},
+		}
+
+		id, err := svc.Create(bad)
+		assert.EqualError(t, err, `site crawls need the "links" module`)
+		assert.Equal(t, uint(0), id)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Assertion", func(t *testing.T) {
+		bad := &model.CreateURLInputDTO{
+			UserID:      1,
+			OriginalURL: "https://example.com",
+			Assertions:  []model.AssertionRule{{Type: model.AssertSelectorExists, Selector: "#checkout["}},
+		}
+
+		id, err := svc.Create(bad)
+		assert.EqualError(t, err, `assertion 0: invalid selector "#checkout["`)
+		assert.Equal(t, uint(0), id)
+		mockRepo.AssertExpectations(t)
+	})
 }
 
 func TestURLService_Get(t *testing.T) {
@@ -186,6 +268,19 @@ func TestURLService_Get(t *testing.T) {
 	})
 }
 
+func TestURLService_Status(t *testing.T) {
+	mockRepo := new(MockURLRepo)
+	svc := service.NewURLService(mockRepo, &DummyCrawlerPool{})
+
+	mockRepo.On("Status", uint(42)).Return(model.StatusRunning, nil).Once()
+
+	status, err := svc.Status(42)
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusRunning, status)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+}
+
 func TestURLService_List(t *testing.T) {
 	mockRepo := new(MockURLRepo)
 	dummyPool := &DummyCrawlerPool{}
@@ -273,6 +368,17 @@ func TestURLService_List(t *testing.T) {
 		assert.Equal(t, 3, result.Pagination.TotalPages) // Ceil(21/10) = 3
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("Zero Page Size Uses Default", func(t *testing.T) {
+		unsized := repository.Pagination{Page: 1}
+		mockRepo.On("ListByUser", userID, unsized).Return(urls, nil).Once()
+		mockRepo.On("CountByUser", userID).Return(21, nil).Once()
+
+		result, err := svc.List(userID, unsized)
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Pagination.TotalPages)
+		mockRepo.AssertExpectations(t)
+	})
 }
 
 func TestURLService_Update(t *testing.T) {
@@ -315,6 +421,94 @@ func TestURLService_Update(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("Update Request Options", func(t *testing.T) {
+		existingURL := &model.URL{
+			ID:             urlID,
+			UserID:         1,
+			OriginalURL:    "https://staging.example.com",
+			Status:         "queued",
+			RequestHeaders: map[string]string{"X-Old": "1"},
+			RequestCookies: map[string]string{"session": "abc"},
+		}
+		input := &model.UpdateURLInput{RequestHeaders: map[string]string{"X-Env": "staging"}}
+
+		mockRepo.On("FindByID", urlID).Return(existingURL, nil).Once()
+		mockRepo.On("Update", mock.MatchedBy(func(u *model.URL) bool {
+			return u.RequestHeaders["X-Env"] == "staging" && u.RequestHeaders["X-Old"] == "" &&
+				u.RequestCookies["session"] == "abc"
+		})).Return(nil).Once()
+
+		err := svc.Update(urlID, input)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Update Modules", func(t *testing.T) {
+		existingURL := &model.URL{ID: urlID, UserID: 1, OriginalURL: "https://example.com", Status: "queued"}
+		input := &model.UpdateURLInput{Modules: []string{"headings", "seo"}}
+
+		mockRepo.On("FindByID", urlID).Return(existingURL, nil).Once()
+		mockRepo.On("Update", mock.MatchedBy(func(u *model.URL) bool {
+			return len(u.Modules) == 2 && u.Modules[0] == "headings" && u.Modules[1] == "seo"
+		})).Return(nil).Once()
+
+		err := svc.Update(urlID, input)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Update Assertions", func(t *testing.T) {
+		existingURL := &model.URL{
+			ID: urlID, UserID: 1, OriginalURL: "https://example.com", Status: "queued",
+			Assertions: []model.AssertionRule{{Type: model.AssertMaxBrokenLinks}},
+		}
+		input := &model.UpdateURLInput{Assertions: []model.AssertionRule{
+			{Type: model.AssertTitleMatches, Pattern: "^Shop"},
+		}}
+
+		mockRepo.On("FindByID", urlID).Return(existingURL, nil).Once()
+		mockRepo.On("Update", mock.MatchedBy(func(u *model.URL) bool {
+			return len(u.Assertions) == 1 && u.Assertions[0].Type == model.AssertTitleMatches
+		})).Return(nil).Once()
+
+		err := svc.Update(urlID, input)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Assertion", func(t *testing.T) {
+		existingURL := &model.URL{ID: urlID, UserID: 1, OriginalURL: "https://example.com", Status: "queued"}
+		input := &model.UpdateURLInput{Assertions: []model.AssertionRule{{Type: "spellcheck"}}}
+
+		mockRepo.On("FindByID", urlID).Return(existingURL, nil).Once()
+		err := svc.Update(urlID, input)
+		assert.EqualError(t, err, `assertion 0: unknown type "spellcheck"`)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Switch To Site Crawl Without Links", func(t *testing.T) {
+		existingURL := &model.URL{
+			ID: urlID, UserID: 1, OriginalURL: "https://example.com", Status: "queued",
+			Modules: []string{"seo"},
+		}
+		input := &model.UpdateURLInput{CrawlMode: model.CrawlModeSite}
+
+		mockRepo.On("FindByID", urlID).Return(existingURL, nil).Once()
+		err := svc.Update(urlID, input)
+		assert.EqualError(t, err, `site crawls need the "links" module`)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Reserved Request Header", func(t *testing.T) {
+		existingURL := &model.URL{ID: urlID, UserID: 1, OriginalURL: "https://example.com", Status: "queued"}
+		input := &model.UpdateURLInput{RequestHeaders: map[string]string{"Host": "evil.test"}}
+
+		mockRepo.On("FindByID", urlID).Return(existingURL, nil).Once()
+		err := svc.Update(urlID, input)
+		assert.EqualError(t, err, `request header "Host" cannot be set`)
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("Invalid Status", func(t *testing.T) {
 		existingURL := &model.URL{
 			ID:          urlID,
@@ -399,7 +593,7 @@ func TestURLService_Start(t *testing.T) {
 
 		mockRepo.On("FindByID", urlID).Return(testURL, nil).Once()
 		mockRepo.On("UpdateStatus", urlID, model.StatusQueued).Return(nil).Once()
-		mockPool.On("Enqueue", urlID).Return().Once()
+		mockPool.On("Enqueue", urlID).Return(nil).Once()
 
 		err := svc.Start(urlID)
 		assert.NoError(t, err)
@@ -433,12 +627,31 @@ func TestURLService_Start(t *testing.T) {
 		assert.Equal(t, expectedErr, err)
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("Enqueue Error", func(t *testing.T) {
+		testURL := &model.URL{
+			ID:          urlID,
+			OriginalURL: "http://example.com",
+			Status:      model.StatusQueued,
+		}
+		expectedErr := errors.New("queue unavailable")
+		mockRepo.On("FindByID", urlID).Return(testURL, nil).Once()
+		mockRepo.On("UpdateStatus", urlID, model.StatusQueued).Return(nil).Once()
+		mockPool.On("Enqueue", urlID).Return(expectedErr).Once()
+
+		err := svc.Start(urlID)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot queue crawling")
+		assert.ErrorIs(t, err, expectedErr)
+		mockRepo.AssertExpectations(t)
+		mockPool.AssertExpectations(t)
+	})
 }
 
 func TestURLService_Stop(t *testing.T) {
 	mockRepo := new(MockURLRepo)
-	dummyPool := &DummyCrawlerPool{}
-	svc := service.NewURLService(mockRepo, dummyPool)
+	mockPool := new(MockCrawlerPool)
+	svc := service.NewURLService(mockRepo, mockPool)
 	urlID := uint(100)
 
 	t.Run("Success", func(t *testing.T) {
@@ -449,18 +662,37 @@ func TestURLService_Stop(t *testing.T) {
 		}
 
 		mockRepo.On("FindByID", urlID).Return(testURL, nil).Once()
-		mockRepo.On("UpdateStatus", urlID, model.StatusError).Return(nil).Once()
+		mockRepo.On("UpdateStatus", urlID, model.StatusStopped).Return(nil).Once()
+		mockPool.On("Cancel", urlID, false).Return(true).Once()
 
-		err := svc.Stop(urlID)
+		err := svc.Stop(urlID, false)
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
+		mockPool.AssertExpectations(t)
+	})
+
+	t.Run("Keep Partial", func(t *testing.T) {
+		testURL := &model.URL{
+			ID:          urlID,
+			OriginalURL: "http://example.com",
+			Status:      model.StatusRunning,
+		}
+
+		mockRepo.On("FindByID", urlID).Return(testURL, nil).Once()
+		mockRepo.On("UpdateStatus", urlID, model.StatusStopped).Return(nil).Once()
+		mockPool.On("Cancel", urlID, true).Return(true).Once()
+
+		err := svc.Stop(urlID, true)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockPool.AssertExpectations(t)
 	})
 
 	t.Run("URL Not Found", func(t *testing.T) {
 		expectedErr := errors.New("record not found")
 		mockRepo.On("FindByID", urlID).Return(nil, expectedErr).Once()
 
-		err := svc.Stop(urlID)
+		err := svc.Stop(urlID, false)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "cannot stop crawling")
 		assert.Contains(t, err.Error(), expectedErr.Error())
@@ -475,9 +707,9 @@ func TestURLService_Stop(t *testing.T) {
 		}
 		expectedErr := errors.New("update status error")
 		mockRepo.On("FindByID", urlID).Return(testURL, nil).Once()
-		mockRepo.On("UpdateStatus", urlID, model.StatusError).Return(expectedErr).Once()
+		mockRepo.On("UpdateStatus", urlID, model.StatusStopped).Return(expectedErr).Once()
 
-		err := svc.Stop(urlID)
+		err := svc.Stop(urlID, false)
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
 		mockRepo.AssertExpectations(t)
@@ -524,11 +756,11 @@ func TestURLService_ResultsWithDetails(t *testing.T) {
 	analysisResults := []*model.AnalysisResult{} // empty slice for test
 	links := []*model.Link{}                     // empty slice for test
 
-	mockRepo.On("ResultsWithDetails", urlID).
+	mockRepo.On("ResultsWithDetails", urlID, uint(0)).
 		Return(testURL, analysisResults, links, nil).
 		Once()
 
-	urlOut, ars, ls, err := svc.ResultsWithDetails(urlID)
+	urlOut, ars, ls, err := svc.ResultsWithDetails(urlID, 0)
 	require.NoError(t, err)
 	assert.Equal(t, urlID, urlOut.ID)
 	assert.Empty(t, ars)
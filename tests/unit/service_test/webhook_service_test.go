@@ -0,0 +1,254 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+	"github.com/fuzumoe/urlinsight-backend/internal/service"
+)
+
+// MockWebhookRepo mocks repository.WebhookRepository.
+type MockWebhookRepo struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepo) Create(w *model.Webhook) error {
+	args := m.Called(w)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) FindByID(id uint) (*model.Webhook, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepo) Update(w *model.Webhook) error {
+	args := m.Called(w)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) ListByUser(userID uint, p repository.Pagination) ([]model.Webhook, error) {
+	args := m.Called(userID, p)
+	return args.Get(0).([]model.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepo) CountByUser(userID uint) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWebhookRepo) ActiveByUser(userID uint) ([]model.Webhook, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepo) CreateDelivery(d *model.WebhookDelivery) error {
+	args := m.Called(d)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) FindDelivery(id uint) (*model.WebhookDelivery, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepo) UpdateDelivery(d *model.WebhookDelivery) error {
+	args := m.Called(d)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) ListDeliveries(webhookID uint, p repository.Pagination) ([]model.WebhookDelivery, error) {
+	args := m.Called(webhookID, p)
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepo) CountDeliveries(webhookID uint) (int, error) {
+	args := m.Called(webhookID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWebhookRepo) DueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepo) ClaimDelivery(id uint, prev, until time.Time) (bool, error) {
+	args := m.Called(id, prev, until)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockRedeliverer mocks service.Redeliverer.
+type MockRedeliverer struct {
+	mock.Mock
+}
+
+func (m *MockRedeliverer) Redeliver(d *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	args := m.Called(d)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.WebhookDelivery), args.Error(1)
+}
+
+func TestWebhookService_Create(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := new(MockWebhookRepo)
+		svc := service.NewWebhookService(repo, new(MockRedeliverer))
+
+		var stored *model.Webhook
+		repo.On("Create", mock.MatchedBy(func(w *model.Webhook) bool {
+			stored = w
+			return w.UserID == 1 && w.Active && len(w.Secret) == 64
+		})).Return(nil).Once()
+
+		dto, err := svc.Create(1, &model.WebhookInputDTO{
+			URL:    "https://ci.example.com/hook",
+			Events: []string{model.EventAnalysisCompleted},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "https://ci.example.com/hook", dto.URL)
+		assert.True(t, dto.Active)
+		assert.Equal(t, stored.Secret, dto.Secret, "the secret is returned on creation")
+		assert.Empty(t, stored.ToDTO().Secret, "and never afterwards")
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Inactive", func(t *testing.T) {
+		repo := new(MockWebhookRepo)
+		svc := service.NewWebhookService(repo, new(MockRedeliverer))
+		off := false
+
+		repo.On("Create", mock.MatchedBy(func(w *model.Webhook) bool { return !w.Active })).Return(nil).Once()
+
+		dto, err := svc.Create(1, &model.WebhookInputDTO{
+			URL:    "https://ci.example.com/hook",
+			Events: []string{model.EventAnalysisFailed},
+			Active: &off,
+		})
+		require.NoError(t, err)
+		assert.False(t, dto.Active)
+	})
+
+	t.Run("Unknown Event", func(t *testing.T) {
+		repo := new(MockWebhookRepo)
+		svc := service.NewWebhookService(repo, new(MockRedeliverer))
+
+		_, err := svc.Create(1, &model.WebhookInputDTO{
+			URL:    "https://ci.example.com/hook",
+			Events: []string{"analysis.started"},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown webhook event "analysis.started"`)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestWebhookService_Ownership(t *testing.T) {
+	hook := &model.Webhook{ID: 5, UserID: 2, Secret: "s", Events: []string{model.EventAnalysisCompleted}}
+
+	t.Run("Get Other User's Webhook", func(t *testing.T) {
+		repo := new(MockWebhookRepo)
+		svc := service.NewWebhookService(repo, new(MockRedeliverer))
+
+		repo.On("FindByID", uint(5)).Return(hook, nil).Once()
+
+		_, err := svc.Get(1, 5)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Delete Other User's Webhook", func(t *testing.T) {
+		repo := new(MockWebhookRepo)
+		svc := service.NewWebhookService(repo, new(MockRedeliverer))
+
+		repo.On("FindByID", uint(5)).Return(hook, nil).Once()
+
+		err := svc.Delete(1, 5)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		repo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+}
+
+func TestWebhookService_Update(t *testing.T) {
+	repo := new(MockWebhookRepo)
+	svc := service.NewWebhookService(repo, new(MockRedeliverer))
+
+	repo.On("FindByID", uint(5)).Return(&model.Webhook{ID: 5, UserID: 1, Secret: "keep", Active: false,
+		Events: []string{model.EventAnalysisCompleted}}, nil).Once()
+	repo.On("Update", mock.MatchedBy(func(w *model.Webhook) bool {
+		return w.Secret == "keep" && !w.Active && w.TargetURL == "https://new.example.com" &&
+			len(w.Events) == 2
+	})).Return(nil).Once()
+
+	dto, err := svc.Update(1, 5, &model.WebhookInputDTO{
+		URL:    "https://new.example.com",
+		Events: []string{model.EventAnalysisFailed, model.EventAnalysisStopped},
+	})
+	require.NoError(t, err)
+	assert.False(t, dto.Active, "active is kept when omitted")
+	assert.Empty(t, dto.Secret)
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	hook := &model.Webhook{ID: 5, UserID: 1}
+
+	t.Run("Success", func(t *testing.T) {
+		repo, d := new(MockWebhookRepo), new(MockRedeliverer)
+		svc := service.NewWebhookService(repo, d)
+		orig := &model.WebhookDelivery{ID: 8, WebhookID: 5, Event: model.EventAnalysisCompleted, Status: model.DeliveryFailed}
+
+		repo.On("FindByID", uint(5)).Return(hook, nil).Once()
+		repo.On("FindDelivery", uint(8)).Return(orig, nil).Once()
+		d.On("Redeliver", orig).Return(&model.WebhookDelivery{ID: 9, WebhookID: 5, Status: model.DeliveryPending}, nil).Once()
+
+		del, err := svc.Redeliver(1, 5, 8)
+		require.NoError(t, err)
+		assert.Equal(t, uint(9), del.ID)
+		d.AssertExpectations(t)
+	})
+
+	t.Run("Delivery Of Another Webhook", func(t *testing.T) {
+		repo, d := new(MockWebhookRepo), new(MockRedeliverer)
+		svc := service.NewWebhookService(repo, d)
+
+		repo.On("FindByID", uint(5)).Return(hook, nil).Once()
+		repo.On("FindDelivery", uint(8)).Return(&model.WebhookDelivery{ID: 8, WebhookID: 6}, nil).Once()
+
+		_, err := svc.Redeliver(1, 5, 8)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		d.AssertNotCalled(t, "Redeliver", mock.Anything)
+	})
+}
+
+func TestWebhookService_Deliveries(t *testing.T) {
+	repo := new(MockWebhookRepo)
+	svc := service.NewWebhookService(repo, new(MockRedeliverer))
+	p := repository.Pagination{Page: 1, PageSize: 10}
+
+	repo.On("FindByID", uint(5)).Return(&model.Webhook{ID: 5, UserID: 1}, nil).Once()
+	repo.On("ListDeliveries", uint(5), p).Return([]model.WebhookDelivery(nil), nil).Once()
+	repo.On("CountDeliveries", uint(5)).Return(0, nil).Once()
+
+	res, err := svc.Deliveries(1, 5, p)
+	require.NoError(t, err)
+	assert.NotNil(t, res.Data, "an empty log is an empty list, not null")
+	assert.Equal(t, 0, res.Pagination.TotalPages)
+}
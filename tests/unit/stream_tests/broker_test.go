@@ -0,0 +1,54 @@
+package stream_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/stream"
+)
+
+func TestBroker(t *testing.T) {
+	t.Run("Fans Out Per URL", func(t *testing.T) {
+		b := stream.NewBroker()
+		a1, stop1 := b.Subscribe(1)
+		defer stop1()
+		a2, stop2 := b.Subscribe(1)
+		defer stop2()
+		other, stop3 := b.Subscribe(2)
+		defer stop3()
+
+		b.Report(model.ProgressEvent{URLID: 1, Type: model.ProgressStatus, Status: model.StatusRunning})
+
+		assert.Equal(t, model.StatusRunning, (<-a1).Status)
+		assert.Equal(t, model.StatusRunning, (<-a2).Status)
+		assert.Empty(t, other)
+		assert.Equal(t, 2, b.Subscribers(1))
+	})
+
+	t.Run("Unsubscribe Closes The Channel", func(t *testing.T) {
+		b := stream.NewBroker()
+		ch, stop := b.Subscribe(1)
+		stop()
+		stop()
+
+		_, open := <-ch
+		assert.False(t, open)
+		assert.Zero(t, b.Subscribers(1))
+		b.Report(model.ProgressEvent{URLID: 1})
+	})
+
+	t.Run("Slow Subscriber Does Not Block", func(t *testing.T) {
+		b := stream.NewBroker()
+		ch, stop := b.Subscribe(1)
+		defer stop()
+
+		for i := 0; i < 1000; i++ {
+			b.Report(model.ProgressEvent{URLID: 1, Type: model.ProgressUpdate, LinksChecked: i + 1})
+		}
+		require.NotEmpty(t, ch)
+		assert.Equal(t, 1, (<-ch).LinksChecked, "the oldest buffered events are kept")
+	})
+}
@@ -0,0 +1,337 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/fuzumoe/urlinsight-backend/internal/model"
+	"github.com/fuzumoe/urlinsight-backend/internal/repository"
+	"github.com/fuzumoe/urlinsight-backend/internal/webhook"
+)
+
+// memRepo is an in-memory repository.WebhookRepository.
+type memRepo struct {
+	mu         sync.Mutex
+	hooks      map[uint]*model.Webhook
+	deliveries []*model.WebhookDelivery
+}
+
+func newMemRepo(hooks ...model.Webhook) *memRepo {
+	r := &memRepo{hooks: map[uint]*model.Webhook{}}
+	for i := range hooks {
+		r.hooks[hooks[i].ID] = &hooks[i]
+	}
+	return r
+}
+
+func (r *memRepo) Create(w *model.Webhook) error { return nil }
+
+func (r *memRepo) FindByID(id uint) (*model.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	w, ok := r.hooks[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *w
+	return &cp, nil
+}
+
+func (r *memRepo) Update(w *model.Webhook) error { return nil }
+func (r *memRepo) Delete(id uint) error          { return nil }
+
+func (r *memRepo) ListByUser(userID uint, p repository.Pagination) ([]model.Webhook, error) {
+	return nil, nil
+}
+
+func (r *memRepo) CountByUser(userID uint) (int, error) { return 0, nil }
+
+func (r *memRepo) ActiveByUser(userID uint) ([]model.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []model.Webhook
+	for _, w := range r.hooks {
+		if w.UserID == userID && w.Active {
+			out = append(out, *w)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRepo) CreateDelivery(d *model.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d.ID = uint(len(r.deliveries) + 1)
+	cp := *d
+	r.deliveries = append(r.deliveries, &cp)
+	return nil
+}
+
+func (r *memRepo) FindDelivery(id uint) (*model.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id == 0 || int(id) > len(r.deliveries) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *r.deliveries[id-1]
+	return &cp, nil
+}
+
+func (r *memRepo) UpdateDelivery(d *model.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *d
+	r.deliveries[d.ID-1] = &cp
+	return nil
+}
+
+func (r *memRepo) ListDeliveries(webhookID uint, p repository.Pagination) ([]model.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (r *memRepo) CountDeliveries(webhookID uint) (int, error) { return 0, nil }
+
+func (r *memRepo) DueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []model.WebhookDelivery
+	for _, d := range r.deliveries {
+		if d.Status == model.DeliveryPending && d.NextAttemptAt != nil && !d.NextAttemptAt.After(now) {
+			out = append(out, *d)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRepo) ClaimDelivery(id uint, prev, until time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := r.deliveries[id-1]
+	if d.Status != model.DeliveryPending || d.NextAttemptAt == nil || !d.NextAttemptAt.Equal(prev) {
+		return false, nil
+	}
+	d.NextAttemptAt = &until
+	return true, nil
+}
+
+// makeDue moves every pending delivery's next attempt into the past.
+func (r *memRepo) makeDue() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	past := time.Now().Add(-time.Second)
+	for _, d := range r.deliveries {
+		if d.Status == model.DeliveryPending {
+			d.NextAttemptAt = &past
+		}
+	}
+}
+
+func (r *memRepo) delivery(id uint) model.WebhookDelivery {
+	d, _ := r.FindDelivery(id)
+	return *d
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{"a":1}' | openssl dgst -sha256 -hmac s3cret
+	assert.Equal(t,
+		"sha256=1698a50bc74d1ff1db85c4e0a5297c2ad9fdba245d5737cdb789e4cc6e098940",
+		webhook.Sign("s3cret", 1700000000, []byte(`{"a":1}`)))
+	assert.NotEqual(t, webhook.Sign("s3cret", 1, []byte("x")), webhook.Sign("s3cret", 2, []byte("x")))
+	assert.NotEqual(t, webhook.Sign("s3cret", 1, []byte("x")), webhook.Sign("other", 1, []byte("x")))
+}
+
+func TestEvents(t *testing.T) {
+	assert.Equal(t, []string{model.EventAnalysisCompleted},
+		webhook.Events(model.AnalysisEvent{Status: model.StatusDone}))
+	assert.Equal(t, []string{model.EventAnalysisCompleted, model.EventBrokenLinksDetected},
+		webhook.Events(model.AnalysisEvent{Status: model.StatusDone, BrokenLinkCount: 2}))
+	assert.Equal(t, []string{model.EventAnalysisFailed},
+		webhook.Events(model.AnalysisEvent{Status: model.StatusError}))
+	assert.Equal(t, []string{model.EventAnalysisStopped},
+		webhook.Events(model.AnalysisEvent{Status: model.StatusStopped}))
+	assert.Empty(t, webhook.Events(model.AnalysisEvent{Status: model.StatusRunning}))
+}
+
+func TestDispatcher(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+
+	newServer := func(t *testing.T, status int) (*httptest.Server, *[]received, *sync.Mutex) {
+		var mu sync.Mutex
+		var got []received
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			got = append(got, received{header: r.Header.Clone(), body: body})
+			mu.Unlock()
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(ts.Close)
+		return ts, &got, &mu
+	}
+
+	event := model.AnalysisEvent{
+		URLID:           9,
+		UserID:          1,
+		OriginalURL:     "https://example.com",
+		Status:          model.StatusDone,
+		BrokenLinkCount: 3,
+		FinishedAt:      time.Now(),
+	}
+
+	t.Run("Delivers Signed Payloads To Subscribed Webhooks", func(t *testing.T) {
+		ts, got, mu := newServer(t, http.StatusNoContent)
+		repo := newMemRepo(
+			model.Webhook{ID: 1, UserID: 1, TargetURL: ts.URL, Secret: "s3cret", Active: true,
+				Events: []string{model.EventAnalysisCompleted, model.EventBrokenLinksDetected}},
+			model.Webhook{ID: 2, UserID: 1, TargetURL: ts.URL, Secret: "other", Active: true,
+				Events: []string{model.EventAnalysisFailed}},
+			model.Webhook{ID: 3, UserID: 1, TargetURL: ts.URL, Secret: "off", Active: false,
+				Events: []string{model.EventAnalysisCompleted}},
+			model.Webhook{ID: 4, UserID: 2, TargetURL: ts.URL, Secret: "someone", Active: true,
+				Events: []string{model.EventAnalysisCompleted}},
+		)
+		d := webhook.New(repo, webhook.Options{})
+
+		d.AnalysisFinished(event)
+		require.Len(t, repo.deliveries, 2, "only webhook 1 subscribes to the raised events")
+		assert.Equal(t, 2, d.RunDue(context.Background()))
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, *got, 2)
+		events := map[string]bool{}
+		for _, r := range *got {
+			ts, err := strconv.ParseInt(r.header.Get(webhook.HeaderTimestamp), 10, 64)
+			require.NoError(t, err)
+			assert.Equal(t, webhook.Sign("s3cret", ts, r.body), r.header.Get(webhook.HeaderSignature))
+			assert.Equal(t, "application/json", r.header.Get("Content-Type"))
+			assert.NotEmpty(t, r.header.Get(webhook.HeaderDelivery))
+
+			var p model.WebhookPayload
+			require.NoError(t, json.Unmarshal(r.body, &p))
+			assert.Equal(t, r.header.Get(webhook.HeaderEvent), p.Event)
+			assert.Equal(t, uint(9), p.Data.URLID)
+			assert.Equal(t, 3, p.Data.BrokenLinkCount)
+			events[p.Event] = true
+		}
+		assert.True(t, events[model.EventAnalysisCompleted])
+		assert.True(t, events[model.EventBrokenLinksDetected])
+
+		for id := uint(1); id <= 2; id++ {
+			del := repo.delivery(id)
+			assert.Equal(t, model.DeliverySucceeded, del.Status)
+			assert.Equal(t, 1, del.Attempts)
+			assert.Equal(t, http.StatusNoContent, del.ResponseCode)
+			assert.NotNil(t, del.DeliveredAt)
+			assert.Nil(t, del.NextAttemptAt)
+		}
+	})
+
+	t.Run("Retries With Backoff Then Fails", func(t *testing.T) {
+		ts, got, mu := newServer(t, http.StatusInternalServerError)
+		repo := newMemRepo(model.Webhook{ID: 1, UserID: 1, TargetURL: ts.URL, Secret: "s", Active: true,
+			Events: []string{model.EventAnalysisCompleted}})
+		d := webhook.New(repo, webhook.Options{MaxAttempts: 3, BaseDelay: time.Minute})
+
+		d.AnalysisFinished(model.AnalysisEvent{UserID: 1, Status: model.StatusDone})
+		require.Equal(t, 1, d.RunDue(context.Background()))
+
+		del := repo.delivery(1)
+		assert.Equal(t, model.DeliveryPending, del.Status)
+		assert.Equal(t, 1, del.Attempts)
+		assert.Equal(t, http.StatusInternalServerError, del.ResponseCode)
+		assert.Equal(t, "unexpected status 500", del.Error)
+		require.NotNil(t, del.NextAttemptAt)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), *del.NextAttemptAt, 5*time.Second)
+		assert.Zero(t, d.RunDue(context.Background()), "the retry is not due yet")
+
+		repo.makeDue()
+		require.Equal(t, 1, d.RunDue(context.Background()))
+		del = repo.delivery(1)
+		assert.Equal(t, 2, del.Attempts)
+		assert.WithinDuration(t, time.Now().Add(2*time.Minute), *del.NextAttemptAt, 5*time.Second)
+
+		repo.makeDue()
+		require.Equal(t, 1, d.RunDue(context.Background()))
+		del = repo.delivery(1)
+		assert.Equal(t, model.DeliveryFailed, del.Status)
+		assert.Equal(t, 3, del.Attempts)
+		assert.Nil(t, del.NextAttemptAt)
+		assert.Nil(t, del.DeliveredAt)
+
+		mu.Lock()
+		assert.Len(t, *got, 3)
+		mu.Unlock()
+	})
+
+	t.Run("Does Not Follow Redirects", func(t *testing.T) {
+		ts := httptest.NewServer(http.RedirectHandler("http://127.0.0.1:1/elsewhere", http.StatusFound))
+		defer ts.Close()
+		repo := newMemRepo(model.Webhook{ID: 1, UserID: 1, TargetURL: ts.URL, Secret: "s", Active: true,
+			Events: []string{model.EventAnalysisStopped}})
+		d := webhook.New(repo, webhook.Options{MaxAttempts: 1})
+
+		d.AnalysisFinished(model.AnalysisEvent{UserID: 1, Status: model.StatusStopped})
+		d.RunDue(context.Background())
+		del := repo.delivery(1)
+		assert.Equal(t, model.DeliveryFailed, del.Status)
+		assert.Equal(t, http.StatusFound, del.ResponseCode)
+	})
+
+	t.Run("Disabled Webhook", func(t *testing.T) {
+		ts, got, mu := newServer(t, http.StatusOK)
+		repo := newMemRepo(model.Webhook{ID: 1, UserID: 1, TargetURL: ts.URL, Secret: "s", Active: true,
+			Events: []string{model.EventAnalysisFailed}})
+		d := webhook.New(repo, webhook.Options{})
+
+		d.AnalysisFinished(model.AnalysisEvent{UserID: 1, Status: model.StatusError, Error: "timeout"})
+		repo.hooks[1].Active = false
+		d.RunDue(context.Background())
+
+		del := repo.delivery(1)
+		assert.Equal(t, model.DeliveryFailed, del.Status)
+		assert.Equal(t, "webhook is disabled", del.Error)
+		assert.Zero(t, del.Attempts)
+		mu.Lock()
+		assert.Empty(t, *got)
+		mu.Unlock()
+	})
+
+	t.Run("Redeliver", func(t *testing.T) {
+		ts, got, mu := newServer(t, http.StatusOK)
+		repo := newMemRepo(model.Webhook{ID: 1, UserID: 1, TargetURL: ts.URL, Secret: "s", Active: true,
+			Events: []string{model.EventAnalysisCompleted}})
+		d := webhook.New(repo, webhook.Options{})
+
+		d.AnalysisFinished(model.AnalysisEvent{UserID: 1, URLID: 4, Status: model.StatusDone})
+		d.RunDue(context.Background())
+		orig := repo.delivery(1)
+
+		again, err := d.Redeliver(&orig)
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), again.ID)
+		assert.Equal(t, model.DeliveryPending, again.Status)
+		require.Equal(t, 1, d.RunDue(context.Background()))
+
+		assert.Equal(t, model.DeliverySucceeded, repo.delivery(2).Status)
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, *got, 2)
+		assert.Equal(t, (*got)[0].body, (*got)[1].body, "a redelivery sends the original payload")
+		assert.Equal(t, "2", (*got)[1].header.Get(webhook.HeaderDelivery))
+	})
+}
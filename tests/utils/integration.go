@@ -177,6 +177,9 @@ func CleanTestData(t *testing.T) {
 		&model.AnalysisResult{},   // Model for analysis_results table.
 		&model.URL{},              // Model for urls table.
 		&model.BlacklistedToken{}, // Model for blacklisted_tokens table.
+		&model.SiteSummary{},      // Model for site_summaries table.
+		&model.CrawlJob{},         // Model for crawl_jobs table.
+		&model.Schedule{},         // Model for schedules table.
 	}
 
 	// Drop each table if it exists.